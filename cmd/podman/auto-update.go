@@ -2,8 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/containers/common/pkg/auth"
 	"github.com/containers/common/pkg/completion"
@@ -17,7 +19,8 @@ import (
 
 type cliAutoUpdateOptions struct {
 	entities.AutoUpdateOptions
-	format string
+	format  string
+	history bool
 }
 
 var (
@@ -29,14 +32,34 @@ var (
   or similar units that create new containers in order to run the updated images.
   Please refer to the podman-auto-update(1) man page for details.`
 	autoUpdateCommand = &cobra.Command{
-		Annotations:       map[string]string{registry.EngineMode: registry.ABIMode},
+		Annotations: map[string]string{
+			registry.EngineMode:     registry.ABIMode,
+			registry.RunnableParent: "",
+		},
 		Use:               "auto-update [options]",
 		Short:             "Auto update containers according to their auto-update policy",
 		Long:              autoUpdateDescription,
 		RunE:              autoUpdate,
 		ValidArgsFunction: completion.AutocompleteNone,
 		Example: `podman auto-update
-  podman auto-update --authfile ~/authfile.json`,
+  podman auto-update --authfile ~/authfile.json
+  podman auto-update --history`,
+	}
+
+	autoUpdateRollbackFormat      string
+	autoUpdateRollbackDescription = `Roll back the latest update of a container or of all containers updated in a systemd unit.
+
+  The image used before the update is tagged another time and the systemd unit is restarted or the container recreated.`
+	autoUpdateRollbackCommand = &cobra.Command{
+		Annotations:       map[string]string{registry.EngineMode: registry.ABIMode},
+		Use:               "rollback [options] CONTAINER|UNIT",
+		Short:             "Roll back the latest auto update of a container or unit",
+		Long:              autoUpdateRollbackDescription,
+		RunE:              autoUpdateRollback,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.AutocompleteContainers,
+		Example: `podman auto-update rollback mycontainer
+  podman auto-update rollback container-web.service`,
 	}
 )
 
@@ -54,8 +77,27 @@ func init() {
 	flags.BoolVar(&autoUpdateOptions.DryRun, "dry-run", false, "Check for pending updates")
 	flags.BoolVar(&autoUpdateOptions.Rollback, "rollback", true, "Rollback to previous image if update fails")
 
+	healthTimeoutFlagName := "health-timeout"
+	flags.DurationVar(&autoUpdateOptions.HealthTimeout, healthTimeoutFlagName, 0, "Wait up to the specified duration for updated containers to turn healthy and roll back otherwise (0 disables waiting)")
+	_ = autoUpdateCommand.RegisterFlagCompletionFunc(healthTimeoutFlagName, completion.AutocompleteNone)
+
+	imageRetentionFlagName := "image-retention"
+	flags.DurationVar(&autoUpdateOptions.ImageRetention, imageRetentionFlagName, 7*24*time.Hour, "Protect the images used before an update from being pruned for the specified duration")
+	_ = autoUpdateCommand.RegisterFlagCompletionFunc(imageRetentionFlagName, completion.AutocompleteNone)
+
+	flags.BoolVar(&autoUpdateOptions.history, "history", false, "List past updates")
+
 	flags.StringVar(&autoUpdateOptions.format, "format", "", "Change the output format to JSON or a Go template")
 	_ = autoUpdateCommand.RegisterFlagCompletionFunc("format", common.AutocompleteFormat(&autoUpdateOutput{}))
+
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: autoUpdateRollbackCommand,
+		Parent:  autoUpdateCommand,
+	})
+
+	rollbackFlags := autoUpdateRollbackCommand.Flags()
+	rollbackFlags.StringVar(&autoUpdateRollbackFormat, "format", "", "Change the output format to JSON or a Go template")
+	_ = autoUpdateRollbackCommand.RegisterFlagCompletionFunc("format", common.AutocompleteFormat(&autoUpdateOutput{}))
 }
 
 func autoUpdate(cmd *cobra.Command, args []string) error {
@@ -64,6 +106,17 @@ func autoUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("`%s` takes no arguments", cmd.CommandPath())
 	}
 
+	if autoUpdateOptions.history {
+		if autoUpdateOptions.DryRun {
+			return errors.New("--history and --dry-run cannot be used together")
+		}
+		historyReports, err := registry.ContainerEngine().AutoUpdateHistory(registry.GetContext())
+		if err != nil {
+			return err
+		}
+		return writeHistoryTemplate(historyReports, autoUpdateOptions.format)
+	}
+
 	allReports, failures := registry.ContainerEngine().AutoUpdate(registry.GetContext(), autoUpdateOptions.AutoUpdateOptions)
 	if allReports == nil {
 		return errorhandling.JoinErrors(failures)
@@ -76,6 +129,19 @@ func autoUpdate(cmd *cobra.Command, args []string) error {
 	return errorhandling.JoinErrors(failures)
 }
 
+func autoUpdateRollback(cmd *cobra.Command, args []string) error {
+	allReports, failures := registry.ContainerEngine().AutoUpdateRollback(registry.GetContext(), args[0])
+	if allReports == nil {
+		return errorhandling.JoinErrors(failures)
+	}
+
+	if err := writeTemplate(allReports, autoUpdateRollbackFormat); err != nil {
+		failures = append(failures, err)
+	}
+
+	return errorhandling.JoinErrors(failures)
+}
+
 type autoUpdateOutput struct {
 	Unit          string
 	Container     string
@@ -84,6 +150,8 @@ type autoUpdateOutput struct {
 	Image         string
 	Policy        string
 	Updated       string
+	OldTag        string
+	NewTag        string
 }
 
 func reportsToOutput(allReports []*entities.AutoUpdateReport) []autoUpdateOutput {
@@ -97,6 +165,8 @@ func reportsToOutput(allReports []*entities.AutoUpdateReport) []autoUpdateOutput
 			Image:         r.ImageName,
 			Policy:        r.Policy,
 			Updated:       r.Updated,
+			OldTag:        r.OldTag,
+			NewTag:        r.NewTag,
 		}
 	}
 	return output
@@ -134,3 +204,70 @@ func writeTemplate(allReports []*entities.AutoUpdateReport, inputFormat string)
 	}
 	return rpt.Execute(output)
 }
+
+type autoUpdateHistoryOutput struct {
+	Time           string
+	Unit           string
+	Container      string
+	ContainerName  string
+	ContainerID    string
+	Image          string
+	OldImage       string
+	NewImage       string
+	Result         string
+	ProtectedUntil string
+}
+
+func historyReportsToOutput(historyReports []*entities.AutoUpdateHistoryReport) []autoUpdateHistoryOutput {
+	output := make([]autoUpdateHistoryOutput, len(historyReports))
+	for i, r := range historyReports {
+		output[i] = autoUpdateHistoryOutput{
+			Time:          r.Time.Format(time.RFC3339),
+			Unit:          r.SystemdUnit,
+			Container:     fmt.Sprintf("%s (%s)", r.ContainerID[:12], r.ContainerName),
+			ContainerName: r.ContainerName,
+			ContainerID:   r.ContainerID,
+			Image:         r.ImageName,
+			OldImage:      r.OldImageID[:12],
+			NewImage:      r.NewImageID[:12],
+			Result:        r.Result,
+		}
+		if !r.ProtectedUntil.IsZero() {
+			output[i].ProtectedUntil = r.ProtectedUntil.Format(time.RFC3339)
+		}
+	}
+	return output
+}
+
+func writeHistoryTemplate(historyReports []*entities.AutoUpdateHistoryReport, inputFormat string) error {
+	rpt := report.New(os.Stdout, "auto-update")
+	defer rpt.Flush()
+
+	output := historyReportsToOutput(historyReports)
+	var err error
+	switch inputFormat {
+	case "":
+		format := "{{range . }}{{.Time}}\t{{.Unit}}\t{{.Container}}\t{{.Image}}\t{{.OldImage}}\t{{.NewImage}}\t{{.Result}}\n{{end -}}"
+		rpt, err = rpt.Parse(report.OriginPodman, format)
+	case "json":
+		prettyJSON, err := json.MarshalIndent(output, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(prettyJSON))
+		return nil
+	default:
+		rpt, err = rpt.Parse(report.OriginUser, inputFormat)
+	}
+	if err != nil {
+		return err
+	}
+
+	if rpt.RenderHeaders {
+		headers := report.Headers(autoUpdateHistoryOutput{}, nil)
+		if err := rpt.Execute(headers); err != nil {
+			return err
+		}
+	}
+	return rpt.Execute(output)
+}
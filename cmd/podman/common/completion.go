@@ -18,6 +18,7 @@ import (
 	"github.com/containers/podman/v4/cmd/podman/registry"
 	"github.com/containers/podman/v4/libpod/define"
 	"github.com/containers/podman/v4/libpod/events"
+	"github.com/containers/podman/v4/libpod/logs"
 	"github.com/containers/podman/v4/pkg/domain/entities"
 	"github.com/containers/podman/v4/pkg/signal"
 	systemdDefine "github.com/containers/podman/v4/pkg/systemd/define"
@@ -549,6 +550,22 @@ func AutocompleteForKube(cmd *cobra.Command, args []string, toComplete string) (
 	return objs, cobra.ShellCompDirectiveNoFileComp
 }
 
+// AutocompleteForQuadlet - Autocomplete all Podman objects supported by quadlet generate.
+func AutocompleteForQuadlet(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !validCurrentCmdLine(cmd, args, toComplete) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	containers, _ := getContainers(cmd, toComplete, completeDefault)
+	pods, _ := getPods(cmd, toComplete, completeDefault)
+	volumes, _ := getVolumes(cmd, toComplete)
+	networks, _ := getNetworks(cmd, toComplete, completeDefault)
+	objs := containers
+	objs = append(objs, pods...)
+	objs = append(objs, volumes...)
+	objs = append(objs, networks...)
+	return objs, cobra.ShellCompDirectiveNoFileComp
+}
+
 func AutocompleteForGenerate(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return AutocompleteForKube(cmd, args, toComplete)
 }
@@ -959,10 +976,9 @@ func AutocompleteImageVolume(cmd *cobra.Command, args []string, toComplete strin
 }
 
 // AutocompleteLogDriver - Autocomplete log-driver options.
-// -> "journald", "none", "k8s-file", "passthrough"
+// -> "journald", "none", "k8s-file", "json-file", "syslog", "fluentd", "passthrough"
 func AutocompleteLogDriver(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	// don't show json-file
-	logDrivers := []string{define.JournaldLogging, define.NoLogging, define.KubernetesLogging}
+	logDrivers := []string{define.JournaldLogging, define.NoLogging, define.KubernetesLogging, define.JSONLogging, define.SyslogLogging, define.FluentdLogging}
 	if !registry.IsRemote() {
 		logDrivers = append(logDrivers, define.PassthroughLogging)
 	}
@@ -970,15 +986,31 @@ func AutocompleteLogDriver(cmd *cobra.Command, args []string, toComplete string)
 }
 
 // AutocompleteLogOpt - Autocomplete log-opt options.
-// -> "path=", "tag="
+// -> "path=", "tag=", "max-size=", "max-file=", "compress=", "mode=", "syslog-*=", "fluentd-*="
 func AutocompleteLogOpt(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	logOptions := []string{"path=", "tag=", "max-size="}
+	logOptions := []string{"path=", "tag=", "max-size=", "max-file=", "compress=", "mode="}
+	for _, driver := range []string{define.SyslogLogging, define.FluentdLogging} {
+		for _, option := range []string{"address=", "buffer-limit=", "retry-wait=", "max-retries="} {
+			logOptions = append(logOptions, driver+"-"+option)
+		}
+	}
+	logOptions = append(logOptions, define.SyslogLogging+"-facility=")
 	if strings.HasPrefix(toComplete, "path=") {
 		return nil, cobra.ShellCompDirectiveDefault
 	}
+	if strings.HasPrefix(toComplete, "mode=") {
+		return []string{"mode=remote", "mode=dual"}, cobra.ShellCompDirectiveNoFileComp
+	}
 	return logOptions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
 }
 
+// AutocompleteLogStream - Autocomplete log stream options.
+// -> "stdout", "stderr"
+func AutocompleteLogStream(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	streams := []string{logs.StdoutStream, logs.StderrStream}
+	return streams, cobra.ShellCompDirectiveNoFileComp
+}
+
 // AutocompletePullOption - Autocomplete pull options for create and run command.
 // -> "always", "missing", "never"
 func AutocompletePullOption(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -1357,29 +1389,30 @@ func getMethodNames(f reflect.Value, prefix string) []formatSuggestion {
 }
 
 // AutocompleteEventFilter - Autocomplete event filter flag options.
-// -> "container=", "event=", "image=", "pod=", "volume=", "type="
+// -> "container=", "event=", "image=", "pod=", "secret=", "volume=", "type="
 func AutocompleteEventFilter(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	event := func(_ string) ([]string, cobra.ShellCompDirective) {
 		return []string{events.Attach.String(), events.AutoUpdate.String(), events.Checkpoint.String(), events.Cleanup.String(),
 			events.Commit.String(), events.Create.String(), events.Exec.String(), events.ExecDied.String(),
 			events.Exited.String(), events.Export.String(), events.Import.String(), events.Init.String(), events.Kill.String(),
 			events.LoadFromArchive.String(), events.Mount.String(), events.NetworkConnect.String(),
-			events.NetworkDisconnect.String(), events.Pause.String(), events.Prune.String(), events.Pull.String(),
+			events.NetworkDisconnect.String(), events.OOM.String(), events.Pause.String(), events.Prune.String(), events.Pull.String(),
 			events.Push.String(), events.Refresh.String(), events.Remove.String(), events.Rename.String(),
 			events.Renumber.String(), events.Restart.String(), events.Restore.String(), events.Save.String(),
 			events.Start.String(), events.Stop.String(), events.Sync.String(), events.Tag.String(), events.Unmount.String(),
-			events.Unpause.String(), events.Untag.String(),
+			events.Unpause.String(), events.Untag.String(), events.Use.String(),
 		}, cobra.ShellCompDirectiveNoFileComp
 	}
 	eventTypes := func(_ string) ([]string, cobra.ShellCompDirective) {
 		return []string{events.Container.String(), events.Image.String(), events.Network.String(),
-			events.Pod.String(), events.System.String(), events.Volume.String(),
+			events.Pod.String(), events.Secret.String(), events.System.String(), events.Volume.String(),
 		}, cobra.ShellCompDirectiveNoFileComp
 	}
 	kv := keyValueCompletion{
 		"container=": func(s string) ([]string, cobra.ShellCompDirective) { return getContainers(cmd, s, completeDefault) },
 		"image=":     func(s string) ([]string, cobra.ShellCompDirective) { return getImages(cmd, s) },
 		"pod=":       func(s string) ([]string, cobra.ShellCompDirective) { return getPods(cmd, s, completeDefault) },
+		"secret=":    func(s string) ([]string, cobra.ShellCompDirective) { return getSecrets(cmd, s, completeDefault) },
 		"volume=":    func(s string) ([]string, cobra.ShellCompDirective) { return getVolumes(cmd, s) },
 		"event=":     event,
 		"type=":      eventTypes,
@@ -1474,7 +1507,7 @@ func AutocompleteImageSaveFormat(cmd *cobra.Command, args []string, toComplete s
 // AutocompleteWaitCondition - Autocomplete wait condition options.
 // -> "unknown", "configured", "created", "running", "stopped", "paused", "exited", "removing"
 func AutocompleteWaitCondition(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	states := []string{"unknown", "configured", "created", "running", "stopped", "paused", "exited", "removing"}
+	states := []string{"unknown", "configured", "created", "running", "stopped", "paused", "exited", "removing", define.ReadinessCheckReady}
 	return states, cobra.ShellCompDirectiveNoFileComp
 }
 
@@ -1485,10 +1518,25 @@ func AutocompleteCgroupManager(cmd *cobra.Command, args []string, toComplete str
 	return types, cobra.ShellCompDirectiveNoFileComp
 }
 
+// AutocompleteEventFormat - Autocomplete the output formats of events.
+// -> "json", "cloudevents", "docker-json" or a Go template
+func AutocompleteEventFormat(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var formats []string
+	for _, format := range []string{"json", "cloudevents", "docker-json"} {
+		if strings.HasPrefix(format, toComplete) {
+			formats = append(formats, format)
+		}
+	}
+	if len(formats) > 0 {
+		return formats, cobra.ShellCompDirectiveNoFileComp
+	}
+	return AutocompleteFormat(&events.Event{})(cmd, args, toComplete)
+}
+
 // AutocompleteEventBackend - Autocomplete event backend options.
-// -> "file", "journald", "none"
+// -> "file", "journald", "sqlite", "none"
 func AutocompleteEventBackend(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	types := []string{events.LogFile.String(), events.Journald.String(), events.Null.String()}
+	types := []string{events.LogFile.String(), events.Journald.String(), events.SQLite.String(), events.Null.String()}
 	return types, cobra.ShellCompDirectiveNoFileComp
 }
 
@@ -1666,3 +1714,9 @@ func AutocompleteSSH(cmd *cobra.Command, args []string, toComplete string) ([]st
 func AutocompleteHealthOnFailure(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return define.SupportedHealthCheckOnFailureActions, cobra.ShellCompDirectiveNoFileComp
 }
+
+// AutocompleteHealthLogDestination returns the destinations of the
+// healthcheck log.
+func AutocompleteHealthLogDestination(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return define.SupportedHealthCheckLogDestinations, cobra.ShellCompDirectiveNoFileComp
+}
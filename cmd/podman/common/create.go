@@ -216,6 +216,78 @@ func DefineCreateFlags(cmd *cobra.Command, cf *entities.ContainerCreateOptions,
 		)
 		_ = cmd.RegisterFlagCompletionFunc(healthOnFailureFlagName, AutocompleteHealthOnFailure)
 
+		healthHTTPFlagName := "health-http"
+		createFlags.StringVar(
+			&cf.HealthHTTP,
+			healthHTTPFlagName, "",
+			"set a URL to be probed via HTTP GET as healthcheck",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(healthHTTPFlagName, completion.AutocompleteNone)
+
+		healthHTTPHeaderFlagName := "health-http-header"
+		createFlags.StringArrayVar(
+			&cf.HealthHTTPHeader,
+			healthHTTPHeaderFlagName, []string{},
+			"add a header (NAME:VALUE) to the requests of the HTTP health probe",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(healthHTTPHeaderFlagName, completion.AutocompleteNone)
+
+		healthHTTPStatusFlagName := "health-http-status"
+		createFlags.IntVar(
+			&cf.HealthHTTPStatus,
+			healthHTTPStatusFlagName, 0,
+			"the expected status code of the HTTP health probe (default any status between 200 and 399)",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(healthHTTPStatusFlagName, completion.AutocompleteNone)
+
+		healthTCPFlagName := "health-tcp"
+		createFlags.StringVar(
+			&cf.HealthTCP,
+			healthTCPFlagName, "",
+			"set an address ([HOST:]PORT) to be probed via TCP as healthcheck",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(healthTCPFlagName, completion.AutocompleteNone)
+
+		healthGRPCFlagName := "health-grpc"
+		createFlags.StringVar(
+			&cf.HealthGRPC,
+			healthGRPCFlagName, "",
+			"set an address ([HOST:]PORT) to be probed via the gRPC health checking protocol as healthcheck",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(healthGRPCFlagName, completion.AutocompleteNone)
+
+		healthGRPCServiceFlagName := "health-grpc-service"
+		createFlags.StringVar(
+			&cf.HealthGRPCService,
+			healthGRPCServiceFlagName, "",
+			"the service to check via the gRPC health probe",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(healthGRPCServiceFlagName, completion.AutocompleteNone)
+
+		healthLogDestinationFlagName := "health-log-destination"
+		createFlags.StringVar(
+			&cf.HealthLogDest,
+			healthLogDestinationFlagName, define.HealthCheckLogDestinationFile,
+			"destination of the healthcheck log (file, journald or events)",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(healthLogDestinationFlagName, AutocompleteHealthLogDestination)
+
+		healthMaxLogCountFlagName := "health-max-log-count"
+		createFlags.UintVar(
+			&cf.HealthMaxLogCount,
+			healthMaxLogCountFlagName, define.DefaultHealthMaxLogCount,
+			"maximum number of entries in the healthcheck log file (0 means unlimited)",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(healthMaxLogCountFlagName, completion.AutocompleteNone)
+
+		healthMaxLogSizeFlagName := "health-max-log-size"
+		createFlags.UintVar(
+			&cf.HealthMaxLogSize,
+			healthMaxLogSizeFlagName, define.DefaultHealthMaxLogSize,
+			"maximum number of bytes of the output of a healthcheck kept in the healthcheck log (0 means unlimited)",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(healthMaxLogSizeFlagName, completion.AutocompleteNone)
+
 		createFlags.BoolVar(
 			&cf.HTTPProxy,
 			"http-proxy", podmanConfig.ContainersConfDefaultsRO.Containers.HTTPProxy,
@@ -453,6 +525,54 @@ func DefineCreateFlags(cmd *cobra.Command, cf *entities.ContainerCreateOptions,
 		)
 		_ = cmd.RegisterFlagCompletionFunc(startupHCTimeoutFlagName, completion.AutocompleteNone)
 
+		readinessCmdFlagName := "readiness-cmd"
+		createFlags.StringVar(
+			&cf.ReadyCmd,
+			readinessCmdFlagName, "",
+			"Set a readiness check command for the container",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(readinessCmdFlagName, completion.AutocompleteNone)
+
+		readinessIntervalFlagName := "readiness-interval"
+		createFlags.StringVar(
+			&cf.ReadyInterval,
+			readinessIntervalFlagName, define.DefaultHealthCheckInterval,
+			"Set an interval for the readiness check (a value of disable results in no automatic timer setup)",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(readinessIntervalFlagName, completion.AutocompleteNone)
+
+		readinessRetriesFlagName := "readiness-retries"
+		createFlags.UintVar(
+			&cf.ReadyRetries,
+			readinessRetriesFlagName, define.DefaultHealthCheckRetries,
+			"The number of consecutive failures before a ready container is marked as not ready",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(readinessRetriesFlagName, completion.AutocompleteNone)
+
+		readinessStartPeriodFlagName := "readiness-start-period"
+		createFlags.StringVar(
+			&cf.ReadyStartPeriod,
+			readinessStartPeriodFlagName, define.DefaultHealthCheckStartPeriod,
+			"The initialization time needed for a container to become ready",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(readinessStartPeriodFlagName, completion.AutocompleteNone)
+
+		readinessSuccessesFlagName := "readiness-success"
+		createFlags.UintVar(
+			&cf.ReadySuccesses,
+			readinessSuccessesFlagName, 0,
+			"Set the number of consecutive successes before the container is marked as ready (0 indicates any success marks it as ready)",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(readinessSuccessesFlagName, completion.AutocompleteNone)
+
+		readinessTimeoutFlagName := "readiness-timeout"
+		createFlags.StringVar(
+			&cf.ReadyTimeout,
+			readinessTimeoutFlagName, define.DefaultHealthCheckTimeout,
+			"The maximum time allowed to complete the readiness check before it is considered failed",
+		)
+		_ = cmd.RegisterFlagCompletionFunc(readinessTimeoutFlagName, completion.AutocompleteNone)
+
 		stopSignalFlagName := "stop-signal"
 		createFlags.StringVar(
 			&cf.StopSignal,
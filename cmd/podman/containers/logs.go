@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 
 	"github.com/containers/common/pkg/completion"
 	"github.com/containers/podman/v4/cmd/podman/common"
 	"github.com/containers/podman/v4/cmd/podman/registry"
 	"github.com/containers/podman/v4/cmd/podman/utils"
 	"github.com/containers/podman/v4/cmd/podman/validate"
+	libpodLogs "github.com/containers/podman/v4/libpod/logs"
 	"github.com/containers/podman/v4/pkg/domain/entities"
 	"github.com/containers/podman/v4/pkg/util"
 	"github.com/spf13/cobra"
@@ -54,6 +56,8 @@ var (
   podman logs --names ctrID1 ctrID2
   podman logs --tail 2 mywebserver
   podman logs --follow=true --since 10m ctrID
+  podman logs --stream stderr --grep 'error|warn' ctrID
+  podman logs --format json ctrID
   podman logs mywebserver mydbserver`,
 	}
 
@@ -118,6 +122,18 @@ func logsFlags(cmd *cobra.Command) {
 	flags.BoolVarP(&logsOptions.Colors, "color", "", false, "Output the containers with different colors in the log.")
 	flags.BoolVarP(&logsOptions.Names, "names", "n", false, "Output the container name in the log")
 
+	grepFlagName := "grep"
+	flags.StringVar(&logsOptions.Grep, grepFlagName, "", "Only output the lines matching the regular expression")
+	_ = cmd.RegisterFlagCompletionFunc(grepFlagName, completion.AutocompleteNone)
+
+	streamFlagName := "stream"
+	flags.StringVar(&logsOptions.Stream, streamFlagName, "", "Only output the lines of the stream (stdout or stderr)")
+	_ = cmd.RegisterFlagCompletionFunc(streamFlagName, common.AutocompleteLogStream)
+
+	formatFlagName := "format"
+	flags.StringVar(&logsOptions.Format, formatFlagName, "", "Output format of the log lines (json)")
+	_ = cmd.RegisterFlagCompletionFunc(formatFlagName, common.AutocompleteFormat(nil))
+
 	flags.SetInterspersed(false)
 	_ = flags.MarkHidden("details")
 }
@@ -140,6 +156,17 @@ func logs(_ *cobra.Command, args []string) error {
 		}
 		logsOptions.Until = until
 	}
+	if err := libpodLogs.ValidateStream(logsOptions.Stream); err != nil {
+		return err
+	}
+	if logsOptions.Grep != "" {
+		if _, err := regexp.Compile(logsOptions.Grep); err != nil {
+			return fmt.Errorf("invalid grep pattern %q: %w", logsOptions.Grep, err)
+		}
+	}
+	if logsOptions.Format != "" && logsOptions.Format != "json" {
+		return fmt.Errorf("unsupported log format %q: only json is supported", logsOptions.Format)
+	}
 	logsOptions.StdoutWriter = os.Stdout
 	logsOptions.StderrWriter = os.Stderr
 	return registry.ContainerEngine().ContainerLogs(registry.GetContext(), args, logsOptions.ContainerLogsOptions)
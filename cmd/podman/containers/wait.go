@@ -96,6 +96,10 @@ func wait(cmd *cobra.Command, args []string) error {
 	}
 
 	for _, condition := range waitConditions {
+		if condition == define.ReadinessCheckReady {
+			waitOptions.Ready = true
+			continue
+		}
 		cond, err := define.StringToContainerStatus(condition)
 		if err != nil {
 			return err
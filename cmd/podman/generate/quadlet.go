@@ -0,0 +1,102 @@
+package generate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/containers/common/pkg/report"
+	"github.com/containers/podman/v4/cmd/podman/common"
+	"github.com/containers/podman/v4/cmd/podman/registry"
+	"github.com/containers/podman/v4/pkg/domain/entities"
+	"github.com/spf13/cobra"
+)
+
+var (
+	quadletFiles       bool
+	quadletFormat      string
+	quadletOptions     = entities.GenerateQuadletOptions{}
+	quadletDescription = `Generate Quadlet units for containers, pods, volumes and networks.
+
+  Pods are generated along with their containers. Settings which have no Quadlet key are passed via PodmanArgs=, settings which cannot be expressed at all are reported on stderr.`
+
+	quadletCmd = &cobra.Command{
+		Use:               "quadlet [options] {CONTAINER|POD|VOLUME|NETWORK...}",
+		Short:             "Generate Quadlet units",
+		Long:              quadletDescription,
+		RunE:              generateQuadlet,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: common.AutocompleteForQuadlet,
+		Annotations:       map[string]string{registry.EngineMode: registry.ABIMode},
+		Example: `podman generate quadlet CTR
+  podman generate quadlet --files POD VOLUME NETWORK`,
+	}
+)
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: quadletCmd,
+		Parent:  GenerateCmd,
+	})
+	flags := quadletCmd.Flags()
+	flags.BoolVarP(&quadletFiles, "files", "f", false, "Write the units to files in the current directory instead of printing them")
+	flags.BoolVar(&quadletOptions.NoHeader, "no-header", false, "Skip header generation")
+
+	formatFlagName := "format"
+	flags.StringVar(&quadletFormat, formatFlagName, "", "Print the created units in specified format (json)")
+	_ = quadletCmd.RegisterFlagCompletionFunc(formatFlagName, common.AutocompleteFormat(nil))
+}
+
+func generateQuadlet(cmd *cobra.Command, args []string) error {
+	reports, err := registry.ContainerEngine().GenerateQuadlet(registry.GetContext(), args, quadletOptions)
+	if err != nil {
+		return err
+	}
+
+	for _, unsupported := range reports.Unsupported {
+		fmt.Fprintln(os.Stderr, unsupported)
+	}
+
+	if quadletFiles {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current working directory: %w", err)
+		}
+		for name, content := range reports.Units {
+			path := filepath.Join(cwd, name)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				return err
+			}
+
+			// add newline if default format is given
+			if quadletFormat == "" {
+				path += "\n"
+			}
+			// modify in place so we can print the
+			// paths when --files is set
+			reports.Units[name] = path
+		}
+	}
+
+	switch {
+	case report.IsJSON(quadletFormat):
+		return printJSON(reports.Units)
+	case quadletFormat == "":
+		// Print the units in a stable order, separated by an empty line
+		names := make([]string, 0, len(reports.Units))
+		for name := range reports.Units {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			if i > 0 && !quadletFiles {
+				fmt.Println()
+			}
+			fmt.Print(reports.Units[name])
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown --format argument: %s", quadletFormat)
+	}
+}
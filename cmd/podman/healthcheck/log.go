@@ -0,0 +1,83 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/containers/common/pkg/completion"
+	"github.com/containers/common/pkg/report"
+	"github.com/containers/podman/v4/cmd/podman/common"
+	"github.com/containers/podman/v4/cmd/podman/registry"
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/containers/podman/v4/pkg/domain/entities"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logCmd = &cobra.Command{
+		Use:   "log [options] CONTAINER",
+		Short: "Show the healthcheck log of a container",
+		Long:  "Show the results of the health checks of a container recorded at the destination of its healthcheck log",
+		Example: `podman healthcheck log mywebapp
+  podman healthcheck log --since 10m --format json mywebapp`,
+		RunE:              healthCheckLog,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: common.AutocompleteContainers,
+	}
+	logOptions entities.HealthCheckLogOptions
+	logFormat  string
+)
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: logCmd,
+		Parent:  healthCmd,
+	})
+	flags := logCmd.Flags()
+
+	sinceFlagName := "since"
+	flags.StringVar(&logOptions.Since, sinceFlagName, "", "Show the results of health checks started since TIMESTAMP")
+	_ = logCmd.RegisterFlagCompletionFunc(sinceFlagName, completion.AutocompleteNone)
+
+	formatFlagName := "format"
+	flags.StringVar(&logFormat, formatFlagName, "", "Change the output format to JSON or a Go template")
+	_ = logCmd.RegisterFlagCompletionFunc(formatFlagName, common.AutocompleteFormat(&define.HealthCheckLog{}))
+}
+
+func healthCheckLog(cmd *cobra.Command, args []string) error {
+	healthLog, err := registry.ContainerEngine().HealthCheckLog(context.Background(), args[0], logOptions)
+	if err != nil {
+		return err
+	}
+
+	if logFormat == "json" {
+		prettyJSON, err := json.MarshalIndent(healthLog, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(prettyJSON))
+		return nil
+	}
+
+	rpt := report.New(os.Stdout, cmd.Name())
+	defer rpt.Flush()
+
+	if cmd.Flags().Changed("format") {
+		rpt, err = rpt.Parse(report.OriginUser, logFormat)
+	} else {
+		rpt, err = rpt.Parse(report.OriginPodman, "{{range . }}{{.Start}}\t{{.End}}\t{{.ExitCode}}\t{{.Output}}\n{{end -}}")
+	}
+	if err != nil {
+		return err
+	}
+
+	if rpt.RenderHeaders {
+		headers := report.Headers(define.HealthCheckLog{}, nil)
+		if err := rpt.Execute(headers); err != nil {
+			return err
+		}
+	}
+	return rpt.Execute(healthLog)
+}
@@ -0,0 +1,164 @@
+package quadlet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/common/pkg/completion"
+	"github.com/containers/podman/v4/cmd/podman/registry"
+	"github.com/containers/podman/v4/pkg/systemd/parser"
+	"github.com/containers/podman/v4/pkg/systemd/quadlet"
+	"github.com/containers/storage/pkg/fileutils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	installDescription = `Install Quadlet units into the Quadlet unit directory of the user, or the system one when run as root.
+
+  Directories are installed with all the units and the drop-in directories (*.d) in them.`
+	installCmd = &cobra.Command{
+		Use:               "install [options] PATH [PATH...]",
+		Short:             "Install one or more Quadlet units",
+		Long:              installDescription,
+		RunE:              install,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completion.AutocompleteDefault,
+		Annotations:       map[string]string{registry.EngineMode: registry.ABIMode},
+		Example: `podman quadlet install ./myapp.container
+  podman quadlet install --replace ./myapp/`,
+	}
+)
+
+var (
+	installOptions struct {
+		replace       bool
+		reloadSystemd bool
+	}
+)
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: installCmd,
+		Parent:  quadletCmd,
+	})
+	flags := installCmd.Flags()
+	flags.BoolVar(&installOptions.replace, "replace", false, "Replace existing units and drop-ins with the same name")
+	flags.BoolVar(&installOptions.reloadSystemd, "reload-systemd", true, "Reload systemd after installing the units")
+}
+
+func install(cmd *cobra.Command, args []string) error {
+	destDir, err := installDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return err
+	}
+
+	installed := []string{}
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			if err := installUnit(arg, destDir); err != nil {
+				return err
+			}
+			installed = append(installed, filepath.Base(arg))
+			continue
+		}
+
+		entries, err := os.ReadDir(arg)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, entry := range entries {
+			name := entry.Name()
+			switch {
+			case entry.IsDir() && strings.HasSuffix(name, ".d"):
+				if err := installDropinDir(filepath.Join(arg, name), destDir); err != nil {
+					return err
+				}
+			case !entry.IsDir() && quadlet.IsExtSupported(name):
+				if err := installUnit(filepath.Join(arg, name), destDir); err != nil {
+					return err
+				}
+				installed = append(installed, name)
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("no Quadlet units found in %q", arg)
+		}
+	}
+
+	for _, name := range installed {
+		fmt.Println(name)
+	}
+
+	if installOptions.reloadSystemd {
+		return reloadSystemd()
+	}
+	return nil
+}
+
+// installUnit copies the unit to the directory, after making sure it parses
+func installUnit(path string, destDir string) error {
+	if !quadlet.IsExtSupported(path) {
+		return fmt.Errorf("%q is not a Quadlet unit", path)
+	}
+	if _, err := parser.ParseUnitFile(path); err != nil {
+		return fmt.Errorf("error loading %q: %w", path, err)
+	}
+	return installFile(path, filepath.Join(destDir, filepath.Base(path)))
+}
+
+// installDropinDir copies the *.conf files of the drop-in directory into
+// the directory with the same name in destDir
+func installDropinDir(path string, destDir string) error {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	dropinDir := filepath.Join(destDir, filepath.Base(path))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".conf" {
+			continue
+		}
+		if err := os.MkdirAll(dropinDir, 0o755); err != nil {
+			return err
+		}
+
+		dropinPath := filepath.Join(path, name)
+		if _, err := parser.ParseUnitFile(dropinPath); err != nil {
+			return fmt.Errorf("error loading %q: %w", dropinPath, err)
+		}
+		if err := installFile(dropinPath, filepath.Join(dropinDir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func installFile(src string, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		if !installOptions.replace {
+			return fmt.Errorf("%q already exists, use --replace to replace it", dest)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if _, err := fileutils.CopyFile(src, dest); err != nil {
+		return fmt.Errorf("copying %q to %q: %w", src, dest, err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package quadlet
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/containers/common/pkg/completion"
+	"github.com/containers/common/pkg/report"
+	"github.com/containers/podman/v4/cmd/podman/common"
+	"github.com/containers/podman/v4/cmd/podman/registry"
+	"github.com/containers/podman/v4/cmd/podman/validate"
+	"github.com/containers/podman/v4/pkg/systemd"
+	"github.com/containers/podman/v4/pkg/systemd/quadlet"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Pull in configured json library
+	json = registry.JSONLibrary()
+
+	listCmd = &cobra.Command{
+		Use:               "list [options]",
+		Aliases:           []string{"ls"},
+		Short:             "List Quadlet units",
+		Long:              "List the installed Quadlet units with the services generated from them and their state",
+		RunE:              list,
+		Args:              validate.NoArgs,
+		ValidArgsFunction: completion.AutocompleteNone,
+		Annotations:       map[string]string{registry.EngineMode: registry.ABIMode},
+		Example: `podman quadlet list
+  podman quadlet list --format "{{.Name}} {{.Status}}"`,
+	}
+)
+
+var (
+	listOptions struct {
+		format    string
+		noHeading bool
+		quiet     bool
+	}
+)
+
+// ListReport describes an installed Quadlet unit
+type ListReport struct {
+	// Name of the Quadlet unit
+	Name string
+	// Name of the generated service, empty if the unit is invalid
+	ServiceName string
+	// Active state of the service, or invalid if the unit is invalid
+	Status string
+	// Path of the Quadlet unit
+	Path string
+}
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: listCmd,
+		Parent:  quadletCmd,
+	})
+	flags := listCmd.Flags()
+
+	formatFlagName := "format"
+	flags.StringVar(&listOptions.format, formatFlagName, "{{range .}}{{.Name}}\t{{.ServiceName}}\t{{.Status}}\t{{.Path}}\n{{end -}}", "Pretty-print units to JSON or using a Go template")
+	_ = listCmd.RegisterFlagCompletionFunc(formatFlagName, common.AutocompleteFormat(&ListReport{}))
+
+	flags.BoolVarP(&listOptions.noHeading, "noheading", "n", false, "Do not print headers")
+	flags.BoolVarP(&listOptions.quiet, "quiet", "q", false, "Print unit names only")
+}
+
+func list(cmd *cobra.Command, args []string) error {
+	units, err := loadUnits()
+	if err != nil {
+		logrus.Warn(err)
+	}
+
+	responses := make([]*ListReport, 0, len(units))
+	serviceNames := make([]string, 0, len(units))
+	for _, converted := range quadlet.ConvertUnits(units, isUser()) {
+		r := &ListReport{
+			Name: converted.Name,
+			Path: converted.Unit.Path,
+		}
+		switch {
+		case converted.Err != nil:
+			r.Status = "invalid"
+		case strings.HasSuffix(converted.Service.Filename, "@.service"):
+			// Only instances of templates have a state
+			r.ServiceName = converted.Service.Filename
+			r.Status = "template"
+		default:
+			r.ServiceName = converted.Service.Filename
+			serviceNames = append(serviceNames, r.ServiceName)
+		}
+		responses = append(responses, r)
+	}
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].Name < responses[j].Name
+	})
+
+	states, err := serviceStates(serviceNames)
+	if err != nil {
+		logrus.Warnf("Failed to get the state of the services: %v", err)
+	}
+	for _, r := range responses {
+		if len(r.Status) > 0 {
+			continue
+		}
+		if state, ok := states[r.ServiceName]; ok {
+			r.Status = state
+		} else {
+			r.Status = "unknown"
+		}
+	}
+
+	switch {
+	case listOptions.quiet:
+		for _, r := range responses {
+			fmt.Println(r.Name)
+		}
+		return nil
+	case report.IsJSON(listOptions.format):
+		prettyJSON, err := json.MarshalIndent(responses, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(prettyJSON))
+		return nil
+	}
+
+	headers := report.Headers(ListReport{}, map[string]string{
+		"ServiceName": "SERVICE NAME",
+	})
+
+	rpt := report.New(os.Stdout, cmd.Name())
+	defer rpt.Flush()
+
+	switch {
+	case cmd.Flag("format").Changed:
+		rpt, err = rpt.Parse(report.OriginUser, listOptions.format)
+	default:
+		rpt, err = rpt.Parse(report.OriginPodman, listOptions.format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if rpt.RenderHeaders && !listOptions.noHeading {
+		if err := rpt.Execute(headers); err != nil {
+			return fmt.Errorf("failed to write report column headers: %w", err)
+		}
+	}
+	return rpt.Execute(responses)
+}
+
+// serviceStates returns the active state of the services, keyed by their name
+func serviceStates(serviceNames []string) (map[string]string, error) {
+	states := make(map[string]string, len(serviceNames))
+	if len(serviceNames) == 0 {
+		return states, nil
+	}
+
+	conn, err := systemd.ConnectToDBUS()
+	if err != nil {
+		return states, err
+	}
+	defer conn.Close()
+
+	statuses, err := conn.ListUnitsByNamesContext(context.Background(), serviceNames)
+	if err != nil {
+		return states, err
+	}
+	for _, status := range statuses {
+		states[status.Name] = status.ActiveState
+	}
+	return states, nil
+}
@@ -0,0 +1,57 @@
+package quadlet
+
+import (
+	"fmt"
+
+	"github.com/containers/podman/v4/cmd/podman/registry"
+	"github.com/containers/podman/v4/pkg/systemd/quadlet"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	printCmd = &cobra.Command{
+		Use:               "print QUADLET",
+		Short:             "Print the service generated from a Quadlet unit",
+		Long:              "Print the systemd service the Quadlet generator creates from an installed Quadlet unit",
+		RunE:              printUnit,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: autocompleteQuadlets,
+		Annotations:       map[string]string{registry.EngineMode: registry.ABIMode},
+		Example:           `podman quadlet print myapp.container`,
+	}
+)
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: printCmd,
+		Parent:  quadletCmd,
+	})
+}
+
+func printUnit(cmd *cobra.Command, args []string) error {
+	units, err := loadUnits()
+	if err != nil {
+		logrus.Warn(err)
+	}
+	if _, ok := units[args[0]]; !ok {
+		return fmt.Errorf("no such Quadlet unit %q", args[0])
+	}
+
+	// All units are converted, as the unit may refer to others
+	for _, converted := range quadlet.ConvertUnits(units, isUser()) {
+		if converted.Name != args[0] {
+			continue
+		}
+		if converted.Err != nil {
+			return fmt.Errorf("converting %q: %w", converted.Name, converted.Err)
+		}
+
+		data, err := converted.Service.ToString()
+		if err != nil {
+			return err
+		}
+		fmt.Print(data)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package quadlet
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/containers/podman/v4/cmd/podman/registry"
+	"github.com/containers/podman/v4/cmd/podman/validate"
+	"github.com/containers/podman/v4/pkg/rootless"
+	"github.com/containers/podman/v4/pkg/systemd"
+	"github.com/containers/podman/v4/pkg/systemd/parser"
+	"github.com/containers/podman/v4/pkg/systemd/quadlet"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Command: podman _quadlet_
+	quadletCmd = &cobra.Command{
+		Use:   "quadlet",
+		Short: "Manage Quadlet units",
+		Long:  "Install, list, print, remove and validate the Quadlet units used to run containers with systemd",
+		// The Quadlet commands only work on unit files and systemd, so
+		// there is no need to set up the engines
+		PersistentPreRunE:  validate.NoOp,
+		PersistentPostRunE: validate.NoOp,
+		RunE:               validate.SubCommandExists,
+	}
+)
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: quadletCmd,
+	})
+}
+
+// isUser returns whether the user units are managed rather than the system ones
+func isUser() bool {
+	return rootless.IsRootless()
+}
+
+// installDir returns the directory units are installed to, i.e. the
+// directory taking precedence over all other unit directories.
+func installDir() (string, error) {
+	dirs := quadlet.GetUnitDirs(isUser())
+	if len(dirs) == 0 {
+		return "", fmt.Errorf("no Quadlet unit directory found")
+	}
+	return dirs[0], nil
+}
+
+// loadUnits loads the Quadlet units and their drop-ins from the unit
+// directories, the same way the generator does. Units that failed to
+// load are reported in the error, the others are returned regardless.
+func loadUnits() (map[string]*parser.UnitFile, error) {
+	var errs []string
+
+	sourcePaths := quadlet.GetUnitDirs(isUser())
+	units := make(map[string]*parser.UnitFile)
+	for _, d := range sourcePaths {
+		if err := quadlet.LoadUnitsFromDir(d, units); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	for name, unit := range units {
+		if err := quadlet.LoadUnitDropins(unit, sourcePaths); err != nil {
+			errs = append(errs, fmt.Sprintf("error loading drop-ins for %q: %s", name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return units, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return units, nil
+}
+
+// reloadSystemd makes systemd rerun the generators, picking up the changed units
+func reloadSystemd() error {
+	conn, err := systemd.ConnectToDBUS()
+	if err != nil {
+		return fmt.Errorf("connecting to systemd: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.ReloadContext(context.Background()); err != nil {
+		return fmt.Errorf("reloading systemd: %w", err)
+	}
+	return nil
+}
+
+// autocompleteQuadlets completes the names of the installed Quadlet units
+func autocompleteQuadlets(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	units, _ := loadUnits()
+
+	names := make([]string, 0, len(units))
+	for name := range units {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
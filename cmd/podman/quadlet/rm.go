@@ -0,0 +1,84 @@
+package quadlet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/podman/v4/cmd/podman/registry"
+	"github.com/containers/podman/v4/cmd/podman/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rmDescription = `Remove installed Quadlet units, along with their unit specific drop-in directory.
+
+  The services generated from the units are not stopped, but they are gone after systemd is reloaded.`
+	rmCmd = &cobra.Command{
+		Use:               "rm [options] QUADLET [QUADLET...]",
+		Aliases:           []string{"remove"},
+		Short:             "Remove one or more Quadlet units",
+		Long:              rmDescription,
+		RunE:              rm,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: autocompleteQuadlets,
+		Annotations:       map[string]string{registry.EngineMode: registry.ABIMode},
+		Example:           `podman quadlet rm myapp.container myapp.volume`,
+	}
+)
+
+var (
+	rmOptions struct {
+		ignore        bool
+		reloadSystemd bool
+	}
+)
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: rmCmd,
+		Parent:  quadletCmd,
+	})
+	flags := rmCmd.Flags()
+	flags.BoolVarP(&rmOptions.ignore, "ignore", "i", false, "Ignore errors when a specified unit is missing")
+	flags.BoolVar(&rmOptions.reloadSystemd, "reload-systemd", true, "Reload systemd after removing the units")
+}
+
+func rm(cmd *cobra.Command, args []string) error {
+	var errs utils.OutputErrors
+
+	units, err := loadUnits()
+	if err != nil {
+		logrus.Warn(err)
+	}
+
+	removed := false
+	for _, name := range args {
+		unit, ok := units[name]
+		if !ok {
+			if !rmOptions.ignore {
+				errs = append(errs, fmt.Errorf("no such Quadlet unit %q", name))
+			}
+			continue
+		}
+
+		if err := os.Remove(unit.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(filepath.Dir(unit.Path), name+".d")); err != nil {
+			errs = append(errs, err)
+		}
+		fmt.Println(name)
+		removed = true
+	}
+
+	if removed && rmOptions.reloadSystemd {
+		if err := reloadSystemd(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs.PrintErrors()
+}
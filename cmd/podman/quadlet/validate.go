@@ -0,0 +1,74 @@
+package quadlet
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/containers/common/pkg/completion"
+	"github.com/containers/podman/v4/cmd/podman/registry"
+	"github.com/containers/podman/v4/cmd/podman/utils"
+	"github.com/containers/podman/v4/pkg/systemd/parser"
+	"github.com/containers/podman/v4/pkg/systemd/quadlet"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateDescription = `Check Quadlet units for errors the generator would report, like unsupported keys or invalid values.
+
+  Without arguments all installed units are checked. Units given as paths are checked against the installed units, so they can refer to them.`
+	validateCmd = &cobra.Command{
+		Use:               "validate [PATH...]",
+		Short:             "Validate Quadlet units",
+		Long:              validateDescription,
+		RunE:              validateUnits,
+		ValidArgsFunction: completion.AutocompleteDefault,
+		Annotations:       map[string]string{registry.EngineMode: registry.ABIMode},
+		Example: `podman quadlet validate
+  podman quadlet validate ./myapp.container`,
+	}
+)
+
+func init() {
+	registry.Commands = append(registry.Commands, registry.CliCommand{
+		Command: validateCmd,
+		Parent:  quadletCmd,
+	})
+}
+
+func validateUnits(cmd *cobra.Command, args []string) error {
+	var errs utils.OutputErrors
+
+	units, err := loadUnits()
+	if err != nil && len(args) == 0 {
+		errs = append(errs, err)
+	}
+
+	// Names of the units to report errors for, all of them by default
+	selected := make(map[string]bool)
+	for _, arg := range args {
+		if !quadlet.IsExtSupported(arg) {
+			errs = append(errs, fmt.Errorf("%q is not a Quadlet unit", arg))
+			continue
+		}
+		unit, err := parser.ParseUnitFile(arg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error loading %q: %w", arg, err))
+			continue
+		}
+		if err := quadlet.LoadUnitDropins(unit, []string{filepath.Dir(arg)}); err != nil {
+			errs = append(errs, fmt.Errorf("error loading drop-ins for %q: %w", arg, err))
+		}
+		units[unit.Filename] = unit
+		selected[unit.Filename] = true
+	}
+
+	for _, converted := range quadlet.ConvertUnits(units, isUser()) {
+		if len(args) > 0 && !selected[converted.Name] {
+			continue
+		}
+		if converted.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", converted.Name, converted.Err))
+		}
+	}
+	return errs.PrintErrors()
+}
@@ -25,6 +25,9 @@ const (
 
 	// EngineMode used as cobra.Annotation when command supports a limited number of Engines
 	EngineMode = "EngineMode"
+
+	// RunnableParent used as cobra.Annotation when a command with subcommands can be run on its own and hence requires the full setup
+	RunnableParent = "RunnableParent"
 )
 
 var (
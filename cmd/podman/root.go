@@ -147,7 +147,8 @@ func persistentPreRunE(cmd *cobra.Command, args []string) error {
 
 	// Help, completion and commands with subcommands are special cases, no need for more setup
 	// Completion cmd is used to generate the shell scripts
-	if cmd.Name() == "help" || cmd.Name() == "completion" || cmd.HasSubCommands() {
+	_, runnableParent := cmd.Annotations[registry.RunnableParent]
+	if cmd.Name() == "help" || cmd.Name() == "completion" || (cmd.HasSubCommands() && !runnableParent) {
 		requireCleanup = false
 		return nil
 	}
@@ -478,7 +479,7 @@ func rootFlags(cmd *cobra.Command, podmanConfig *entities.PodmanConfig) {
 		pFlags.StringVar(&podmanConfig.ContainersConf.Containers.DefaultMountsFile, "default-mounts-file", podmanConfig.ContainersConfDefaultsRO.Containers.DefaultMountsFile, "Path to default mounts file")
 
 		eventsBackendFlagName := "events-backend"
-		pFlags.StringVar(&podmanConfig.ContainersConf.Engine.EventsLogger, eventsBackendFlagName, podmanConfig.ContainersConfDefaultsRO.Engine.EventsLogger, `Events backend to use ("file"|"journald"|"none")`)
+		pFlags.StringVar(&podmanConfig.ContainersConf.Engine.EventsLogger, eventsBackendFlagName, podmanConfig.ContainersConfDefaultsRO.Engine.EventsLogger, `Events backend to use ("file"|"journald"|"sqlite"|"none")`)
 		_ = cmd.RegisterFlagCompletionFunc(eventsBackendFlagName, common.AutocompleteEventBackend)
 
 		hooksDirFlagName := "hooks-dir"
@@ -29,7 +29,9 @@ var (
 		Example: `podman events
   podman events --filter event=create
   podman events --format {{.Image}}
-  podman events --since 1h30s`,
+  podman events --format cloudevents
+  podman events --since 1h30s
+  podman events --count 10 --stream=false`,
 	}
 
 	systemEventsCommand = &cobra.Command{
@@ -43,6 +45,13 @@ var (
 	}
 )
 
+const (
+	// cloudEventsFormat prints events as CloudEvents 1.0.
+	cloudEventsFormat = "cloudevents"
+	// dockerJSONFormat prints events in the format of the Docker API.
+	dockerJSONFormat = "docker-json"
+)
+
 var (
 	eventOptions entities.EventsOptions
 	eventFormat  string
@@ -69,8 +78,8 @@ func eventsFlags(cmd *cobra.Command) {
 	_ = cmd.RegisterFlagCompletionFunc(filterFlagName, common.AutocompleteEventFilter)
 
 	formatFlagName := "format"
-	flags.StringVar(&eventFormat, formatFlagName, "", "format the output using a Go template")
-	_ = cmd.RegisterFlagCompletionFunc(formatFlagName, common.AutocompleteFormat(&events.Event{}))
+	flags.StringVar(&eventFormat, formatFlagName, "", "format the output as JSON, CloudEvents (cloudevents), Docker-compatible JSON (docker-json) or using a Go template")
+	_ = cmd.RegisterFlagCompletionFunc(formatFlagName, common.AutocompleteEventFormat)
 
 	flags.BoolVar(&eventOptions.Stream, "stream", true, "stream events and do not exit when returning the last known event")
 
@@ -83,10 +92,14 @@ func eventsFlags(cmd *cobra.Command) {
 	untilFlagName := "until"
 	flags.StringVar(&eventOptions.Until, untilFlagName, "", "show all events until timestamp")
 	_ = cmd.RegisterFlagCompletionFunc(untilFlagName, completion.AutocompleteNone)
+
+	countFlagName := "count"
+	flags.UintVar(&eventOptions.Count, countFlagName, 0, "show only the specified number of most recent events")
+	_ = cmd.RegisterFlagCompletionFunc(countFlagName, completion.AutocompleteNone)
 }
 
 func eventsCmd(cmd *cobra.Command, _ []string) error {
-	if len(eventOptions.Since) > 0 || len(eventOptions.Until) > 0 {
+	if len(eventOptions.Since) > 0 || len(eventOptions.Until) > 0 || eventOptions.Count > 0 {
 		eventOptions.FromStart = true
 	}
 	eventChannel := make(chan *events.Event, 1)
@@ -96,11 +109,12 @@ func eventsCmd(cmd *cobra.Command, _ []string) error {
 	var (
 		rpt    *report.Formatter
 		doJSON bool
+		source = entities.CloudEventsSource()
 	)
 
 	if cmd.Flags().Changed("format") {
 		doJSON = report.IsJSON(eventFormat)
-		if !doJSON {
+		if !doJSON && eventFormat != cloudEventsFormat && eventFormat != dockerJSONFormat {
 			var err error
 			// Use OriginUnknown so it does not add an extra range since it
 			// will only be called for each single element and not a slice.
@@ -130,6 +144,18 @@ func eventsCmd(cmd *cobra.Command, _ []string) error {
 					return err
 				}
 				fmt.Println(jsonStr)
+			case eventFormat == cloudEventsFormat:
+				cloudEvent, err := entities.ConvertToCloudEvent(*event, source)
+				if err != nil {
+					return err
+				}
+				if err := printJSONLine(cloudEvent); err != nil {
+					return err
+				}
+			case eventFormat == dockerJSONFormat:
+				if err := printJSONLine(entities.ConvertToDockerEvent(*event)); err != nil {
+					return err
+				}
 			case cmd.Flags().Changed("format"):
 				if err := rpt.Execute(event); err != nil {
 					return err
@@ -146,3 +172,13 @@ func eventsCmd(cmd *cobra.Command, _ []string) error {
 		}
 	}
 }
+
+// printJSONLine prints the value as JSON on a single line.
+func printJSONLine(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
@@ -21,6 +21,8 @@ var (
         podman system migrate
 
         Migrate existing containers to a new version of Podman.
+
+        With --migrate-db, the database is migrated from boltdb to sqlite.
 `
 
 	migrateCommand = &cobra.Command{
@@ -34,6 +36,9 @@ var (
 		Long:              migrateDescription,
 		Run:               migrate,
 		ValidArgsFunction: completion.AutocompleteNone,
+		Example: `podman system migrate
+  podman system migrate --migrate-db --dry-run
+  podman system migrate --rollback-db`,
 	}
 )
 
@@ -52,9 +57,22 @@ func init() {
 	newRuntimeFlagName := "new-runtime"
 	flags.StringVar(&migrateOptions.NewRuntime, newRuntimeFlagName, "", "Specify a new runtime for all containers")
 	_ = migrateCommand.RegisterFlagCompletionFunc(newRuntimeFlagName, completion.AutocompleteNone)
+
+	flags.BoolVar(&migrateOptions.MigrateDB, "migrate-db", false, "Migrate the database from boltdb to sqlite")
+	flags.BoolVar(&migrateOptions.RollbackDB, "rollback-db", false, "Roll back a migration of the database to sqlite")
+	flags.BoolVar(&migrateOptions.DryRun, "dry-run", false, "Only report what migrating or rolling back the database would do")
 }
 
 func migrate(cmd *cobra.Command, args []string) {
+	if migrateOptions.MigrateDB && migrateOptions.RollbackDB {
+		fmt.Println("--migrate-db and --rollback-db cannot be used together")
+		os.Exit(define.ExecErrorCodeGeneric)
+	}
+	if migrateOptions.DryRun && !migrateOptions.MigrateDB && !migrateOptions.RollbackDB {
+		fmt.Println("--dry-run can only be used with --migrate-db or --rollback-db")
+		os.Exit(define.ExecErrorCodeGeneric)
+	}
+
 	// Shutdown all running engines, `renumber` will hijack repository
 	registry.ContainerEngine().Shutdown(registry.Context())
 	registry.ImageEngine().Shutdown(registry.Context())
@@ -66,7 +84,7 @@ func migrate(cmd *cobra.Command, args []string) {
 	}
 	defer engine.Shutdown(registry.Context())
 
-	err = engine.Migrate(registry.Context(), cmd.Flags(), registry.PodmanConfig(), migrateOptions)
+	report, err := engine.Migrate(registry.Context(), cmd.Flags(), registry.PodmanConfig(), migrateOptions)
 	if err != nil {
 		fmt.Println(err)
 
@@ -75,5 +93,8 @@ func migrate(cmd *cobra.Command, args []string) {
 		//nolint:gocritic
 		os.Exit(define.ExecErrorCodeGeneric)
 	}
+	if report.DBMigration != "" {
+		fmt.Println(report.DBMigration)
+	}
 	os.Exit(0)
 }
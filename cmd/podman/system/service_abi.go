@@ -121,6 +121,9 @@ func restService(flags *pflag.FlagSet, cfg *entities.PodmanConfig, opts entities
 
 	servicereaper.Start()
 	infra.StartWatcher(libpodRuntime)
+	if err := libpodRuntime.StartHealthCheckScheduler(); err != nil {
+		logrus.Warnf("Unable to start healthcheck scheduler: %v", err)
+	}
 	server, err := api.NewServerWithSettings(libpodRuntime, listener, opts)
 	if err != nil {
 		return err
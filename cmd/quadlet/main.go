@@ -1,11 +1,9 @@
 package main
 
 import (
-	"errors"
 	"flag"
 	"fmt"
 	"os"
-	"os/user"
 	"path"
 	"path/filepath"
 	"strings"
@@ -38,16 +36,6 @@ var (
 	kmsgFile *os.File
 )
 
-var (
-	void                struct{}
-	supportedExtensions = map[string]struct{}{
-		".container": void,
-		".volume":    void,
-		".kube":      void,
-		".network":   void,
-	}
-)
-
 // We log directly to /dev/kmsg, because that is the only way to get information out
 // of the generator into the system logs.
 func logToKmsg(s string) bool {
@@ -96,68 +84,6 @@ func Debugf(format string, a ...interface{}) {
 	}
 }
 
-// This returns the directories where we read quadlet .container and .volumes from
-// For system generators these are in /usr/share/containers/systemd (for distro files)
-// and /etc/containers/systemd (for sysadmin files).
-// For user generators these can live in /etc/containers/systemd/users, /etc/containers/systemd/users/$UID, and $XDG_CONFIG_HOME/containers/systemd
-func getUnitDirs(rootless bool) []string {
-	// Allow overriding source dir, this is mainly for the CI tests
-	unitDirsEnv := os.Getenv("QUADLET_UNIT_DIRS")
-	if len(unitDirsEnv) > 0 {
-		return strings.Split(unitDirsEnv, ":")
-	}
-
-	dirs := make([]string, 0)
-	if rootless {
-		configDir, err := os.UserConfigDir()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: %v", err)
-			return nil
-		}
-		dirs = append(dirs, path.Join(configDir, "containers/systemd"))
-		u, err := user.Current()
-		if err == nil {
-			dirs = append(dirs, filepath.Join(quadlet.UnitDirAdmin, "users", u.Uid))
-		} else {
-			fmt.Fprintf(os.Stderr, "Warning: %v", err)
-		}
-		return append(dirs, filepath.Join(quadlet.UnitDirAdmin, "users"))
-	}
-	dirs = append(dirs, quadlet.UnitDirAdmin)
-	return append(dirs, quadlet.UnitDirDistro)
-}
-
-func isExtSupported(filename string) bool {
-	ext := filepath.Ext(filename)
-	_, ok := supportedExtensions[ext]
-	return ok
-}
-
-func loadUnitsFromDir(sourcePath string, units map[string]*parser.UnitFile) {
-	files, err := os.ReadDir(sourcePath)
-	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			Logf("Can't read \"%s\": %s", sourcePath, err)
-		}
-		return
-	}
-
-	for _, file := range files {
-		name := file.Name()
-		if units[name] == nil && isExtSupported(name) {
-			path := path.Join(sourcePath, name)
-
-			Debugf("Loading source unit file %s", path)
-
-			if f, err := parser.ParseUnitFile(path); err != nil {
-				Logf("Error loading '%s', ignoring: %s", path, err)
-			} else {
-				units[name] = f
-			}
-		}
-	}
-}
-
 func generateServiceFile(service *parser.UnitFile) error {
 	Debugf("writing '%s'", service.Path)
 
@@ -197,20 +123,36 @@ func enableServiceFile(outputPath string, service *parser.UnitFile) {
 		symlinks = append(symlinks, filepath.Clean(alias))
 	}
 
-	wantedBy := service.LookupAllStrv(quadlet.InstallGroup, "WantedBy")
-	for _, wantedByUnit := range wantedBy {
-		// Only allow filenames, not paths
-		if !strings.Contains(wantedByUnit, "/") {
-			symlinks = append(symlinks, fmt.Sprintf("%s.wants/%s", wantedByUnit, service.Filename))
+	// Templates can only be wanted or required as an instance, so like
+	// "systemctl enable" we use the DefaultInstance, if there is one
+	serviceName := service.Filename
+	if strings.HasSuffix(serviceName, "@.service") {
+		defaultInstance, _ := service.Lookup(quadlet.InstallGroup, "DefaultInstance")
+		if len(defaultInstance) > 0 {
+			serviceName = strings.TrimSuffix(serviceName, ".service") + defaultInstance + ".service"
+		} else {
+			serviceName = ""
 		}
 	}
 
-	requiredBy := service.LookupAllStrv(quadlet.InstallGroup, "RequiredBy")
-	for _, requiredByUnit := range requiredBy {
-		// Only allow filenames, not paths
-		if !strings.Contains(requiredByUnit, "/") {
-			symlinks = append(symlinks, fmt.Sprintf("%s.requires/%s", requiredByUnit, service.Filename))
+	if len(serviceName) > 0 {
+		wantedBy := service.LookupAllStrv(quadlet.InstallGroup, "WantedBy")
+		for _, wantedByUnit := range wantedBy {
+			// Only allow filenames, not paths
+			if !strings.Contains(wantedByUnit, "/") {
+				symlinks = append(symlinks, fmt.Sprintf("%s.wants/%s", wantedByUnit, serviceName))
+			}
+		}
+
+		requiredBy := service.LookupAllStrv(quadlet.InstallGroup, "RequiredBy")
+		for _, requiredByUnit := range requiredBy {
+			// Only allow filenames, not paths
+			if !strings.Contains(requiredByUnit, "/") {
+				symlinks = append(symlinks, fmt.Sprintf("%s.requires/%s", requiredByUnit, serviceName))
+			}
 		}
+	} else if service.HasKey(quadlet.InstallGroup, "WantedBy") || service.HasKey(quadlet.InstallGroup, "RequiredBy") {
+		Logf("Template unit %s has no DefaultInstance, not creating WantedBy or RequiredBy symlinks", service.Filename)
 	}
 
 	for _, symlinkRel := range symlinks {
@@ -292,13 +234,17 @@ func isUnambiguousName(imageName string) bool {
 //
 // We implement a simple version of this from scratch here to avoid
 // a huge dependency in the generator just for a warning.
-func warnIfAmbiguousName(container *parser.UnitFile) {
-	imageName, ok := container.Lookup(quadlet.ContainerGroup, quadlet.KeyImage)
+func warnIfAmbiguousName(unit *parser.UnitFile, group string) {
+	imageName, ok := unit.Lookup(group, quadlet.KeyImage)
 	if !ok {
 		return
 	}
+	// References to .image and .build units are checked when those units are converted
+	if strings.HasSuffix(imageName, ".image") || strings.HasSuffix(imageName, ".build") {
+		return
+	}
 	if !isUnambiguousName(imageName) {
-		Logf("Warning: %s specifies the image \"%s\" which not a fully qualified image name. This is not ideal for performance and security reasons. See the podman-pull manpage discussion of short-name-aliases.conf for details.", container.Filename, imageName)
+		Logf("Warning: %s specifies the image \"%s\" which not a fully qualified image name. This is not ideal for performance and security reasons. See the podman-pull manpage discussion of short-name-aliases.conf for details.", unit.Filename, imageName)
 	}
 }
 
@@ -335,11 +281,13 @@ func main() {
 		Debugf("Starting quadlet-generator, output to: %s", outputPath)
 	}
 
-	sourcePaths := getUnitDirs(isUserFlag)
+	sourcePaths := quadlet.GetUnitDirs(isUserFlag)
 
 	units := make(map[string]*parser.UnitFile)
 	for _, d := range sourcePaths {
-		loadUnitsFromDir(d, units)
+		if err := quadlet.LoadUnitsFromDir(d, units); err != nil {
+			Logf("%s", err)
+		}
 	}
 
 	if len(units) == 0 {
@@ -349,6 +297,13 @@ func main() {
 		os.Exit(0)
 	}
 
+	for name, unit := range units {
+		Debugf("Loaded source unit file %s", unit.Path)
+		if err := quadlet.LoadUnitDropins(unit, sourcePaths); err != nil {
+			Logf("Error loading drop-ins for '%s': %s", name, err)
+		}
+	}
+
 	if !dryRunFlag {
 		err := os.MkdirAll(outputPath, os.ModePerm)
 		if err != nil {
@@ -357,44 +312,35 @@ func main() {
 		}
 	}
 
-	for name, unit := range units {
-		var service *parser.UnitFile
-		var err error
-
+	for _, converted := range quadlet.ConvertUnits(units, isUserFlag) {
 		switch {
-		case strings.HasSuffix(name, ".container"):
-			warnIfAmbiguousName(unit)
-			service, err = quadlet.ConvertContainer(unit, isUserFlag)
-		case strings.HasSuffix(name, ".volume"):
-			service, err = quadlet.ConvertVolume(unit, name)
-		case strings.HasSuffix(name, ".kube"):
-			service, err = quadlet.ConvertKube(unit, isUserFlag)
-		case strings.HasSuffix(name, ".network"):
-			service, err = quadlet.ConvertNetwork(unit, name)
-		default:
-			Logf("Unsupported file type '%s'", name)
+		case strings.HasSuffix(converted.Name, ".container"):
+			warnIfAmbiguousName(converted.Unit, quadlet.ContainerGroup)
+		case strings.HasSuffix(converted.Name, ".image"):
+			warnIfAmbiguousName(converted.Unit, quadlet.ImageGroup)
+		}
+
+		if converted.Err != nil {
+			Logf("Error converting '%s', ignoring: %s", converted.Name, converted.Err)
 			continue
 		}
 
-		if err != nil {
-			Logf("Error converting '%s', ignoring: %s", name, err)
-		} else {
-			service.Path = path.Join(outputPath, service.Filename)
-
-			if dryRunFlag {
-				data, err := service.ToString()
-				if err != nil {
-					Debugf("Error parsing %s\n---\n", service.Path)
-					exitCode = 1
-				} else {
-					fmt.Printf("---%s---\n%s\n", service.Path, data)
-				}
+		service := converted.Service
+		service.Path = path.Join(outputPath, service.Filename)
+
+		if dryRunFlag {
+			data, err := service.ToString()
+			if err != nil {
+				Debugf("Error parsing %s\n---\n", service.Path)
+				exitCode = 1
 			} else {
-				if err := generateServiceFile(service); err != nil {
-					Logf("Error writing '%s'o: %s", service.Path, err)
-				}
-				enableServiceFile(outputPath, service)
+				fmt.Printf("---%s---\n%s\n", service.Path, data)
+			}
+		} else {
+			if err := generateServiceFile(service); err != nil {
+				Logf("Error writing '%s'o: %s", service.Path, err)
 			}
+			enableServiceFile(outputPath, service)
 		}
 	}
 
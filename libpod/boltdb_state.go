@@ -2,6 +2,7 @@ package libpod
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"net"
@@ -18,10 +19,11 @@ import (
 
 // BoltState is a state implementation backed by a Bolt DB
 type BoltState struct {
-	valid   bool
-	dbPath  string
-	dbLock  sync.Mutex
-	runtime *Runtime
+	valid    bool
+	readOnly bool
+	dbPath   string
+	dbLock   sync.Mutex
+	runtime  *Runtime
 }
 
 // A brief description of the format of the BoltDB state:
@@ -66,6 +68,9 @@ type BoltState struct {
 //   read the exit code from the containers bucket.  Hence, exit codes go into
 //   their own bucket.  To avoid the rather expensive JSON (un)marshalling, we
 //   have two buckets: one for the exit codes, the other for the timestamps.
+// - autoUpdateRecordsBkt: Contains the JSON encoded records of updates
+//   performed by auto-update keyed by a sequence number, such that updates
+//   can be listed and rolled back.
 
 // NewBoltState creates a new bolt-backed state database
 func NewBoltState(path string, runtime *Runtime) (State, error) {
@@ -101,6 +106,7 @@ func NewBoltState(path string, runtime *Runtime) (State, error) {
 		exitCodeBkt,
 		exitCodeTimeStampBkt,
 		volCtrsBkt,
+		autoUpdateRecordsBkt,
 	}
 
 	// Does the DB need an update?
@@ -141,6 +147,19 @@ func NewBoltState(path string, runtime *Runtime) (State, error) {
 	return state, nil
 }
 
+// newReadOnlyBoltState opens an existing bolt-backed state database without
+// ever modifying it, e.g. to migrate it to another database backend.
+func newReadOnlyBoltState(path string, runtime *Runtime) *BoltState {
+	logrus.Debugf("Opening boltdb state at %s read-only", path)
+
+	return &BoltState{
+		valid:    true,
+		readOnly: true,
+		dbPath:   path,
+		runtime:  runtime,
+	}
+}
+
 // Close closes the state and prevents further use
 func (s *BoltState) Close() error {
 	s.valid = false
@@ -1536,6 +1555,82 @@ func (s *BoltState) PruneContainerExitCodes() error {
 	return nil
 }
 
+// AddAutoUpdateRecord adds a record of an update performed by auto-update to
+// the database.
+func (s *BoltState) AddAutoUpdateRecord(record *define.AutoUpdateRecord) error {
+	if !s.valid {
+		return define.ErrDBClosed
+	}
+
+	rawRecord, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshalling auto-update record of container %s: %w", record.ContainerID, err)
+	}
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return err
+	}
+	defer s.deferredCloseDBCon(db)
+
+	return db.Update(func(tx *bolt.Tx) error {
+		recordsBucket, err := getAutoUpdateRecordsBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		seq, err := recordsBucket.NextSequence()
+		if err != nil {
+			return fmt.Errorf("getting sequence number of auto-update record: %w", err)
+		}
+		rawSeq := make([]byte, 8)
+		binary.BigEndian.PutUint64(rawSeq, seq)
+
+		if err := recordsBucket.Put(rawSeq, rawRecord); err != nil {
+			return fmt.Errorf("adding auto-update record of container %s to DB: %w", record.ContainerID, err)
+		}
+		return nil
+	})
+}
+
+// AllAutoUpdateRecords returns all auto-update records in the order they were
+// added to the database.
+func (s *BoltState) AllAutoUpdateRecords() ([]*define.AutoUpdateRecord, error) {
+	if !s.valid {
+		return nil, define.ErrDBClosed
+	}
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return nil, err
+	}
+	defer s.deferredCloseDBCon(db)
+
+	records := []*define.AutoUpdateRecord{}
+	err = db.View(func(tx *bolt.Tx) error {
+		recordsBucket, err := getAutoUpdateRecordsBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		// Keys are big-endian sequence numbers, so iterating in key
+		// order yields the records in the order they were added.
+		return recordsBucket.ForEach(func(_, rawRecord []byte) error {
+			record := new(define.AutoUpdateRecord)
+			if err := json.Unmarshal(rawRecord, record); err != nil {
+				return fmt.Errorf("unmarshalling auto-update record: %w", err)
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
 // AddExecSession adds an exec session to the state.
 func (s *BoltState) AddExecSession(ctr *Container, session *ExecSession) error {
 	if !s.valid {
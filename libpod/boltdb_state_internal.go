@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/containers/podman/v4/libpod/define"
 	"github.com/containers/podman/v4/pkg/rootless"
@@ -31,6 +33,8 @@ const (
 	exitCodeName          = "exit-code"
 	exitCodeTimeStampName = "exit-code-time-stamp"
 
+	autoUpdateRecordsName = "auto-update-records"
+
 	configName         = "config"
 	stateName          = "state"
 	dependenciesName   = "dependencies"
@@ -69,6 +73,8 @@ var (
 	exitCodeBkt          = []byte(exitCodeName)
 	exitCodeTimeStampBkt = []byte(exitCodeTimeStampName)
 
+	autoUpdateRecordsBkt = []byte(autoUpdateRecordsName)
+
 	configKey     = []byte(configName)
 	stateKey      = []byte(stateName)
 	netNSKey      = []byte(netNSName)
@@ -249,7 +255,9 @@ func (s *BoltState) getDBCon() (*bolt.DB, error) {
 	// https://www.sqlite.org/src/artifact/c230a7a24?ln=994-1081
 	s.dbLock.Lock()
 
-	db, err := bolt.Open(s.dbPath, 0600, nil)
+	options := *bolt.DefaultOptions
+	options.ReadOnly = s.readOnly
+	db, err := bolt.Open(s.dbPath, 0600, &options)
 	if err != nil {
 		return nil, fmt.Errorf("opening database %s: %w", s.dbPath, err)
 	}
@@ -373,6 +381,14 @@ func getExitCodeTimeStampBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
 	return bkt, nil
 }
 
+func getAutoUpdateRecordsBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	bkt := tx.Bucket(autoUpdateRecordsBkt)
+	if bkt == nil {
+		return nil, fmt.Errorf("auto-update records bucket not found in DB: %w", define.ErrDBBadConfig)
+	}
+	return bkt, nil
+}
+
 func getVolumeContainersBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
 	bkt := tx.Bucket(volCtrsBkt)
 	if bkt == nil {
@@ -1028,3 +1044,69 @@ func (s *BoltState) lookupContainerID(idOrName string, ctrBucket, namesBucket *b
 	}
 	return id, nil
 }
+
+// boltStatePath returns the path of the bolt-backed state database of the
+// runtime.
+func boltStatePath(rt *Runtime) string {
+	baseDir := rt.config.Engine.StaticDir
+	if rt.storageConfig.TransientStore {
+		baseDir = rt.config.Engine.TmpDir
+	}
+	return filepath.Join(baseDir, "bolt_state.db")
+}
+
+// containerExitCode is an exit code in the database along with the time it
+// was added.
+type containerExitCode struct {
+	exitCode  int32
+	timeStamp time.Time
+}
+
+// allContainerExitCodes returns all exit codes in the database by container
+// ID.
+func (s *BoltState) allContainerExitCodes() (map[string]containerExitCode, error) {
+	if !s.valid {
+		return nil, define.ErrDBClosed
+	}
+
+	db, err := s.getDBCon()
+	if err != nil {
+		return nil, err
+	}
+	defer s.deferredCloseDBCon(db)
+
+	exitCodes := make(map[string]containerExitCode)
+	err = db.View(func(tx *bolt.Tx) error {
+		exitCodeBucket, err := getExitCodeBucket(tx)
+		if err != nil {
+			return err
+		}
+		timeStampBucket, err := getExitCodeTimeStampBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		return exitCodeBucket.ForEach(func(rawID, rawExitCode []byte) error {
+			exitCode, err := strconv.Atoi(string(rawExitCode))
+			if err != nil {
+				return fmt.Errorf("converting raw exit code %v of container %s: %w", rawExitCode, string(rawID), err)
+			}
+			var timeStamp time.Time
+			if rawTimeStamp := timeStampBucket.Get(rawID); rawTimeStamp != nil {
+				if err := timeStamp.UnmarshalText(rawTimeStamp); err != nil {
+					return fmt.Errorf("converting raw exit-code time stamp of container %s: %w", string(rawID), err)
+				}
+			}
+			exitCodes[string(rawID)] = containerExitCode{
+				exitCode:  int32(exitCode),
+				timeStamp: timeStamp,
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return exitCodes, nil
+}
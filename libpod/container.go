@@ -214,6 +214,15 @@ type ContainerState struct {
 	// healthcheck. The container will be restarted if this exceed a set
 	// number in the startup HC config.
 	StartupHCFailureCount int `json:"startupHCFailureCount,omitempty"`
+	// Ready indicates that the readiness check of the container has
+	// succeeded (see ReadinessCheckConfig).
+	Ready bool `json:"ready,omitempty"`
+	// ReadinessSuccessCount indicates the number of consecutive successes
+	// of the readiness check.
+	ReadinessSuccessCount int `json:"readinessSuccessCount,omitempty"`
+	// ReadinessFailureCount indicates the number of consecutive failures
+	// of the readiness check.
+	ReadinessFailureCount int `json:"readinessFailureCount,omitempty"`
 
 	// ExtensionStageHooks holds hooks which will be executed by libpod
 	// and not delegated to the OCI runtime.
@@ -981,6 +990,38 @@ func (c *Container) StartupHCPassed() (bool, error) {
 	return c.state.StartupHCPassed, nil
 }
 
+// Ready returns whether the container is ready.  A running container without
+// a readiness check is always ready.
+func (c *Container) Ready() (bool, error) {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return false, err
+		}
+	}
+
+	return c.isReady(), nil
+}
+
+// ReadinessStatus returns the status of the container's readiness check:
+// define.ReadinessCheckReady or define.ReadinessCheckNotReady.  An empty
+// string is returned if the container has no readiness check.
+func (c *Container) ReadinessStatus() (string, error) {
+	if c.config.ReadinessCheckConfig == nil {
+		return "", nil
+	}
+	ready, err := c.Ready()
+	if err != nil {
+		return "", err
+	}
+	if ready {
+		return define.ReadinessCheckReady, nil
+	}
+	return define.ReadinessCheckNotReady, nil
+}
+
 // Misc Accessors
 // Most will require locking
 
@@ -684,11 +684,17 @@ type waitResult struct {
 }
 
 func (c *Container) WaitForConditionWithInterval(ctx context.Context, waitTimeout time.Duration, conditions ...define.ContainerStatus) (int32, error) {
+	return c.WaitForReadyOrConditionWithInterval(ctx, waitTimeout, false, conditions...)
+}
+
+// WaitForReadyOrConditionWithInterval waits for one of the conditions or, if
+// waitForReady is set, for the container to pass its readiness check.
+func (c *Container) WaitForReadyOrConditionWithInterval(ctx context.Context, waitTimeout time.Duration, waitForReady bool, conditions ...define.ContainerStatus) (int32, error) {
 	if !c.valid {
 		return -1, define.ErrCtrRemoved
 	}
 
-	if len(conditions) == 0 {
+	if len(conditions) == 0 && !waitForReady {
 		panic("at least one condition should be passed")
 	}
 
@@ -752,6 +758,48 @@ func (c *Container) WaitForConditionWithInterval(ctx context.Context, waitTimeou
 		}()
 	}
 
+	if waitForReady {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				ready, err := c.Ready()
+				if err != nil {
+					trySend(-1, err)
+					return
+				}
+				if ready {
+					trySend(-1, nil)
+					return
+				}
+				// Nothing else may be watching the state, so stop
+				// waiting once the container has exited: it will not
+				// become ready before it is started again.
+				state, err := c.State()
+				if err != nil {
+					trySend(-1, err)
+					return
+				}
+				if state == define.ContainerStateStopped || state == define.ContainerStateExited {
+					code, _, err := c.ExitCode()
+					if err != nil {
+						trySend(-1, err)
+						return
+					}
+					trySend(code, fmt.Errorf("container %s exited with code %d before it was ready: %w", c.ID(), code, define.ErrCtrStopped))
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(waitTimeout):
+					continue
+				}
+			}
+		}()
+	}
+
 	var result waitResult
 	select {
 	case result = <-resultChan:
@@ -370,6 +370,14 @@ type ContainerMiscConfig struct {
 	LogSize int64 `json:"logSize"`
 	// LogDriver driver for logs
 	LogDriver string `json:"logDriver"`
+	// LogOptions are the log driver specific options, e.g., the maximum
+	// number of log files of the json-file driver.
+	LogOptions map[string]string `json:"logOptions,omitempty"`
+	// JSONFileLogFormat is set if the json-file log driver writes the log
+	// in the format of Docker's json-file log driver.  It is unset for
+	// containers created while json-file was an alias of k8s-file, which
+	// keep writing the k8s-file format.
+	JSONFileLogFormat bool `json:"jsonFileLogFormat,omitempty"`
 	// File containing the conmon PID
 	ConmonPidFile string `json:"conmonPidFile,omitempty"`
 	// RestartPolicy indicates what action the container will take upon
@@ -405,10 +413,25 @@ type ContainerMiscConfig struct {
 	HealthCheckConfig *manifest.Schema2HealthConfig `json:"healthcheck"`
 	// HealthCheckOnFailureAction defines an action to take once the container turns unhealthy.
 	HealthCheckOnFailureAction define.HealthCheckOnFailureAction `json:"healthcheck_on_failure_action"`
+	// HealthLogDestination is the destination of the healthcheck log.
+	// If empty, the log is written to a file.
+	HealthLogDestination string `json:"healthLogDestination,omitempty"`
+	// HealthMaxLogCount is the maximum number of entries of the
+	// healthcheck log file.  If nil, define.DefaultHealthMaxLogCount is
+	// used.  0 means unlimited.
+	HealthMaxLogCount *uint `json:"healthMaxLogCount,omitempty"`
+	// HealthMaxLogSize is the maximum number of bytes of the output of a
+	// healthcheck stored in the log.  If nil, define.DefaultHealthMaxLogSize
+	// is used.  0 means unlimited.
+	HealthMaxLogSize *uint `json:"healthMaxLogSize,omitempty"`
 	// StartupHealthCheckConfig is the configuration of the startup
 	// healthcheck for the container. This will run before the regular HC
 	// runs, and when it passes the regular HC will be activated.
 	StartupHealthCheckConfig *define.StartupHealthCheck `json:"startupHealthCheck,omitempty"`
+	// ReadinessCheckConfig is the configuration of the readiness check
+	// for the container.  It runs independently of the healthchecks and
+	// marks the container as ready once it passes.
+	ReadinessCheckConfig *define.ReadinessCheck `json:"readinessCheck,omitempty"`
 	// PreserveFDs is a number of additional file descriptors (in addition
 	// to 0, 1, 2) that will be passed to the executed process. The total FDs
 	// passed will be 3 + PreserveFDs.
@@ -247,6 +247,16 @@ func startNode(ctx context.Context, node *containerNode, setError bool, ctrError
 		ctrErrored = true
 	}
 
+	// Wait for dependencies with a readiness check to become ready.
+	// Like the check above, this does not require that the container be
+	// locked.
+	if !ctrErrored {
+		if err := node.container.waitForDependenciesReady(ctx); err != nil {
+			ctrErrors[node.id] = err
+			ctrErrored = true
+		}
+	}
+
 	// Lock before we start
 	node.container.lock.Lock()
 
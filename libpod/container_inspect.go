@@ -140,6 +140,7 @@ func (c *Container) getContainerInspectData(size bool, driverData *define.Driver
 			CheckpointPath: runtimeInfo.CheckpointPath,
 			CheckpointLog:  runtimeInfo.CheckpointLog,
 			RestoreLog:     runtimeInfo.RestoreLog,
+			Ready:          c.isReady(),
 		},
 		Image:                   config.RootfsImageID,
 		ImageName:               config.RootfsImageName,
@@ -399,6 +400,13 @@ func (c *Container) generateInspectContainerConfig(spec *spec.Spec) *define.Insp
 	ctrConfig.Healthcheck = c.config.HealthCheckConfig
 
 	ctrConfig.HealthcheckOnFailureAction = c.config.HealthCheckOnFailureAction.String()
+	if c.HasHealthCheck() {
+		ctrConfig.HealthLogDestination = c.healthLogDestination()
+		ctrConfig.HealthMaxLogCount = c.healthMaxLogCount()
+		ctrConfig.HealthMaxLogSize = c.healthMaxLogSize()
+	}
+
+	ctrConfig.ReadinessCheck = c.config.ReadinessCheckConfig
 
 	ctrConfig.CreateCommand = c.config.CreateCommand
 
@@ -450,6 +458,7 @@ func (c *Container) generateInspectContainerHostConfig(ctrSpec *spec.Spec, named
 	logConfig.Path = c.config.LogPath
 	logConfig.Size = units.HumanSize(float64(c.config.LogSize))
 	logConfig.Tag = c.config.LogTag
+	logConfig.Config = c.config.LogOptions
 
 	hostConfig.LogConfig = logConfig
 
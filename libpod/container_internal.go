@@ -24,6 +24,7 @@ import (
 	"github.com/containers/common/pkg/config"
 	"github.com/containers/common/pkg/hooks"
 	"github.com/containers/common/pkg/hooks/exec"
+	"github.com/containers/common/pkg/secrets"
 	cutil "github.com/containers/common/pkg/util"
 	"github.com/containers/podman/v4/libpod/define"
 	"github.com/containers/podman/v4/libpod/events"
@@ -195,6 +196,7 @@ func (c *Container) handleExitFile(exitFile string, fi os.FileInfo) error {
 	oomFilePath := filepath.Join(c.bundlePath(), "oom")
 	if _, err = os.Stat(oomFilePath); err == nil {
 		c.state.OOMKilled = true
+		c.newContainerOOMEvent()
 	}
 
 	c.state.Exited = true
@@ -264,6 +266,11 @@ func (c *Container) handleRestartPolicy(ctx context.Context) (_ bool, retErr err
 			return false, err
 		}
 	}
+	if c.config.ReadinessCheckConfig != nil {
+		if err := c.removeReadinessTransientFiles(ctx); err != nil {
+			return false, err
+		}
+	}
 
 	// Is the container running again?
 	// If so, we don't have to do anything
@@ -623,6 +630,9 @@ func resetContainerState(state *ContainerState) {
 	state.StartupHCPassed = false
 	state.StartupHCSuccessCount = 0
 	state.StartupHCFailureCount = 0
+	state.Ready = false
+	state.ReadinessSuccessCount = 0
+	state.ReadinessFailureCount = 0
 	state.NetNS = ""
 	state.NetworkStatus = nil
 	state.NetworkStatusOld = nil
@@ -799,6 +809,26 @@ func (c *Container) prepareToStart(ctx context.Context, recursive bool) (retErr
 		}
 	}
 
+	// Waiting for the dependencies to become ready may take a while, so
+	// do not hold the lock of the container in the meantime.
+	if !c.batched {
+		c.lock.Unlock()
+	}
+	waitErr := c.waitForDependenciesReady(ctx)
+	if !c.batched {
+		c.lock.Lock()
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+	if waitErr != nil {
+		return waitErr
+	}
+	// The container may have been started or removed while unlocked
+	if !c.ensureState(define.ContainerStateConfigured, define.ContainerStateCreated, define.ContainerStateStopped, define.ContainerStateExited) {
+		return fmt.Errorf("container %s must be in Created or Stopped state to be started: %w", c.ID(), define.ErrCtrStateInvalid)
+	}
+
 	defer func() {
 		if retErr != nil {
 			if err := c.cleanup(ctx); err != nil {
@@ -1076,6 +1106,7 @@ func (c *Container) init(ctx context.Context, retainRetries bool) error {
 	c.state.StartupHCFailureCount = 0
 	c.state.StartupHCSuccessCount = 0
 	c.state.StartupHCPassed = false
+	c.resetReadiness()
 
 	if !retainRetries {
 		c.state.RestartCount = 0
@@ -1103,6 +1134,11 @@ func (c *Container) init(ctx context.Context, retainRetries bool) error {
 			logrus.Error(err)
 		}
 	}
+	if c.config.ReadinessCheckConfig != nil {
+		if err := c.createReadinessTimer(); err != nil {
+			logrus.Error(err)
+		}
+	}
 
 	defer c.newContainerEvent(events.Init)
 	return c.completeNetworkSetup()
@@ -1256,6 +1292,11 @@ func (c *Container) start() error {
 			logrus.Error(err)
 		}
 	}
+	if c.config.ReadinessCheckConfig != nil {
+		if err := c.startReadinessTimer(); err != nil {
+			logrus.Error(err)
+		}
+	}
 
 	defer c.newContainerEvent(events.Start)
 
@@ -1458,6 +1499,11 @@ func (c *Container) restartWithTimeout(ctx context.Context, timeout uint) (retEr
 				logrus.Error(err.Error())
 			}
 		}
+		if c.config.ReadinessCheckConfig != nil {
+			if err := c.removeReadinessTransientFiles(context.Background()); err != nil {
+				logrus.Error(err.Error())
+			}
+		}
 		// Old versions of conmon have a bug where they create the exit file before
 		// closing open file descriptors causing a race condition when restarting
 		// containers with open ports since we cannot bind the ports as they're not
@@ -1956,6 +2002,11 @@ func (c *Container) cleanup(ctx context.Context) error {
 			logrus.Errorf("Removing timer for container %s healthcheck: %v", c.ID(), err)
 		}
 	}
+	if c.config.ReadinessCheckConfig != nil {
+		if err := c.removeReadinessTransientFiles(ctx); err != nil {
+			logrus.Errorf("Removing timer for container %s readiness check: %v", c.ID(), err)
+		}
+	}
 
 	// Clean up network namespace, if present
 	if err := c.cleanupNetwork(); err != nil {
@@ -2436,7 +2487,7 @@ func (c *Container) extractSecretToCtrStorage(secr *ContainerSecret) error {
 	if err != nil {
 		return err
 	}
-	_, data, err := manager.LookupSecretData(secr.Name)
+	data, err := c.lookupSecretData(manager, secr.Name)
 	if err != nil {
 		return err
 	}
@@ -2462,6 +2513,17 @@ func (c *Container) extractSecretToCtrStorage(secr *ContainerSecret) error {
 	return nil
 }
 
+// lookupSecretData returns the data of a secret used by the container and
+// records the use in a secret event.
+func (c *Container) lookupSecretData(manager *secrets.SecretsManager, name string) ([]byte, error) {
+	secret, data, err := manager.LookupSecretData(name)
+	if err != nil {
+		return nil, err
+	}
+	c.newSecretUseEvent(secret.ID, secret.Name)
+	return data, nil
+}
+
 // update calls the ociRuntime update function to modify a cgroup config after container creation
 func (c *Container) update(resources *spec.LinuxResources) error {
 	if err := c.ociRuntime.UpdateContainer(c, resources); err != nil {
@@ -605,7 +605,7 @@ func (c *Container) generateSpec(ctx context.Context) (s *spec.Spec, cleanupFunc
 			return nil, nil, err
 		}
 		for name, secr := range c.config.EnvSecrets {
-			_, data, err := manager.LookupSecretData(secr.Name)
+			data, err := c.lookupSecretData(manager, secr.Name)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/containers/podman/v4/libpod/define"
 	"github.com/containers/storage/pkg/idtools"
 	stypes "github.com/containers/storage/types"
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
@@ -197,3 +198,18 @@ func init() {
 		panic("we need a reliable executable path on Windows")
 	}
 }
+
+func TestLogFormatDriver(t *testing.T) {
+	c := &Container{config: &ContainerConfig{}}
+
+	// Containers created while json-file was an alias of k8s-file keep
+	// writing the k8s-file format.
+	c.config.LogDriver = define.JSONLogging
+	assert.Equal(t, define.KubernetesLogging, c.logFormatDriver())
+
+	c.config.JSONFileLogFormat = true
+	assert.Equal(t, define.JSONLogging, c.logFormatDriver())
+
+	c.config.LogDriver = define.JournaldLogging
+	assert.Equal(t, define.JournaldLogging, c.logFormatDriver())
+}
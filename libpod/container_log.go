@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/containers/podman/v4/libpod/define"
@@ -19,14 +21,50 @@ import (
 // logDrivers stores the currently available log drivers, do not modify
 var logDrivers []string
 
+// logForwarderTimeout is the maximum time to wait for the log forwarder of a
+// stopped container to write the remaining log.
+const logForwarderTimeout = 5 * time.Second
+
 func init() {
-	logDrivers = append(logDrivers, define.KubernetesLogging, define.NoLogging, define.PassthroughLogging)
+	logDrivers = append(logDrivers, define.KubernetesLogging, define.JSONLogging, define.SyslogLogging, define.FluentdLogging, define.NoLogging, define.PassthroughLogging)
 }
 
 // Log is a runtime function that can read one or more container logs.
+// The lines of multiple containers are ordered by their time.
 func (r *Runtime) Log(ctx context.Context, containers []*Container, options *logs.LogOptions, logChannel chan *logs.LogLine) error {
+	if len(containers) == 1 {
+		return containers[0].ReadLog(ctx, options, logChannel, 0)
+	}
+
+	// Read the log of every container into a channel of its own, which
+	// is closed once the container's log was read, and merge them.
+	inputs := make([]chan *logs.LogLine, len(containers))
+	for c := range inputs {
+		inputs[c] = make(chan *logs.LogLine, cap(logChannel))
+	}
+	var window time.Duration
+	if options.Follow {
+		window = logs.MergeWindow
+	}
+	options.WaitGroup.Add(1)
+	go func() {
+		defer options.WaitGroup.Done()
+		logs.MergeLogs(ctx, inputs, logChannel, window)
+	}()
+
 	for c, ctr := range containers {
-		if err := ctr.ReadLog(ctx, options, logChannel, int64(c)); err != nil {
+		var wg sync.WaitGroup
+		readOptions := *options
+		readOptions.WaitGroup = &wg
+		err := ctr.ReadLog(ctx, &readOptions, inputs[c], int64(c))
+		go func(input chan *logs.LogLine) {
+			wg.Wait()
+			close(input)
+		}(inputs[c])
+		if err != nil {
+			for _, input := range inputs[c+1:] {
+				close(input)
+			}
 			return err
 		}
 	}
@@ -34,8 +72,41 @@ func (r *Runtime) Log(ctx context.Context, containers []*Container, options *log
 }
 
 // ReadLog reads a containers log based on the input options and returns log lines over a channel.
+// The lines are filtered by their stream and message as specified in the options.
 func (c *Container) ReadLog(ctx context.Context, options *logs.LogOptions, logChannel chan *logs.LogLine, colorID int64) error {
-	switch c.LogDriver() {
+	filter := logs.NewLogFilter(options)
+	if filter == nil {
+		return c.readLog(ctx, options, logChannel, colorID)
+	}
+
+	// Read the log into an intermediate channel from which the lines
+	// passing the filter are sent to the log channel.
+	var wg sync.WaitGroup
+	readOptions := *options
+	readOptions.WaitGroup = &wg
+	lines := make(chan *logs.LogLine, cap(logChannel))
+	options.WaitGroup.Add(1)
+	go func() {
+		defer options.WaitGroup.Done()
+		for line := range lines {
+			for _, l := range filter.Filter(line) {
+				logChannel <- l
+			}
+		}
+		for _, l := range filter.Flush() {
+			logChannel <- l
+		}
+	}()
+	err := c.readLog(ctx, &readOptions, lines, colorID)
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+	return err
+}
+
+func (c *Container) readLog(ctx context.Context, options *logs.LogOptions, logChannel chan *logs.LogLine, colorID int64) error {
+	switch c.logFormatDriver() {
 	case define.PassthroughLogging:
 		// if running under systemd fallback to a more native journald reading
 		if unitName, ok := c.config.Labels[systemdDefine.EnvVariable]; ok {
@@ -47,9 +118,14 @@ func (c *Container) ReadLog(ctx context.Context, options *logs.LogOptions, logCh
 	case define.JournaldLogging:
 		return c.readFromJournal(ctx, options, logChannel, colorID, "")
 	case define.JSONLogging:
-		// TODO provide a separate implementation of this when Conmon
-		// has support.
-		fallthrough
+		c.waitForLogForwarder()
+		return c.readFromLogFile(ctx, options, logChannel, colorID)
+	case define.SyslogLogging, define.FluentdLogging:
+		if !logs.IsDualLogMode(c.config.LogOptions) {
+			return fmt.Errorf("this container is using the '%s' log driver, cannot read logs without --log-opt mode=dual: %w", c.LogDriver(), define.ErrNoLogs)
+		}
+		c.waitForLogForwarder()
+		return c.readFromLogFile(ctx, options, logChannel, colorID)
 	case define.KubernetesLogging, "":
 		return c.readFromLogFile(ctx, options, logChannel, colorID)
 	default:
@@ -102,7 +178,7 @@ func (c *Container) readFromLogFile(ctx context.Context, options *logs.LogOption
 					return
 				}
 			}
-			nll, err := logs.NewLogLine(line.Text)
+			nll, err := logs.ParseLogLine(line.Text)
 			if err != nil {
 				logrus.Errorf("Getting new log line: %v", err)
 				continue
@@ -153,6 +229,7 @@ func (c *Container) readFromLogFile(ctx context.Context, options *logs.LogOption
 			// Now wait for the died event and signal to finish
 			// reading the log until EOF.
 			<-eventChannel
+			c.waitForLogForwarder()
 			// Make sure to wait at least for the poll duration
 			// before stopping the file logger (see #10675).
 			time.Sleep(watch.POLL_DURATION)
@@ -164,3 +241,45 @@ func (c *Container) readFromLogFile(ctx context.Context, options *logs.LogOption
 	}
 	return nil
 }
+
+// logFifoPath returns the path of the FIFO to which conmon writes the log
+// read by the log forwarder.
+func (c *Container) logFifoPath() string {
+	return filepath.Join(c.state.RunDir, "ctr.log.fifo")
+}
+
+// logFormatDriver returns the log driver determining the format of the
+// container's log.  Containers created while json-file was an alias of
+// k8s-file keep writing the k8s-file format, so that their log is not mixed.
+func (c *Container) logFormatDriver() string {
+	if c.config.LogDriver == define.JSONLogging && !c.config.JSONFileLogFormat {
+		return define.KubernetesLogging
+	}
+	return c.config.LogDriver
+}
+
+// usesLogForwarder returns true if conmon writes the log of the driver to the
+// FIFO of a log forwarder.
+func usesLogForwarder(driver string) bool {
+	switch driver {
+	case define.JSONLogging, define.SyslogLogging, define.FluentdLogging:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitForLogForwarder waits for the log forwarder of a stopped container to
+// write the remaining log, so that it can be read completely.
+func (c *Container) waitForLogForwarder() {
+	if !usesLogForwarder(c.logFormatDriver()) {
+		return
+	}
+	state, err := c.State()
+	if err != nil || (state != define.ContainerStateStopped && state != define.ContainerStateExited) || c.state.RunDir == "" {
+		return
+	}
+	if err := logs.WaitForwarder(c.logFifoPath(), logForwarderTimeout); err != nil {
+		logrus.Warnf("Reading log of container %s: %v", c.ID(), err)
+	}
+}
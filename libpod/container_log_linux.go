@@ -193,15 +193,9 @@ func (c *Container) readFromJournal(ctx context.Context, options *logs.LogOption
 				logrus.Errorf("Failed parse journal entry: %v", err)
 				return
 			}
-			id := c.ID()
-			if len(id) > 12 {
-				id = id[:12]
-			}
-			logLine.CID = id
+			logLine.CID = c.ID()
+			logLine.CName = c.Name()
 			logLine.ColorID = colorID
-			if options.UseName {
-				logLine.CName = c.Name()
-			}
 			if doTail {
 				tailQueue = append(tailQueue, logLine)
 				continue
@@ -149,7 +149,7 @@ func (c *Container) validate() error {
 		// TODO: we cannot reference pkg/autoupdate here due to
 		// circular dependencies.  It's worth considering moving the
 		// auto-update logic into the libpod package.
-		if value == "registry" || value == "image" {
+		if value == "registry" || value == "image" || value == "semver" {
 			if err := validateAutoUpdateImageReference(c.config.RawImageName); err != nil {
 				return err
 			}
@@ -1,5 +1,7 @@
 package define
 
+import "time"
+
 // AutoUpdateLabel denotes the container/pod label key to specify auto-update
 // policies in container labels.
 const AutoUpdateLabel = "io.containers.autoupdate"
@@ -7,3 +9,38 @@ const AutoUpdateLabel = "io.containers.autoupdate"
 // AutoUpdateAuthfileLabel denotes the container label key to specify authfile
 // in container labels.
 const AutoUpdateAuthfileLabel = "io.containers.autoupdate.authfile"
+
+// AutoUpdateSemverLabel denotes the container label key to specify the semver
+// constraint (e.g., "~1.4") of the semver auto-update policy.
+const AutoUpdateSemverLabel = "io.containers.autoupdate.semver"
+
+// AutoUpdateHealthTimeoutLabel denotes the container label key to specify the
+// time auto-update waits for an updated container to turn healthy before
+// rolling it back.
+const AutoUpdateHealthTimeoutLabel = "io.containers.autoupdate.health-timeout"
+
+// AutoUpdateRecord describes an update of a container performed by
+// auto-update.  Records are stored in the database to list past updates and
+// to allow for rolling them back.
+type AutoUpdateRecord struct {
+	// ContainerID is the ID of the container before the update.
+	ContainerID string `json:"containerID"`
+	// ContainerName is the name of the container.
+	ContainerName string `json:"containerName"`
+	// SystemdUnit is the systemd unit running the container.  It is
+	// empty if the container does not run in a systemd unit.
+	SystemdUnit string `json:"systemdUnit,omitempty"`
+	// ImageName is the raw image name of the container.
+	ImageName string `json:"imageName"`
+	// OldImageID is the ID of the image before the update.
+	OldImageID string `json:"oldImageID"`
+	// NewImageID is the ID of the image after the update.
+	NewImageID string `json:"newImageID"`
+	// Time is the time of the update.
+	Time time.Time `json:"time"`
+	// Result is the status of the update (e.g., "true" or "rolled back").
+	Result string `json:"result"`
+	// ProtectedUntil is the time until which the old image is protected
+	// from being pruned to allow for rolling back the update.
+	ProtectedUntil time.Time `json:"protectedUntil"`
+}
@@ -76,6 +76,12 @@ const KubernetesLogging = "k8s-file"
 // JSONLogging is the string conmon expects when specifying to use the json logging format
 const JSONLogging = "json-file"
 
+// SyslogLogging is the log driver sending the log to a syslog server
+const SyslogLogging = "syslog"
+
+// FluentdLogging is the log driver sending the log to a fluentd server
+const FluentdLogging = "fluentd"
+
 // NoLogging is the string conmon expects when specifying to use no log driver whatsoever
 const NoLogging = "none"
 
@@ -57,6 +57,16 @@ type InspectContainerConfig struct {
 	Healthcheck *manifest.Schema2HealthConfig `json:"Healthcheck,omitempty"`
 	// HealthcheckOnFailureAction defines an action to take once the container turns unhealthy.
 	HealthcheckOnFailureAction string `json:"HealthcheckOnFailureAction,omitempty"`
+	// HealthLogDestination is the destination of the healthcheck log.
+	HealthLogDestination string `json:"HealthLogDestination,omitempty"`
+	// HealthMaxLogCount is the maximum number of entries of the
+	// healthcheck log file.  0 means unlimited.
+	HealthMaxLogCount uint `json:"HealthMaxLogCount,omitempty"`
+	// HealthMaxLogSize is the maximum number of bytes of the output of a
+	// healthcheck stored in the log.  0 means unlimited.
+	HealthMaxLogSize uint `json:"HealthMaxLogSize,omitempty"`
+	// Configured readiness check for the container
+	ReadinessCheck *ReadinessCheck `json:"ReadinessCheck,omitempty"`
 	// CreateCommand is the full command plus arguments of the process the
 	// container has been created with.
 	CreateCommand []string `json:"CreateCommand,omitempty"`
@@ -220,6 +230,7 @@ type InspectContainerState struct {
 	StartedAt      time.Time          `json:"StartedAt"`
 	FinishedAt     time.Time          `json:"FinishedAt"`
 	Health         HealthCheckResults `json:"Health,omitempty"`
+	Ready          bool               `json:"Ready"`
 	Checkpointed   bool               `json:"Checkpointed,omitempty"`
 	CgroupPath     string             `json:"CgroupPath,omitempty"`
 	CheckpointedAt time.Time          `json:"CheckpointedAt,omitempty"`
@@ -18,6 +18,15 @@ const (
 	HealthCheckStarting string = "starting"
 )
 
+const (
+	// ReadinessCheckReady describes a container which passed its readiness
+	// check
+	ReadinessCheckReady string = "ready"
+	// ReadinessCheckNotReady describes a container which has not (yet)
+	// passed its readiness check or failed it since
+	ReadinessCheckNotReady string = "not ready"
+)
+
 // HealthCheckStatus represents the current state of a container
 type HealthCheckStatus int
 
@@ -137,6 +146,47 @@ func ParseHealthCheckOnFailureAction(s string) (HealthCheckOnFailureAction, erro
 	}
 }
 
+// Destinations of the healthcheck log.
+const (
+	// HealthCheckLogDestinationFile writes the log to a file in the
+	// container's run directory.  It is shown by inspect.
+	HealthCheckLogDestinationFile = "file"
+	// HealthCheckLogDestinationJournald writes the log to journald.
+	HealthCheckLogDestinationJournald = "journald"
+	// HealthCheckLogDestinationEvents writes the log as health_status
+	// events.
+	HealthCheckLogDestinationEvents = "events"
+)
+
+// Defaults of the healthcheck log.
+const (
+	// DefaultHealthMaxLogCount is the default number of healthcheck log
+	// entries kept in the log file.
+	DefaultHealthMaxLogCount uint = 5
+	// DefaultHealthMaxLogSize is the default number of bytes of the output
+	// of a healthcheck stored in the log.
+	DefaultHealthMaxLogSize uint = 500
+)
+
+// SupportedHealthCheckLogDestinations lists all supported destinations of the
+// healthcheck log.
+var SupportedHealthCheckLogDestinations = []string{
+	HealthCheckLogDestinationFile,
+	HealthCheckLogDestinationJournald,
+	HealthCheckLogDestinationEvents,
+}
+
+// ValidateHealthCheckLogDestination returns an error if the specified
+// destination of the healthcheck log is not supported.
+func ValidateHealthCheckLogDestination(destination string) error {
+	switch destination {
+	case HealthCheckLogDestinationFile, HealthCheckLogDestinationJournald, HealthCheckLogDestinationEvents:
+		return nil
+	default:
+		return fmt.Errorf("invalid healthcheck log destination %q: supported destinations are %s: %w", destination, strings.Join(SupportedHealthCheckLogDestinations, ","), ErrInvalidArg)
+	}
+}
+
 // StartupHealthCheck is the configuration of a startup healthcheck.
 type StartupHealthCheck struct {
 	manifest.Schema2HealthConfig
@@ -145,3 +195,15 @@ type StartupHealthCheck struct {
 	// If set to 0, a single success will mark the HC as passed.
 	Successes int `json:",omitempty"`
 }
+
+// ReadinessCheck is the configuration of a readiness check.  In contrast to
+// healthchecks, readiness checks do not trigger any action but only mark the
+// container as ready or not ready.  The Retries of the config are the number
+// of consecutive failures which mark a ready container as not ready.
+type ReadinessCheck struct {
+	manifest.Schema2HealthConfig
+	// Successes are the number of consecutive successes required to mark
+	// the container as ready.
+	// If set to 0, a single success will mark the container as ready.
+	Successes int `json:",omitempty"`
+}
@@ -0,0 +1,156 @@
+package define
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HealthConfig.Test options of health probes
+const (
+	// HealthConfigTestHTTPGet performs an HTTP GET request
+	HealthConfigTestHTTPGet = "HTTP-GET"
+	// HealthConfigTestTCP opens a TCP connection
+	HealthConfigTestTCP = "TCP"
+	// HealthConfigTestGRPC performs a call of the gRPC health checking protocol
+	HealthConfigTestGRPC = "GRPC"
+)
+
+// Option prefixes of health probes in HealthConfig.Test.
+const (
+	healthProbeStatusPrefix  = "status="
+	healthProbeHeaderPrefix  = "header="
+	healthProbeServicePrefix = "service="
+)
+
+// HealthProbe is a health check performed by Podman itself in the network
+// namespace of the container.  In contrast to health-check commands, probes
+// do not require any tools (e.g., curl or nc) in the container's image.
+//
+// Probes are stored in the Test field of the health-check config, for
+// instance, ["HTTP-GET", "http://localhost:8080/healthz", "status=200"].
+type HealthProbe struct {
+	// Type of the probe: HealthConfigTestHTTPGet, HealthConfigTestTCP or
+	// HealthConfigTestGRPC.
+	Type string
+	// Address to probe: a URL for HTTP probes, HOST:PORT otherwise.
+	Address string
+	// Status is the expected status code of HTTP probes.  If 0, any
+	// status code between 200 and 399 is considered successful.
+	Status int
+	// Headers of the HTTP request in the "NAME: VALUE" format.
+	Headers []string
+	// Service to check via gRPC.  If empty, the overall health of the
+	// server is checked.
+	Service string
+}
+
+// IsHealthProbe returns whether the specified health-check test is a probe.
+func IsHealthProbe(test []string) bool {
+	if len(test) == 0 {
+		return false
+	}
+	switch test[0] {
+	case HealthConfigTestHTTPGet, HealthConfigTestTCP, HealthConfigTestGRPC:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewHealthProbe returns a probe of the specified type.  The address of TCP
+// and gRPC probes may be a port only, in which case localhost is probed.
+func NewHealthProbe(probeType, address string) (*HealthProbe, error) {
+	probe := &HealthProbe{Type: probeType, Address: address}
+	if probeType != HealthConfigTestHTTPGet {
+		if _, err := strconv.ParseUint(address, 10, 16); err == nil {
+			probe.Address = net.JoinHostPort("localhost", address)
+		}
+	}
+	if err := probe.Validate(); err != nil {
+		return nil, err
+	}
+	return probe, nil
+}
+
+// ParseHealthProbe parses the probe of the specified health-check test.
+func ParseHealthProbe(test []string) (*HealthProbe, error) {
+	if !IsHealthProbe(test) || len(test) < 2 {
+		return nil, fmt.Errorf("invalid health probe %q: %w", test, ErrInvalidArg)
+	}
+	probe := &HealthProbe{Type: test[0], Address: test[1]}
+	for _, option := range test[2:] {
+		switch {
+		case strings.HasPrefix(option, healthProbeStatusPrefix):
+			status, err := strconv.Atoi(strings.TrimPrefix(option, healthProbeStatusPrefix))
+			if err != nil {
+				return nil, fmt.Errorf("invalid status of health probe: %w", err)
+			}
+			probe.Status = status
+		case strings.HasPrefix(option, healthProbeHeaderPrefix):
+			probe.Headers = append(probe.Headers, strings.TrimPrefix(option, healthProbeHeaderPrefix))
+		case strings.HasPrefix(option, healthProbeServicePrefix):
+			probe.Service = strings.TrimPrefix(option, healthProbeServicePrefix)
+		default:
+			return nil, fmt.Errorf("invalid option %q of health probe: %w", option, ErrInvalidArg)
+		}
+	}
+	if err := probe.Validate(); err != nil {
+		return nil, err
+	}
+	return probe, nil
+}
+
+// Validate returns an error if the probe is invalid.
+func (p *HealthProbe) Validate() error {
+	switch p.Type {
+	case HealthConfigTestHTTPGet:
+		u, err := url.Parse(p.Address)
+		if err != nil {
+			return fmt.Errorf("invalid URL of HTTP health probe: %w", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid URL %q of HTTP health probe: must be an absolute http or https URL: %w", p.Address, ErrInvalidArg)
+		}
+		if p.Status != 0 && (p.Status < 100 || p.Status > 599) {
+			return fmt.Errorf("invalid status %d of HTTP health probe: %w", p.Status, ErrInvalidArg)
+		}
+		for _, header := range p.Headers {
+			if name, _, ok := strings.Cut(header, ":"); !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("invalid header %q of HTTP health probe: must be in the NAME:VALUE format: %w", header, ErrInvalidArg)
+			}
+		}
+	case HealthConfigTestTCP, HealthConfigTestGRPC:
+		if _, _, err := net.SplitHostPort(p.Address); err != nil {
+			return fmt.Errorf("invalid address of %s health probe: %w", p.Type, err)
+		}
+	default:
+		return fmt.Errorf("invalid health probe type %q: %w", p.Type, ErrInvalidArg)
+	}
+
+	if p.Type != HealthConfigTestHTTPGet && (p.Status != 0 || len(p.Headers) > 0) {
+		return fmt.Errorf("status and headers are only supported by HTTP health probes: %w", ErrInvalidArg)
+	}
+	if p.Type != HealthConfigTestGRPC && p.Service != "" {
+		return fmt.Errorf("services are only supported by gRPC health probes: %w", ErrInvalidArg)
+	}
+	return nil
+}
+
+// Test returns the representation of the probe in the Test field of a
+// health-check config.
+func (p *HealthProbe) Test() []string {
+	test := []string{p.Type, p.Address}
+	if p.Status != 0 {
+		test = append(test, healthProbeStatusPrefix+strconv.Itoa(p.Status))
+	}
+	for _, header := range p.Headers {
+		test = append(test, healthProbeHeaderPrefix+header)
+	}
+	if p.Service != "" {
+		test = append(test, healthProbeServicePrefix+p.Service)
+	}
+	return test
+}
@@ -5,23 +5,60 @@ import (
 	"fmt"
 	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/containers/podman/v4/libpod/define"
 	"github.com/containers/podman/v4/libpod/events"
 	"github.com/sirupsen/logrus"
 )
 
+// eventSinksShutdownTimeout is the maximum time to wait for pending events
+// to be forwarded to the event sinks when the runtime is shut down.
+const eventSinksShutdownTimeout = 5 * time.Second
+
 // newEventer returns an eventer that can be used to read/write events
 func (r *Runtime) newEventer() (events.Eventer, error) {
 	if r.config.Engine.EventsLogFilePath == "" {
 		// default, use path under tmpdir when none was explicitly set by the user
 		r.config.Engine.EventsLogFilePath = filepath.Join(r.config.Engine.TmpDir, "events", "events.log")
 	}
+	var maxAge time.Duration
+	if r.config.Engine.EventsMaxAge != "" {
+		var err error
+		maxAge, err = time.ParseDuration(r.config.Engine.EventsMaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid events_max_age %q: %w", r.config.Engine.EventsMaxAge, err)
+		}
+	}
+	dbDir := r.config.Engine.StaticDir
+	if r.storageConfig.TransientStore {
+		dbDir = r.config.Engine.TmpDir
+	}
 	options := events.EventerOptions{
 		EventerType:    r.config.Engine.EventsLogger,
 		LogFilePath:    r.config.Engine.EventsLogFilePath,
 		LogFileMaxSize: r.config.Engine.EventsLogMaxSize(),
+		DBPath:         filepath.Join(dbDir, "events.db"),
+		MaxAge:         maxAge,
+	}
+	eventer, err := events.NewEventer(options)
+	if err != nil {
+		return nil, err
 	}
-	return events.NewEventer(options)
+
+	if len(r.config.Engine.EventsSinks) == 0 {
+		return eventer, nil
+	}
+	sinks := make([]events.SinkConfig, 0, len(r.config.Engine.EventsSinks))
+	for _, sink := range r.config.Engine.EventsSinks {
+		sinks = append(sinks, events.SinkConfig(sink))
+	}
+	forwarder, err := events.NewForwardingEventer(eventer, sinks)
+	if err != nil {
+		return nil, fmt.Errorf("setting up event sinks: %w", err)
+	}
+	r.eventForwarder = forwarder
+	return forwarder, nil
 }
 
 // newContainerEvent creates a new event based on a container
@@ -77,6 +114,31 @@ func (c *Container) newContainerEventWithInspectData(status events.Status, inspe
 	return c.runtime.eventer.Write(e)
 }
 
+// newContainerHealthLogEvent creates a new health_status event including the
+// specified healthcheck result
+func (c *Container) newContainerHealthLogEvent(hcl define.HealthCheckLog, status string) error {
+	rawLog, err := json.Marshal(hcl)
+	if err != nil {
+		return err
+	}
+
+	e := events.NewEvent(events.HealthStatus)
+	e.ID = c.ID()
+	e.Name = c.Name()
+	e.Image = c.config.RootfsImageName
+	e.Type = events.Container
+	e.HealthStatus = status
+	e.HealthLog = string(rawLog)
+
+	e.Details = events.Details{
+		ID:         e.ID,
+		PodID:      c.PodID(),
+		Attributes: c.Labels(),
+	}
+
+	return c.runtime.eventer.Write(e)
+}
+
 // newContainerExitedEvent creates a new event for a container's death
 func (c *Container) newContainerExitedEvent(exitCode int32) {
 	e := events.NewEvent(events.Exited)
@@ -97,6 +159,30 @@ func (c *Container) newContainerExitedEvent(exitCode int32) {
 	}
 }
 
+// newContainerOOMEvent creates a new event for a container killed because it
+// ran out of memory.  The event includes the memory limit and usage of the
+// container at the time it was killed.
+func (c *Container) newContainerOOMEvent() {
+	e := events.NewEvent(events.OOM)
+	e.ID = c.ID()
+	e.Name = c.Name()
+	e.Image = c.config.RootfsImageName
+	e.Type = events.Container
+	e.PodID = c.PodID()
+	if resources := c.LinuxResources(); resources != nil && resources.Memory != nil && resources.Memory.Limit != nil && *resources.Memory.Limit > 0 {
+		e.MemoryLimit = uint64(*resources.Memory.Limit)
+	}
+
+	e.Details = events.Details{
+		ID:         e.ID,
+		Attributes: c.Labels(),
+	}
+
+	if err := c.runtime.eventer.Write(e); err != nil {
+		logrus.Errorf("Unable to write container oom event: %q", err)
+	}
+}
+
 // newExecDiedEvent creates a new event for an exec session's death
 func (c *Container) newExecDiedEvent(sessionID string, exitCode int) {
 	e := events.NewEvent(events.ExecDied)
@@ -151,6 +237,46 @@ func (r *Runtime) NewSystemEvent(status events.Status) {
 	}
 }
 
+// NewAutoUpdateEvent creates a new auto-update event for the specified
+// container.  The attributes describe the update (e.g., the old and new tag of
+// the image).
+func (r *Runtime) NewAutoUpdateEvent(ctr *Container, image string, attributes map[string]string) {
+	e := events.NewEvent(events.AutoUpdate)
+	e.Type = events.System
+	e.ID = ctr.ID()
+	e.Name = ctr.Name()
+	e.Image = image
+	e.Attributes = attributes
+
+	if err := r.eventer.Write(e); err != nil {
+		logrus.Errorf("Unable to write auto-update event: %q", err)
+	}
+}
+
+// NewSecretEvent creates a new event for the secret with the specified ID
+// and name.  The attributes describe the event further (e.g., the container
+// using the secret).
+func (r *Runtime) NewSecretEvent(status events.Status, id, name string, attributes map[string]string) {
+	e := events.NewEvent(status)
+	e.Type = events.Secret
+	e.ID = id
+	e.Name = name
+	e.Attributes = attributes
+
+	if err := r.eventer.Write(e); err != nil {
+		logrus.Errorf("Unable to write secret event: %q", err)
+	}
+}
+
+// newSecretUseEvent creates a new event for the container using the data of
+// the secret.
+func (c *Container) newSecretUseEvent(secretID, secretName string) {
+	c.runtime.NewSecretEvent(events.Use, secretID, secretName, map[string]string{
+		"container":     c.ID(),
+		"containerName": c.Name(),
+	})
+}
+
 // newVolumeEvent creates a new event for a libpod volume
 func (v *Volume) newVolumeEvent(status events.Status) {
 	e := events.NewEvent(status)
@@ -18,6 +18,8 @@ const (
 	Null EventerType = iota
 	// Memory indicates the event logger will hold events in memory
 	Memory EventerType = iota
+	// SQLite indicates the event logger will be a SQLite database
+	SQLite EventerType = iota
 )
 
 // Event describes the attributes of a libpod event
@@ -41,6 +43,12 @@ type Event struct {
 	Type Type
 	// Health status of the current container
 	HealthStatus string `json:"health_status,omitempty"`
+	// HealthLog is the JSON-encoded result of a healthcheck if the
+	// container's healthcheck log is written as events
+	HealthLog string `json:"health_log,omitempty"`
+	// MemoryLimit is the memory limit in bytes of a container killed
+	// because it ran out of memory
+	MemoryLimit uint64 `json:"memory_limit,omitempty"`
 
 	Details
 }
@@ -70,7 +78,13 @@ type EventerOptions struct {
 	// the file logger
 	LogFilePath string
 	// LogFileMaxSize is the default limit used for rotating the log file
+	// or, if using the sqlite logger, for pruning the oldest events
 	LogFileMaxSize uint64
+	// DBPath is the path to the database if using the sqlite logger
+	DBPath string
+	// MaxAge is the maximum age of events kept by the sqlite logger.  Events
+	// are kept regardless of their age if it is 0.
+	MaxAge time.Duration
 }
 
 // Eventer is the interface for journald or file event logging
@@ -97,6 +111,9 @@ type ReadOptions struct {
 	Stream bool
 	// Until reads "until" the given time
 	Until string
+	// Count limits the events read from the logs to the most recent ones.
+	// When streaming, new events are returned after them.
+	Count uint
 }
 
 // Type of event that occurred (container, volume, image, pod, etc)
@@ -116,6 +133,8 @@ const (
 	Network Type = "network"
 	// Pod - event is related to pods
 	Pod Type = "pod"
+	// Secret - event is related to secrets
+	Secret Type = "secret"
 	// System - event is related to Podman whole and not to any specific
 	// container/pod/image/volume
 	System Type = "system"
@@ -162,6 +181,9 @@ const (
 	LoadFromArchive Status = "loadfromarchive"
 	// Mount ...
 	Mount Status = "mount"
+	// OOM indicates that a container was killed because it ran out of
+	// memory.
+	OOM Status = "oom"
 	// NetworkConnect
 	NetworkConnect Status = "connect"
 	// NetworkDisconnect
@@ -206,6 +228,8 @@ const (
 	Unpause Status = "unpause"
 	// Untag ...
 	Untag Status = "untag"
+	// Use indicates that the data of a secret was used by a container.
+	Use Status = "use"
 )
 
 // EventFilter for filtering events
@@ -1,9 +1,11 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/containers/storage/pkg/stringid"
@@ -24,6 +26,8 @@ func (et EventerType) String() string {
 		return "memory"
 	case Null:
 		return "none"
+	case SQLite:
+		return "sqlite"
 	default:
 		return "invalid"
 	}
@@ -40,6 +44,8 @@ func IsValidEventer(eventer string) bool {
 		return true
 	case Null.String():
 		return true
+	case SQLite.String():
+		return true
 	default:
 		return false
 	}
@@ -83,6 +89,9 @@ func (e *Event) ToHumanReadable(truncate bool) string {
 		if e.HealthStatus != "" {
 			humanFormat += fmt.Sprintf(", health_status=%s", e.HealthStatus)
 		}
+		if e.Status == OOM {
+			humanFormat += fmt.Sprintf(", memory_limit=%d", e.MemoryLimit)
+		}
 		// check if the container has labels and add it to the output
 		if len(e.Attributes) > 0 {
 			for k, v := range e.Attributes {
@@ -95,11 +104,34 @@ func (e *Event) ToHumanReadable(truncate bool) string {
 	case Image:
 		humanFormat = fmt.Sprintf("%s %s %s %s %s", e.Time, e.Type, e.Status, id, e.Name)
 	case System:
-		if e.Name != "" {
+		if e.ID != "" {
+			// Per-container system events such as auto updates
+			humanFormat = fmt.Sprintf("%s %s %s %s (image=%s, name=%s", e.Time, e.Type, e.Status, id, e.Image, e.Name)
+			keys := make([]string, 0, len(e.Attributes))
+			for k := range e.Attributes {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				humanFormat += fmt.Sprintf(", %s=%s", k, e.Attributes[k])
+			}
+			humanFormat += ")"
+		} else if e.Name != "" {
 			humanFormat = fmt.Sprintf("%s %s %s %s", e.Time, e.Type, e.Status, e.Name)
 		} else {
 			humanFormat = fmt.Sprintf("%s %s %s", e.Time, e.Type, e.Status)
 		}
+	case Secret:
+		humanFormat = fmt.Sprintf("%s %s %s %s (name=%s", e.Time, e.Type, e.Status, id, e.Name)
+		keys := make([]string, 0, len(e.Attributes))
+		for k := range e.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			humanFormat += fmt.Sprintf(", %s=%s", k, e.Attributes[k])
+		}
+		humanFormat += ")"
 	case Volume, Machine:
 		humanFormat = fmt.Sprintf("%s %s %s %s", e.Time, e.Type, e.Status, e.Name)
 	}
@@ -139,6 +171,8 @@ func StringToType(name string) (Type, error) {
 		return Network, nil
 	case Pod.String():
 		return Pod, nil
+	case Secret.String():
+		return Secret, nil
 	case System.String():
 		return System, nil
 	case Volume.String():
@@ -188,6 +222,8 @@ func StringToStatus(name string) (Status, error) {
 		return LoadFromArchive, nil
 	case Mount.String():
 		return Mount, nil
+	case OOM.String():
+		return OOM, nil
 	case NetworkConnect.String():
 		return NetworkConnect, nil
 	case NetworkDisconnect.String():
@@ -230,6 +266,70 @@ func StringToStatus(name string) (Status, error) {
 		return Unpause, nil
 	case Untag.String():
 		return Untag, nil
+	case Use.String():
+		return Use, nil
 	}
 	return "", fmt.Errorf("unknown event status %q", name)
 }
+
+// readLatestEvents implements the Count option for eventers which cannot seek
+// backwards in their logs.  It reads all events matching the options, sends
+// the most recent ones to the event channel and, when streaming, follows the
+// events written after them.
+func readLatestEvents(ctx context.Context, eventer Eventer, options ReadOptions) error {
+	defer close(options.EventChannel)
+
+	history := options
+	history.Count = 0
+	history.FromStart = true
+	history.Stream = false
+	historyChannel := make(chan *Event)
+	history.EventChannel = historyChannel
+
+	readTime := time.Now()
+	errChannel := make(chan error, 1)
+	go func() {
+		errChannel <- eventer.Read(ctx, history)
+	}()
+	latest := make([]*Event, 0, options.Count)
+	for event := range historyChannel {
+		if uint(len(latest)) == options.Count {
+			latest = latest[1:]
+		}
+		latest = append(latest, event)
+	}
+	if err := <-errChannel; err != nil {
+		return err
+	}
+	for _, event := range latest {
+		select {
+		case options.EventChannel <- event:
+		case <-ctx.Done():
+			return nil
+		}
+		if event.Time.After(readTime) {
+			readTime = event.Time
+		}
+	}
+	if !options.Stream {
+		return nil
+	}
+
+	// Only follow the events written after the ones read above.
+	follow := options
+	follow.Count = 0
+	follow.FromStart = true
+	follow.Since = readTime.Format(time.RFC3339Nano)
+	followChannel := make(chan *Event)
+	follow.EventChannel = followChannel
+	go func() {
+		errChannel <- eventer.Read(ctx, follow)
+	}()
+	for event := range followChannel {
+		select {
+		case options.EventChannel <- event:
+		case <-ctx.Done():
+		}
+	}
+	return <-errChannel
+}
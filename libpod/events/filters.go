@@ -47,6 +47,16 @@ func generateEventFilter(filter, filterValue string) (func(e *Event) bool, error
 			}
 			return strings.HasPrefix(e.ID, filterValue)
 		}, nil
+	case "SECRET":
+		return func(e *Event) bool {
+			if e.Type != Secret {
+				return false
+			}
+			if e.Name == filterValue {
+				return true
+			}
+			return strings.HasPrefix(e.ID, filterValue)
+		}, nil
 	case "VOLUME":
 		return func(e *Event) bool {
 			if e.Type != Volume {
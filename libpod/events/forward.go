@@ -0,0 +1,307 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SinkUnix forwards events to a unix stream socket.
+	SinkUnix = "unix"
+	// SinkUnixgram forwards events to a unix datagram socket.
+	SinkUnixgram = "unixgram"
+	// SinkHTTP forwards events to an HTTP endpoint via POST requests.
+	SinkHTTP = "http"
+	// SinkExec forwards events to a local command.
+	SinkExec = "exec"
+)
+
+const (
+	// defaultSinkTimeout is the default timeout for delivering an event to
+	// a sink.
+	defaultSinkTimeout = 5 * time.Second
+	// sinkQueueSize is the number of events which are queued per sink.
+	// Events are dropped if the queue is full.
+	sinkQueueSize = 256
+	// sinkInitialBackoff is the time to wait before the first retry of a
+	// failed HTTP delivery.  It is doubled with each retry.
+	sinkInitialBackoff = 500 * time.Millisecond
+)
+
+// SinkConfig describes a sink to which events are forwarded.
+type SinkConfig struct {
+	// Name of the sink, used in log messages.
+	Name string `toml:"name,omitempty"`
+	// Type of the sink: unix, unixgram, http or exec.
+	Type string `toml:"type,omitempty"`
+	// Address is the path of the socket for unix and unixgram sinks and
+	// the URL for http sinks.
+	Address string `toml:"address,omitempty"`
+	// Command to execute for each event for exec sinks.  The event is
+	// passed as JSON on stdin.
+	Command []string `toml:"command,omitempty"`
+	// Filters limit the events forwarded to the sink.  They have the same
+	// format as the filters of `podman events`.
+	Filters []string `toml:"filters,omitempty"`
+	// Retries is the number of times a failed delivery to an http sink is
+	// retried with an exponential backoff.
+	Retries uint `toml:"retries,omitempty"`
+	// Timeout for delivering an event to the sink (e.g., "5s").
+	Timeout string `toml:"timeout,omitempty"`
+}
+
+// String returns the name of the sink or, if it has none, its type and
+// address.
+func (c *SinkConfig) String() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	if c.Type == SinkExec {
+		return fmt.Sprintf("%s:%s", c.Type, strings.Join(c.Command, " "))
+	}
+	return fmt.Sprintf("%s:%s", c.Type, c.Address)
+}
+
+// sink delivers the events queued by the ForwardingEventer to a single
+// destination.
+type sink struct {
+	config  SinkConfig
+	filters map[string][]EventFilter
+	timeout time.Duration
+	queue   chan *Event
+	deliver func(payload []byte, event *Event) error
+	// stop is closed when the runtime shuts down.  Failed deliveries are
+	// not retried anymore then.
+	stop chan struct{}
+}
+
+// newSink validates the configuration of a sink and returns it.
+func newSink(config SinkConfig, stop chan struct{}) (*sink, error) {
+	filters, err := generateEventFilters(config.Filters, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid filters of event sink %s: %w", config.String(), err)
+	}
+	s := &sink{
+		config:  config,
+		filters: filters,
+		timeout: defaultSinkTimeout,
+		queue:   make(chan *Event, sinkQueueSize),
+		stop:    stop,
+	}
+	if config.Timeout != "" {
+		s.timeout, err = time.ParseDuration(config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout of event sink %s: %w", config.String(), err)
+		}
+	}
+
+	switch config.Type {
+	case SinkUnix, SinkUnixgram:
+		if config.Address == "" {
+			return nil, fmt.Errorf("event sink %s requires a socket address", config.String())
+		}
+		s.deliver = s.deliverSocket
+	case SinkHTTP:
+		if !strings.HasPrefix(config.Address, "http://") && !strings.HasPrefix(config.Address, "https://") {
+			return nil, fmt.Errorf("event sink %s requires an http or https URL", config.String())
+		}
+		s.deliver = s.deliverHTTP
+	case SinkExec:
+		if len(config.Command) == 0 {
+			return nil, fmt.Errorf("event sink %s requires a command", config.String())
+		}
+		s.deliver = s.deliverExec
+	default:
+		return nil, fmt.Errorf("unknown type %q of event sink %s", config.Type, config.String())
+	}
+	return s, nil
+}
+
+// run delivers the queued events until the queue is closed.
+func (s *sink) run() {
+	for event := range s.queue {
+		payload, err := event.ToJSONString()
+		if err != nil {
+			logrus.Errorf("Encoding event for sink %s: %v", s.config.String(), err)
+			continue
+		}
+		if err := s.deliver([]byte(payload), event); err != nil {
+			logrus.Warnf("Forwarding event to sink %s: %v", s.config.String(), err)
+		}
+	}
+}
+
+// deliverSocket writes the event to a unix socket.  Events sent to stream
+// sockets are terminated by a newline.
+func (s *sink) deliverSocket(payload []byte, event *Event) error {
+	conn, err := net.DialTimeout(s.config.Type, s.config.Address, s.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+		return err
+	}
+	if s.config.Type == SinkUnix {
+		payload = append(payload, '\n')
+	}
+	_, err = conn.Write(payload)
+	return err
+}
+
+// deliverHTTP posts the event to the URL of the sink.  Failed deliveries are
+// retried with an exponential backoff until the runtime shuts down.
+func (s *sink) deliverHTTP(payload []byte, event *Event) error {
+	backoff := sinkInitialBackoff
+	for attempt := uint(0); ; attempt++ {
+		err := s.postEvent(payload)
+		if err == nil || attempt >= s.config.Retries {
+			return err
+		}
+		logrus.Debugf("Forwarding event to sink %s failed, retrying in %s: %v", s.config.String(), backoff, err)
+		select {
+		case <-s.stop:
+			return fmt.Errorf("not retrying on shutdown: %w", err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// postEvent posts the event to the URL of the sink.
+func (s *sink) postEvent(payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.Address, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// deliverExec runs the command of the sink with the event as JSON on stdin.
+// The type, status, ID and name of the event are also passed as environment
+// variables.
+func (s *sink) deliverExec(payload []byte, event *Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, s.config.Command[0], s.config.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"PODMAN_EVENT_TYPE="+event.Type.String(),
+		"PODMAN_EVENT_STATUS="+event.Status.String(),
+		"PODMAN_EVENT_ID="+event.ID,
+		"PODMAN_EVENT_NAME="+event.Name,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// ForwardingEventer writes events to an eventer and forwards them to one or
+// more sinks.  Events are delivered to the sinks in the background, so that
+// slow or unreachable sinks do not delay the operations emitting the events.
+type ForwardingEventer struct {
+	Eventer
+	sinks  []*sink
+	stop   chan struct{}
+	wg     sync.WaitGroup
+	lock   sync.Mutex
+	closed bool
+}
+
+// NewForwardingEventer returns an eventer which writes events to the
+// specified eventer and forwards them to the configured sinks.
+func NewForwardingEventer(eventer Eventer, configs []SinkConfig) (*ForwardingEventer, error) {
+	f := &ForwardingEventer{Eventer: eventer, stop: make(chan struct{})}
+	for _, config := range configs {
+		s, err := newSink(config, f.stop)
+		if err != nil {
+			return nil, err
+		}
+		f.sinks = append(f.sinks, s)
+	}
+	for _, s := range f.sinks {
+		f.wg.Add(1)
+		go func(s *sink) {
+			defer f.wg.Done()
+			s.run()
+		}(s)
+	}
+	return f, nil
+}
+
+// Write writes the event to the underlying eventer and queues it for all
+// sinks whose filters match.  Events are dropped if the queue of a sink is
+// full.
+func (f *ForwardingEventer) Write(event Event) error {
+	err := f.Eventer.Write(event)
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.closed {
+		return err
+	}
+	for _, s := range f.sinks {
+		if !applyFilters(&event, s.filters) {
+			continue
+		}
+		e := event
+		select {
+		case s.queue <- &e:
+		default:
+			logrus.Warnf("Dropping %s event: queue of event sink %s is full", event.Status, s.config.String())
+		}
+	}
+	return err
+}
+
+// Close stops accepting new events and waits for the queued events to be
+// delivered.  Failed deliveries are not retried anymore, so that an
+// unreachable sink does not delay the shutdown.  Close gives up after the
+// timeout and returns an error if events may have been lost.
+func (f *ForwardingEventer) Close(timeout time.Duration) error {
+	f.lock.Lock()
+	if !f.closed {
+		f.closed = true
+		close(f.stop)
+		for _, s := range f.sinks {
+			close(s.queue)
+		}
+	}
+	f.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return errors.New("timed out waiting for events to be forwarded to sinks")
+	}
+}
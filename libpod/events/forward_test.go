@@ -0,0 +1,134 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardingEventer(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		received []Event
+		attempts int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		attempts++
+		// Fail the first attempt to exercise the retries.
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var e Event
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(body, &e))
+		received = append(received, e)
+	}))
+	defer server.Close()
+
+	socketPath := filepath.Join(t.TempDir(), "events.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	execOutput := filepath.Join(t.TempDir(), "exec")
+
+	forwarder, err := NewForwardingEventer(newNullEventer(), []SinkConfig{
+		{Type: SinkHTTP, Address: server.URL, Retries: 2, Filters: []string{"event=start"}},
+		{Type: SinkUnixgram, Address: socketPath, Filters: []string{"type=container"}},
+		{Type: SinkExec, Command: []string{"sh", "-c", "echo $PODMAN_EVENT_STATUS >> " + execOutput}},
+	})
+	require.NoError(t, err)
+
+	for _, status := range []Status{Create, Start} {
+		e := NewEvent(status)
+		e.Type = Container
+		e.ID = "abc"
+		require.NoError(t, forwarder.Write(e))
+	}
+	imageEvent := NewEvent(Pull)
+	imageEvent.Type = Image
+	require.NoError(t, forwarder.Write(imageEvent))
+	// Failed deliveries are not retried on shutdown, so wait for the
+	// retry before closing.
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(received) == 1
+	}, 10*time.Second, 50*time.Millisecond)
+	require.NoError(t, forwarder.Close(10*time.Second))
+
+	lock.Lock()
+	assert.Equal(t, 2, attempts)
+	require.Len(t, received, 1)
+	assert.Equal(t, Start, received[0].Status)
+	lock.Unlock()
+
+	buf := make([]byte, 4096)
+	for _, status := range []Status{Create, Start} {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+		var e Event
+		require.NoError(t, json.Unmarshal(buf[:n], &e))
+		assert.Equal(t, status, e.Status)
+	}
+
+	output, err := os.ReadFile(execOutput)
+	require.NoError(t, err)
+	assert.Equal(t, "create\nstart\npull\n", string(output))
+
+	// Events written after closing are not forwarded anymore.
+	assert.NoError(t, forwarder.Write(NewEvent(Remove)))
+}
+
+func TestNewSinkValidation(t *testing.T) {
+	for _, config := range []SinkConfig{
+		{Type: "bogus", Address: "/run/events.sock"},
+		{Type: SinkUnix},
+		{Type: SinkHTTP, Address: "localhost:8080"},
+		{Type: SinkExec},
+		{Type: SinkExec, Command: []string{"true"}, Filters: []string{"invalid"}},
+		{Type: SinkExec, Command: []string{"true"}, Timeout: "forever"},
+	} {
+		_, err := newSink(config, nil)
+		assert.Error(t, err, config.String())
+	}
+}
+
+func TestForwardingEventerCloseSkipsRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	forwarder, err := NewForwardingEventer(newNullEventer(), []SinkConfig{
+		{Type: SinkHTTP, Address: server.URL, Retries: 10},
+	})
+	require.NoError(t, err)
+	require.NoError(t, forwarder.Write(NewEvent(Start)))
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&attempts) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, forwarder.Close(10*time.Second))
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
@@ -64,6 +64,12 @@ func (e EventJournalD) Write(ee Event) error {
 			m["PODMAN_LABELS"] = string(b)
 		}
 		m["PODMAN_HEALTH_STATUS"] = ee.HealthStatus
+		if ee.HealthLog != "" {
+			m["PODMAN_HEALTH_LOG"] = ee.HealthLog
+		}
+		if ee.Status == OOM {
+			m["PODMAN_MEMORY_LIMIT"] = strconv.FormatUint(ee.MemoryLimit, 10)
+		}
 
 		if len(ee.Details.ContainerInspectData) > 0 {
 			m["PODMAN_CONTAINER_INSPECT_DATA"] = ee.Details.ContainerInspectData
@@ -73,12 +79,38 @@ func (e EventJournalD) Write(ee Event) error {
 		m["PODMAN_NETWORK_NAME"] = ee.Network
 	case Volume:
 		m["PODMAN_NAME"] = ee.Name
+	case Secret:
+		m["PODMAN_ID"] = ee.ID
+		m["PODMAN_NAME"] = ee.Name
+		if len(ee.Details.Attributes) > 0 {
+			b, err := json.Marshal(ee.Details.Attributes)
+			if err != nil {
+				return err
+			}
+			m["PODMAN_LABELS"] = string(b)
+		}
+	case System:
+		if ee.ID != "" {
+			m["PODMAN_ID"] = ee.ID
+			m["PODMAN_NAME"] = ee.Name
+			m["PODMAN_IMAGE"] = ee.Image
+		}
+		if len(ee.Details.Attributes) > 0 {
+			b, err := json.Marshal(ee.Details.Attributes)
+			if err != nil {
+				return err
+			}
+			m["PODMAN_LABELS"] = string(b)
+		}
 	}
 	return journal.Send(ee.ToHumanReadable(false), journal.PriInfo, m)
 }
 
 // Read reads events from the journal and sends qualified events to the event channel
 func (e EventJournalD) Read(ctx context.Context, options ReadOptions) error {
+	if options.Count > 0 {
+		return readLatestEvents(ctx, e, options)
+	}
 	defer close(options.EventChannel)
 	filterMap, err := generateEventFilters(options.Filters, options.Since, options.Until)
 	if err != nil {
@@ -211,12 +243,39 @@ func newEventFromJournalEntry(entry *sdjournal.JournalEntry) (*Event, error) {
 			}
 		}
 		newEvent.HealthStatus = entry.Fields["PODMAN_HEALTH_STATUS"]
+		newEvent.HealthLog = entry.Fields["PODMAN_HEALTH_LOG"]
+		if newEvent.Status == OOM {
+			newEvent.MemoryLimit, _ = strconv.ParseUint(entry.Fields["PODMAN_MEMORY_LIMIT"], 10, 64)
+		}
 		newEvent.Details.ContainerInspectData = entry.Fields["PODMAN_CONTAINER_INSPECT_DATA"]
 	case Network:
 		newEvent.ID = entry.Fields["PODMAN_ID"]
 		newEvent.Network = entry.Fields["PODMAN_NETWORK_NAME"]
 	case Image:
 		newEvent.ID = entry.Fields["PODMAN_ID"]
+	case Secret:
+		newEvent.ID = entry.Fields["PODMAN_ID"]
+		if stringLabels, ok := entry.Fields["PODMAN_LABELS"]; ok && len(stringLabels) > 0 {
+			attributes := make(map[string]string)
+			if err := json.Unmarshal([]byte(stringLabels), &attributes); err != nil {
+				return nil, err
+			}
+			if len(attributes) > 0 {
+				newEvent.Attributes = attributes
+			}
+		}
+	case System:
+		newEvent.ID = entry.Fields["PODMAN_ID"]
+		newEvent.Image = entry.Fields["PODMAN_IMAGE"]
+		if stringLabels, ok := entry.Fields["PODMAN_LABELS"]; ok && len(stringLabels) > 0 {
+			attributes := make(map[string]string)
+			if err := json.Unmarshal([]byte(stringLabels), &attributes); err != nil {
+				return nil, err
+			}
+			if len(attributes) > 0 {
+				newEvent.Attributes = attributes
+			}
+		}
 	}
 	return &newEvent, nil
 }
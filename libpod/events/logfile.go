@@ -109,6 +109,9 @@ func (e EventLogFile) readRotateEvent(event *Event) (begin bool, end bool, err e
 
 // Reads from the log file
 func (e EventLogFile) Read(ctx context.Context, options ReadOptions) error {
+	if options.Count > 0 {
+		return readLatestEvents(ctx, e, options)
+	}
 	defer close(options.EventChannel)
 	filterMap, err := generateEventFilters(options.Filters, options.Since, options.Until)
 	if err != nil {
@@ -171,7 +174,7 @@ func (e EventLogFile) Read(ctx context.Context, options ReadOptions) error {
 			return err
 		}
 		switch event.Type {
-		case Image, Volume, Pod, Container, Network:
+		case Image, Volume, Pod, Container, Network, Secret:
 			//	no-op
 		case System:
 			begin, end, err := e.readRotateEvent(event)
@@ -0,0 +1,386 @@
+//go:build (linux || freebsd) && !remote
+// +build linux freebsd
+// +build !remote
+
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containers/podman/v4/pkg/util"
+	"github.com/sirupsen/logrus"
+
+	// SQLite backend for database/sql
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// Events are written by many concurrent Podman processes, so wait for
+	// the database to be unlocked instead of failing right away.  Write
+	// ahead logging allows for reading events while they are written.
+	sqliteEventsOptions = "?_loc=auto&_busy_timeout=10000&_journal_mode=WAL&_txlock=immediate"
+
+	// sqliteEventsPollInterval is the interval at which the database is
+	// queried for new events when streaming.
+	sqliteEventsPollInterval = 250 * time.Millisecond
+
+	sqliteEventsSchema = `
+        CREATE TABLE IF NOT EXISTS Event(
+                ID       INTEGER PRIMARY KEY AUTOINCREMENT,
+                Time     INTEGER NOT NULL,
+                Type     TEXT    NOT NULL,
+                Status   TEXT    NOT NULL,
+                ObjectID TEXT    NOT NULL,
+                Name     TEXT    NOT NULL,
+                Size     INTEGER NOT NULL,
+                JSON     TEXT    NOT NULL
+        );
+        CREATE INDEX IF NOT EXISTS EventTime ON Event(Time);
+        CREATE INDEX IF NOT EXISTS EventObject ON Event(Type, ObjectID);
+        CREATE TABLE IF NOT EXISTS EventSize(
+                ID   INTEGER PRIMARY KEY CHECK (ID = 0),
+                Size INTEGER NOT NULL
+        );
+        CREATE TRIGGER IF NOT EXISTS EventInsertSize AFTER INSERT ON Event BEGIN
+                UPDATE EventSize SET Size = Size + NEW.Size WHERE ID = 0;
+        END;
+        CREATE TRIGGER IF NOT EXISTS EventDeleteSize AFTER DELETE ON Event BEGIN
+                UPDATE EventSize SET Size = Size - OLD.Size WHERE ID = 0;
+        END;`
+)
+
+// EventSQLite is the structure for event writing to a SQLite database.  Events
+// are indexed by their time, type and ID, so that reading a time range or the
+// events of a container does not require decoding all stored events.  The
+// total size of the stored events is kept up to date by triggers in the
+// EventSize table, so that it is not computed on every write.
+type EventSQLite struct {
+	options EventerOptions
+	conn    *sql.DB
+}
+
+// newSQLiteEventer creates a new EventSQLite eventer
+func newSQLiteEventer(options EventerOptions) (*EventSQLite, error) {
+	if err := os.MkdirAll(filepath.Dir(options.DBPath), 0700); err != nil {
+		return nil, fmt.Errorf("creating events database dir: %w", err)
+	}
+	conn, err := sql.Open("sqlite3", options.DBPath+sqliteEventsOptions)
+	if err != nil {
+		return nil, fmt.Errorf("opening events database: %w", err)
+	}
+	if err := initSQLiteEvents(conn); err != nil {
+		if err := conn.Close(); err != nil {
+			logrus.Errorf("Closing events database: %v", err)
+		}
+		return nil, err
+	}
+	return &EventSQLite{options: options, conn: conn}, nil
+}
+
+// initSQLiteEvents creates the tables of the events database and initializes
+// the total size of the stored events.
+func initSQLiteEvents(conn *sql.DB) (defErr error) {
+	tx, err := conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if defErr != nil {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Rolling back transaction to create events tables: %v", err)
+			}
+		}
+	}()
+
+	if _, err := tx.Exec(sqliteEventsSchema); err != nil {
+		return fmt.Errorf("creating events tables: %w", err)
+	}
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM EventSize;").Scan(&count); err != nil {
+		return fmt.Errorf("querying size of events: %w", err)
+	}
+	if count == 0 {
+		// Account for the events stored before the size was tracked.
+		if _, err := tx.Exec("INSERT INTO EventSize(ID, Size) SELECT 0, COALESCE(SUM(Size), 0) FROM Event;"); err != nil {
+			return fmt.Errorf("initializing size of events: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing events tables: %w", err)
+	}
+	return nil
+}
+
+// Write stores the event in the database and prunes the events exceeding the
+// configured age and size.
+func (e *EventSQLite) Write(ee Event) (defErr error) {
+	eventJSONString, err := ee.ToJSONString()
+	if err != nil {
+		return err
+	}
+
+	tx, err := e.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if defErr != nil {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Rolling back transaction to write event: %v", err)
+			}
+		}
+	}()
+
+	if _, err := tx.Exec("INSERT INTO Event(Time, Type, Status, ObjectID, Name, Size, JSON) VALUES (?, ?, ?, ?, ?, ?, ?);",
+		ee.Time.UnixNano(), string(ee.Type), string(ee.Status), ee.ID, ee.Name, len(eventJSONString), eventJSONString); err != nil {
+		return fmt.Errorf("inserting event: %w", err)
+	}
+	if err := e.prune(tx, ee.Time); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing event: %w", err)
+	}
+	return nil
+}
+
+// prune removes the events older than the maximum age and, if the size of the
+// stored events exceeds the limit, the oldest events.  Like the file logger,
+// only the most recent events filling half of the limit are kept then, so
+// that the oldest events are only looked up once in a while.
+func (e *EventSQLite) prune(tx *sql.Tx, now time.Time) error {
+	if e.options.MaxAge > 0 {
+		if _, err := tx.Exec("DELETE FROM Event WHERE Time < ?;", now.Add(-e.options.MaxAge).UnixNano()); err != nil {
+			return fmt.Errorf("pruning events older than %s: %w", e.options.MaxAge, err)
+		}
+	}
+	if e.options.LogFileMaxSize == 0 {
+		return nil
+	}
+	var size uint64
+	if err := tx.QueryRow("SELECT Size FROM EventSize WHERE ID = 0;").Scan(&size); err != nil {
+		return fmt.Errorf("querying size of events: %w", err)
+	}
+	if size <= e.options.LogFileMaxSize {
+		return nil
+	}
+	if _, err := tx.Exec(`DELETE FROM Event WHERE ID IN (
+                SELECT ID FROM (SELECT ID, SUM(Size) OVER (ORDER BY ID DESC) AS Total FROM Event) WHERE Total > ?
+        );`, e.options.LogFileMaxSize/2); err != nil {
+		return fmt.Errorf("pruning events exceeding %d bytes: %w", e.options.LogFileMaxSize, err)
+	}
+	return nil
+}
+
+// Read reads the events matching the options from the database.  The time
+// range and the type, event and object filters are evaluated by the database;
+// all other filters are applied to the decoded events.
+func (e *EventSQLite) Read(ctx context.Context, options ReadOptions) error {
+	defer close(options.EventChannel)
+	filterMap, err := generateEventFilters(options.Filters, options.Since, options.Until)
+	if err != nil {
+		return fmt.Errorf("failed to parse event filters: %w", err)
+	}
+	where, args, err := sqliteEventsQuery(options)
+	if err != nil {
+		return err
+	}
+	var untilTime time.Time
+	if len(options.Until) > 0 {
+		untilTime, err = util.ParseInputTime(options.Until, false)
+		if err != nil {
+			return err
+		}
+	}
+	logrus.Debugf("Reading events from database %q", e.options.DBPath)
+
+	var lastID int64
+	switch {
+	case options.Count > 0:
+		lastID, err = e.readLatest(ctx, where, args, filterMap, options)
+	case options.Stream && !options.FromStart:
+		// Only return the events written from now on.
+		err = e.conn.QueryRowContext(ctx, "SELECT COALESCE(MAX(ID), 0) FROM Event;").Scan(&lastID)
+	default:
+		lastID, err = e.readSince(ctx, lastID, where, args, filterMap, options.EventChannel)
+	}
+	if err != nil || !options.Stream {
+		return ignoreCanceled(ctx, err)
+	}
+
+	ticker := time.NewTicker(sqliteEventsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if !untilTime.IsZero() && time.Now().After(untilTime) {
+			return nil
+		}
+		lastID, err = e.readSince(ctx, lastID, where, args, filterMap, options.EventChannel)
+		if err != nil {
+			return ignoreCanceled(ctx, err)
+		}
+	}
+}
+
+// readSince sends the matching events stored after the event with the
+// specified ID to the channel.  It returns the ID of the last event read.
+func (e *EventSQLite) readSince(ctx context.Context, lastID int64, where string, args []interface{}, filterMap map[string][]EventFilter, eventChannel chan *Event) (int64, error) {
+	rows, err := e.conn.QueryContext(ctx, "SELECT ID, JSON FROM Event WHERE ID > ?"+where+" ORDER BY ID;", append([]interface{}{lastID}, args...)...)
+	if err != nil {
+		return lastID, fmt.Errorf("querying events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var eventJSON string
+		if err := rows.Scan(&lastID, &eventJSON); err != nil {
+			return lastID, fmt.Errorf("scanning event row: %w", err)
+		}
+		event, err := newEventFromJSONString(eventJSON)
+		if err != nil {
+			return lastID, err
+		}
+		if !applyFilters(event, filterMap) {
+			continue
+		}
+		select {
+		case eventChannel <- event:
+		case <-ctx.Done():
+			return lastID, ctx.Err()
+		}
+	}
+	return lastID, rows.Err()
+}
+
+// readLatest sends the options.Count most recent matching events to the event
+// channel.  It returns the ID of the most recent event matching the query.
+func (e *EventSQLite) readLatest(ctx context.Context, where string, args []interface{}, filterMap map[string][]EventFilter, options ReadOptions) (int64, error) {
+	rows, err := e.conn.QueryContext(ctx, "SELECT ID, JSON FROM Event WHERE 1=1"+where+" ORDER BY ID DESC;", args...)
+	if err != nil {
+		return 0, fmt.Errorf("querying events: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		lastID int64
+		latest []*Event
+	)
+	for uint(len(latest)) < options.Count && rows.Next() {
+		var (
+			id        int64
+			eventJSON string
+		)
+		if err := rows.Scan(&id, &eventJSON); err != nil {
+			return 0, fmt.Errorf("scanning event row: %w", err)
+		}
+		if lastID == 0 {
+			lastID = id
+		}
+		event, err := newEventFromJSONString(eventJSON)
+		if err != nil {
+			return 0, err
+		}
+		if applyFilters(event, filterMap) {
+			latest = append(latest, event)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	for i := len(latest) - 1; i >= 0; i-- {
+		select {
+		case options.EventChannel <- latest[i]:
+		case <-ctx.Done():
+			return lastID, ctx.Err()
+		}
+	}
+	return lastID, nil
+}
+
+// sqliteEventsQuery translates the time range and the filters of the options
+// which can be evaluated by the database into a WHERE clause.  Filters are
+// only used to narrow down the events read from the database, so the clause
+// may match more events than the filters.
+func sqliteEventsQuery(options ReadOptions) (string, []interface{}, error) {
+	var (
+		where strings.Builder
+		args  []interface{}
+	)
+	if len(options.Since) > 0 {
+		since, err := util.ParseInputTime(options.Since, true)
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to convert since time of %s: %w", options.Since, err)
+		}
+		where.WriteString(" AND Time > ?")
+		args = append(args, since.UnixNano())
+	}
+	if len(options.Until) > 0 {
+		until, err := util.ParseInputTime(options.Until, false)
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to convert until time of %s: %w", options.Until, err)
+		}
+		where.WriteString(" AND Time < ?")
+		args = append(args, until.UnixNano())
+	}
+
+	values := make(map[string][]string)
+	for _, filter := range options.Filters {
+		key, value, err := parseFilter(filter)
+		if err != nil {
+			return "", nil, err
+		}
+		key = strings.ToUpper(key)
+		if key == "STATUS" {
+			key = "EVENT"
+		}
+		values[key] = append(values[key], value)
+	}
+	for key, vals := range values {
+		var conditions []string
+		for _, value := range vals {
+			switch key {
+			case "TYPE":
+				conditions = append(conditions, "Type = ?")
+				args = append(args, value)
+			case "EVENT":
+				if value == "die" { // Docker compat
+					value = "died"
+				}
+				conditions = append(conditions, "Status = ?")
+				args = append(args, value)
+			case "CONTAINER", "IMAGE", "POD", "SECRET":
+				conditions = append(conditions, "(Type = ? AND (Name = ? OR substr(ObjectID, 1, ?) = ?))")
+				args = append(args, strings.ToLower(key), value, len(value), value)
+			}
+		}
+		if len(conditions) > 0 {
+			where.WriteString(" AND (" + strings.Join(conditions, " OR ") + ")")
+		}
+	}
+	return where.String(), args, nil
+}
+
+// ignoreCanceled returns nil if the error was caused by the consumer canceling
+// the context.
+func ignoreCanceled(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return nil
+	}
+	return err
+}
+
+// String returns a string representation of the logger
+func (e *EventSQLite) String() string {
+	return SQLite.String()
+}
@@ -0,0 +1,13 @@
+//go:build (linux || freebsd) && remote
+// +build linux freebsd
+// +build remote
+
+package events
+
+import "errors"
+
+// newSQLiteEventer is not supported by the remote client which reads events
+// from the service.
+func newSQLiteEventer(options EventerOptions) (Eventer, error) {
+	return nil, errors.New("the sqlite events backend is not supported by the remote client")
+}
@@ -0,0 +1,135 @@
+//go:build (linux || freebsd) && !remote
+// +build linux freebsd
+// +build !remote
+
+package events
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readSQLiteEvents(t *testing.T, eventer Eventer, options ReadOptions) []*Event {
+	eventChannel := make(chan *Event)
+	options.EventChannel = eventChannel
+	errChannel := make(chan error, 1)
+	go func() {
+		errChannel <- eventer.Read(context.Background(), options)
+	}()
+	var read []*Event
+	for e := range eventChannel {
+		read = append(read, e)
+	}
+	require.NoError(t, <-errChannel)
+	return read
+}
+
+func TestSQLiteEventer(t *testing.T) {
+	eventer, err := newSQLiteEventer(EventerOptions{DBPath: filepath.Join(t.TempDir(), "events.db")})
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i, name := range []string{"first", "second", "third"} {
+		e := NewEvent(Start)
+		e.Type = Container
+		e.ID = name + "id"
+		e.Name = name
+		e.Time = start.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, eventer.Write(e))
+	}
+	image := NewEvent(Pull)
+	image.Type = Image
+	image.Name = "alpine"
+	image.Time = start.Add(3 * time.Minute)
+	require.NoError(t, eventer.Write(image))
+
+	names := func(events []*Event) []string {
+		var names []string
+		for _, e := range events {
+			names = append(names, e.Name)
+		}
+		return names
+	}
+
+	for _, test := range []struct {
+		options ReadOptions
+		names   []string
+	}{
+		{ReadOptions{}, []string{"first", "second", "third", "alpine"}},
+		{ReadOptions{Filters: []string{"type=container"}}, []string{"first", "second", "third"}},
+		{ReadOptions{Filters: []string{"container=sec", "container=third"}}, []string{"second", "third"}},
+		{ReadOptions{Filters: []string{"container=secondid", "event=start"}}, []string{"second"}},
+		{ReadOptions{Since: start.Add(30 * time.Second).Format(time.RFC3339Nano), Until: start.Add(150 * time.Second).Format(time.RFC3339Nano)}, []string{"second", "third"}},
+		{ReadOptions{Count: 2}, []string{"third", "alpine"}},
+		{ReadOptions{Count: 2, Filters: []string{"type=container"}}, []string{"second", "third"}},
+	} {
+		test.options.FromStart = true
+		assert.Equal(t, test.names, names(readSQLiteEvents(t, eventer, test.options)), "%+v", test.options)
+	}
+}
+
+func TestSQLiteEventerPrune(t *testing.T) {
+	eventer, err := newSQLiteEventer(EventerOptions{
+		DBPath:         filepath.Join(t.TempDir(), "events.db"),
+		LogFileMaxSize: 500,
+		MaxAge:         time.Hour,
+	})
+	require.NoError(t, err)
+
+	old := NewEvent(Create)
+	old.Type = Volume
+	old.Time = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, eventer.Write(old))
+
+	var size, stored int
+	for i := 0; i < 10; i++ {
+		e := NewEvent(Create)
+		e.Type = Volume
+		e.Name = "volume"
+		require.NoError(t, eventer.Write(e))
+		eventJSON, err := e.ToJSONString()
+		require.NoError(t, err)
+		size = len(eventJSON)
+		// Exceeding the limit keeps the events filling half of it.
+		stored++
+		if stored*size > 500 {
+			stored = 250 / size
+		}
+	}
+
+	read := readSQLiteEvents(t, eventer, ReadOptions{FromStart: true})
+	assert.Len(t, read, stored)
+	for _, e := range read {
+		assert.Equal(t, "volume", e.Name)
+	}
+
+	var total, sum int
+	require.NoError(t, eventer.conn.QueryRow("SELECT Size FROM EventSize;").Scan(&total))
+	require.NoError(t, eventer.conn.QueryRow("SELECT SUM(Size) FROM Event;").Scan(&sum))
+	assert.Equal(t, sum, total)
+}
+
+func TestSQLiteEventerSizeOfExistingEvents(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "events.db")
+	eventer, err := newSQLiteEventer(EventerOptions{DBPath: dbPath})
+	require.NoError(t, err)
+	require.NoError(t, eventer.Write(NewEvent(Create)))
+	// Databases written before the size was tracked have no EventSize row.
+	_, err = eventer.conn.Exec("DELETE FROM EventSize;")
+	require.NoError(t, err)
+	require.NoError(t, eventer.conn.Close())
+
+	eventer, err = newSQLiteEventer(EventerOptions{DBPath: dbPath})
+	require.NoError(t, err)
+	defer eventer.conn.Close()
+	var total, sum int
+	require.NoError(t, eventer.conn.QueryRow("SELECT Size FROM EventSize;").Scan(&total))
+	require.NoError(t, eventer.conn.QueryRow("SELECT SUM(Size) FROM Event;").Scan(&sum))
+	assert.Equal(t, sum, total)
+	assert.NotZero(t, total)
+}
@@ -2,9 +2,11 @@ package libpod
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -16,11 +18,12 @@ import (
 )
 
 const (
-	// MaxHealthCheckNumberLogs is the maximum number of attempts we keep
-	// in the healthcheck history file
-	MaxHealthCheckNumberLogs int = 5
-	// MaxHealthCheckLogLength in characters
-	MaxHealthCheckLogLength = 500
+	// MaxHealthCheckNumberLogs is the default maximum number of attempts
+	// we keep in the healthcheck history file
+	MaxHealthCheckNumberLogs = int(define.DefaultHealthMaxLogCount)
+	// MaxHealthCheckLogLength is the default maximum length of the output
+	// of a healthcheck in bytes
+	MaxHealthCheckLogLength = int(define.DefaultHealthMaxLogSize)
 )
 
 // HealthCheck verifies the state and validity of the healthcheck configuration
@@ -56,63 +59,24 @@ func (r *Runtime) HealthCheck(ctx context.Context, name string) (define.HealthCh
 
 func (c *Container) runHealthCheck(ctx context.Context, isStartup bool) (define.HealthCheckStatus, string, error) {
 	var (
-		newCommand    []string
 		returnCode    int
 		inStartPeriod bool
 	)
 	hcCommand := c.HealthCheckConfig().Test
+	timeout := c.HealthCheckConfig().Timeout
 	if isStartup {
 		logrus.Debugf("Running startup healthcheck for container %s", c.ID())
 		hcCommand = c.config.StartupHealthCheckConfig.Test
+		timeout = c.config.StartupHealthCheckConfig.Timeout
 	}
-	if len(hcCommand) < 1 {
-		return define.HealthCheckNotDefined, "", fmt.Errorf("container %s has no defined healthcheck", c.ID())
-	}
-	switch hcCommand[0] {
-	case "", define.HealthConfigTestNone:
-		return define.HealthCheckNotDefined, "", fmt.Errorf("container %s has no defined healthcheck", c.ID())
-	case define.HealthConfigTestCmd:
-		newCommand = hcCommand[1:]
-	case define.HealthConfigTestCmdShell:
-		// TODO: SHELL command from image not available in Container - use Docker default
-		newCommand = []string{"/bin/sh", "-c", strings.Join(hcCommand[1:], " ")}
-	default:
-		// command supplied on command line - pass as-is
-		newCommand = hcCommand
-	}
-	if len(newCommand) < 1 || newCommand[0] == "" {
-		return define.HealthCheckNotDefined, "", fmt.Errorf("container %s has no defined healthcheck", c.ID())
-	}
-	rPipe, wPipe, err := os.Pipe()
+	newCommand, probe, err := c.healthCheckCommand(hcCommand)
 	if err != nil {
-		return define.HealthCheckInternalError, "", fmt.Errorf("unable to create pipe for healthcheck session: %w", err)
+		return define.HealthCheckNotDefined, "", err
 	}
-	defer wPipe.Close()
-	defer rPipe.Close()
-
-	streams := new(define.AttachStreams)
-
-	streams.InputStream = bufio.NewReader(os.Stdin)
-	streams.OutputStream = wPipe
-	streams.ErrorStream = wPipe
-	streams.AttachOutput = true
-	streams.AttachError = true
-	streams.AttachInput = true
-
-	stdout := []string{}
-	go func() {
-		scanner := bufio.NewScanner(rPipe)
-		for scanner.Scan() {
-			stdout = append(stdout, scanner.Text())
-		}
-	}()
 
-	logrus.Debugf("executing health check command %s for %s", strings.Join(newCommand, " "), c.ID())
 	timeStart := time.Now()
 	hcResult := define.HealthCheckSuccess
-	config := new(ExecConfig)
-	config.Command = newCommand
-	exitCode, hcErr := c.exec(config, streams, nil, true)
+	exitCode, stdout, hcErr := c.execHealthCheck(ctx, newCommand, probe, timeout)
 	if hcErr != nil {
 		hcResult = define.HealthCheckFailure
 		if errors.Is(hcErr, define.ErrOCIRuntimeNotFound) ||
@@ -150,26 +114,133 @@ func (c *Container) runHealthCheck(ctx context.Context, isStartup bool) (define.
 		}
 	}
 
-	eventLog := strings.Join(stdout, "\n")
-	if len(eventLog) > MaxHealthCheckLogLength {
-		eventLog = eventLog[:MaxHealthCheckLogLength]
-	}
-
 	if timeEnd.Sub(timeStart) > c.HealthCheckConfig().Timeout {
 		returnCode = -1
 		hcResult = define.HealthCheckFailure
 		hcErr = fmt.Errorf("healthcheck command exceeded timeout of %s", c.HealthCheckConfig().Timeout.String())
 	}
 
-	hcl := newHealthCheckLog(timeStart, timeEnd, returnCode, eventLog)
+	hcl := newHealthCheckLog(timeStart, timeEnd, returnCode, stdout)
 	logStatus, err := c.updateHealthCheckLog(hcl, inStartPeriod)
 	if err != nil {
 		return hcResult, "", fmt.Errorf("unable to update health check log %s for %s: %w", c.healthCheckLogPath(), c.ID(), err)
 	}
+	if err := c.writeHealthCheckLog(hcl, logStatus); err != nil {
+		logrus.Errorf("Writing health check log of container %s to %s: %v", c.ID(), c.healthLogDestination(), err)
+	}
 
 	return hcResult, logStatus, hcErr
 }
 
+// healthCheckCommand returns the command to execute in the container for the
+// specified test of a healthcheck or, if the test is a probe, the probe to
+// perform.
+func (c *Container) healthCheckCommand(test []string) ([]string, *define.HealthProbe, error) {
+	var newCommand []string
+	if len(test) < 1 {
+		return nil, nil, fmt.Errorf("container %s has no defined healthcheck", c.ID())
+	}
+	switch test[0] {
+	case "", define.HealthConfigTestNone:
+		return nil, nil, fmt.Errorf("container %s has no defined healthcheck", c.ID())
+	case define.HealthConfigTestHTTPGet, define.HealthConfigTestTCP, define.HealthConfigTestGRPC:
+		probe, err := define.ParseHealthProbe(test)
+		if err != nil {
+			return nil, nil, fmt.Errorf("container %s has an invalid healthcheck: %w", c.ID(), err)
+		}
+		// The probe is performed by Podman, so there is no command.
+		return test, probe, nil
+	case define.HealthConfigTestCmd:
+		newCommand = test[1:]
+	case define.HealthConfigTestCmdShell:
+		// TODO: SHELL command from image not available in Container - use Docker default
+		newCommand = []string{"/bin/sh", "-c", strings.Join(test[1:], " ")}
+	default:
+		// command supplied on command line - pass as-is
+		newCommand = test
+	}
+	if len(newCommand) < 1 || newCommand[0] == "" {
+		return nil, nil, fmt.Errorf("container %s has no defined healthcheck", c.ID())
+	}
+	return newCommand, nil, nil
+}
+
+// healthCheckOutput captures the output of a healthcheck up to a maximum
+// number of bytes and discards the rest.
+type healthCheckOutput struct {
+	buf bytes.Buffer
+	// maxSize is the maximum number of bytes to capture.  0 means
+	// unlimited.
+	maxSize uint
+}
+
+func (o *healthCheckOutput) Write(p []byte) (int, error) {
+	n := len(p)
+	if o.maxSize > 0 {
+		remaining := int(o.maxSize) - o.buf.Len()
+		if remaining <= 0 {
+			return n, nil
+		}
+		if len(p) > remaining {
+			p = p[:remaining]
+		}
+	}
+	o.buf.Write(p)
+	return n, nil
+}
+
+// String returns the captured output without trailing newlines.
+func (o *healthCheckOutput) String() string {
+	return strings.TrimRight(o.buf.String(), "\r\n")
+}
+
+// execHealthCheck executes the specified command in the container or, if
+// probe is set, performs the probe.  It returns the exit code and the
+// combined output of the command, which is truncated to the maximum log size
+// of the container's healthchecks.
+func (c *Container) execHealthCheck(ctx context.Context, command []string, probe *define.HealthProbe, timeout time.Duration) (int, string, error) {
+	rPipe, wPipe, err := os.Pipe()
+	if err != nil {
+		return -1, "", fmt.Errorf("unable to create pipe for healthcheck session: %w", err)
+	}
+	defer wPipe.Close()
+	defer rPipe.Close()
+
+	streams := new(define.AttachStreams)
+
+	streams.InputStream = bufio.NewReader(os.Stdin)
+	streams.OutputStream = wPipe
+	streams.ErrorStream = wPipe
+	streams.AttachOutput = true
+	streams.AttachError = true
+	streams.AttachInput = true
+
+	output := &healthCheckOutput{maxSize: c.healthMaxLogSize()}
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		_, _ = io.Copy(output, rPipe)
+	}()
+
+	var (
+		exitCode int
+		hcErr    error
+	)
+	if probe != nil {
+		logrus.Debugf("performing health probe %s for %s", strings.Join(command, " "), c.ID())
+		exitCode, hcErr = c.runHealthProbe(ctx, probe, timeout, wPipe)
+	} else {
+		logrus.Debugf("executing health check command %s for %s", strings.Join(command, " "), c.ID())
+		config := new(ExecConfig)
+		config.Command = command
+		exitCode, hcErr = c.exec(config, streams, nil, true)
+	}
+	// Close the write end to let the reader finish.
+	wPipe.Close()
+	<-outputDone
+	return exitCode, output.String(), hcErr
+}
+
 func (c *Container) processHealthCheckStatus(status string) error {
 	if status != define.HealthCheckUnhealthy {
 		return nil
@@ -388,10 +459,8 @@ func (c *Container) updateHealthCheckLog(hcl define.HealthCheckLog, inStartPerio
 			}
 		}
 	}
-	healthCheck.Log = append(healthCheck.Log, hcl)
-	if len(healthCheck.Log) > MaxHealthCheckNumberLogs {
-		healthCheck.Log = healthCheck.Log[1:]
-	}
+	// Keep the last results for inspect whatever the destination of the log
+	healthCheck.Log = trimHealthCheckLog(append(healthCheck.Log, hcl), c.healthMaxLogCount())
 	newResults, err := json.Marshal(healthCheck)
 	if err != nil {
 		return "", fmt.Errorf("unable to marshall healthchecks for writing: %w", err)
@@ -22,6 +22,21 @@ func (c *Container) createTimer(interval string, isStartup bool) error {
 	if c.disableHealthCheckSystemd(isStartup) {
 		return nil
 	}
+	return createTransientTimer(c.hcUnitName(isStartup), interval, "healthcheck", "run", c.ID())
+}
+
+// createReadinessTimer creates a systemd timer for the readiness check of a
+// container
+func (c *Container) createReadinessTimer() error {
+	if c.disableReadinessCheckSystemd() {
+		return nil
+	}
+	return createTransientTimer(c.readinessUnitName(), c.config.ReadinessCheckConfig.Interval.String(), "healthcheck", "run", "--readiness", c.ID())
+}
+
+// createTransientTimer creates a transient systemd timer with the specified
+// unit name which runs podman with the specified arguments
+func createTransientTimer(unitName, interval string, args ...string) error {
 	podman, err := os.Executable()
 	if err != nil {
 		return fmt.Errorf("failed to get path for podman for a health check timer: %w", err)
@@ -36,13 +51,13 @@ func (c *Container) createTimer(interval string, isStartup bool) error {
 		cmd = append(cmd, "--setenv=PATH="+path)
 	}
 
-	cmd = append(cmd, "--unit", c.hcUnitName(isStartup), fmt.Sprintf("--on-unit-inactive=%s", interval), "--timer-property=AccuracySec=1s", podman)
+	cmd = append(cmd, "--unit", unitName, fmt.Sprintf("--on-unit-inactive=%s", interval), "--timer-property=AccuracySec=1s", podman)
 
 	if logrus.IsLevelEnabled(logrus.DebugLevel) {
 		cmd = append(cmd, "--log-level=debug", "--syslog")
 	}
 
-	cmd = append(cmd, "healthcheck", "run", c.ID())
+	cmd = append(cmd, args...)
 
 	conn, err := systemd.ConnectToDBUS()
 	if err != nil {
@@ -73,13 +88,26 @@ func (c *Container) startTimer(isStartup bool) error {
 	if c.disableHealthCheckSystemd(isStartup) {
 		return nil
 	}
+	return startTransientUnit(c.hcUnitName(isStartup))
+}
+
+// startReadinessTimer starts a systemd timer for the readiness check
+func (c *Container) startReadinessTimer() error {
+	if c.disableReadinessCheckSystemd() {
+		return nil
+	}
+	return startTransientUnit(c.readinessUnitName())
+}
+
+// startTransientUnit starts the transient systemd unit with the specified name
+func startTransientUnit(unitName string) error {
 	conn, err := systemd.ConnectToDBUS()
 	if err != nil {
 		return fmt.Errorf("unable to get systemd connection to start healthchecks: %w", err)
 	}
 	defer conn.Close()
 
-	startFile := fmt.Sprintf("%s.service", c.hcUnitName(isStartup))
+	startFile := fmt.Sprintf("%s.service", unitName)
 	startChan := make(chan string)
 	if _, err := conn.RestartUnitContext(context.Background(), startFile, "fail", startChan); err != nil {
 		return err
@@ -97,6 +125,21 @@ func (c *Container) removeTransientFiles(ctx context.Context, isStartup bool) er
 	if c.disableHealthCheckSystemd(isStartup) {
 		return nil
 	}
+	return removeTransientUnits(ctx, c.hcUnitName(isStartup))
+}
+
+// removeReadinessTransientFiles removes the systemd timer and unit files of
+// the readiness check for the container
+func (c *Container) removeReadinessTransientFiles(ctx context.Context) error {
+	if c.disableReadinessCheckSystemd() {
+		return nil
+	}
+	return removeTransientUnits(ctx, c.readinessUnitName())
+}
+
+// removeTransientUnits stops and removes the transient systemd timer and
+// service with the specified name
+func removeTransientUnits(ctx context.Context, unitName string) error {
 	conn, err := systemd.ConnectToDBUS()
 	if err != nil {
 		return fmt.Errorf("unable to get systemd connection to remove healthchecks: %w", err)
@@ -110,7 +153,7 @@ func (c *Container) removeTransientFiles(ctx context.Context, isStartup bool) er
 	// Stop the timer before the service to make sure the timer does not
 	// fire after the service is stopped.
 	timerChan := make(chan string)
-	timerFile := fmt.Sprintf("%s.timer", c.hcUnitName(isStartup))
+	timerFile := fmt.Sprintf("%s.timer", unitName)
 	if _, err := conn.StopUnitContext(ctx, timerFile, "ignore-dependencies", timerChan); err != nil {
 		if !strings.HasSuffix(err.Error(), ".timer not loaded.") {
 			stopErrors = append(stopErrors, fmt.Errorf("removing health-check timer %q: %w", timerFile, err))
@@ -122,7 +165,7 @@ func (c *Container) removeTransientFiles(ctx context.Context, isStartup bool) er
 	// Reset the service before stopping it to make sure it's being removed
 	// on stop.
 	serviceChan := make(chan string)
-	serviceFile := fmt.Sprintf("%s.service", c.hcUnitName(isStartup))
+	serviceFile := fmt.Sprintf("%s.service", unitName)
 	if err := conn.ResetFailedUnitContext(ctx, serviceFile); err != nil {
 		logrus.Debugf("Failed to reset unit file: %q", err)
 	}
@@ -137,8 +180,14 @@ func (c *Container) removeTransientFiles(ctx context.Context, isStartup bool) er
 	return errorhandling.JoinErrors(stopErrors)
 }
 
+// systemdHealthCheckTimers returns whether healthchecks are run by transient
+// systemd timers
+func systemdHealthCheckTimers() bool {
+	return utils.RunsOnSystemd() && os.Getenv("DISABLE_HC_SYSTEMD") != "true"
+}
+
 func (c *Container) disableHealthCheckSystemd(isStartup bool) bool {
-	if !utils.RunsOnSystemd() || os.Getenv("DISABLE_HC_SYSTEMD") == "true" {
+	if !systemdHealthCheckTimers() {
 		return true
 	}
 	if isStartup {
@@ -152,6 +201,13 @@ func (c *Container) disableHealthCheckSystemd(isStartup bool) bool {
 	return false
 }
 
+func (c *Container) disableReadinessCheckSystemd() bool {
+	if !systemdHealthCheckTimers() {
+		return true
+	}
+	return c.config.ReadinessCheckConfig.Interval == 0
+}
+
 // Systemd unit name for the healthcheck systemd unit
 func (c *Container) hcUnitName(isStartup bool) string {
 	unitName := c.ID()
@@ -160,3 +216,8 @@ func (c *Container) hcUnitName(isStartup bool) string {
 	}
 	return unitName
 }
+
+// Systemd unit name for the readiness check systemd unit
+func (c *Container) readinessUnitName() string {
+	return c.ID() + "-readiness"
+}
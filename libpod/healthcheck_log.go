@@ -0,0 +1,143 @@
+package libpod
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/containers/podman/v4/libpod/events"
+	"github.com/sirupsen/logrus"
+)
+
+// healthLogDestination returns the destination of the container's
+// healthcheck log.
+func (c *Container) healthLogDestination() string {
+	if c.config.HealthLogDestination == "" {
+		return define.HealthCheckLogDestinationFile
+	}
+	return c.config.HealthLogDestination
+}
+
+// healthMaxLogCount returns the maximum number of entries of the container's
+// healthcheck log file.  0 means unlimited.
+func (c *Container) healthMaxLogCount() uint {
+	if c.config.HealthMaxLogCount == nil {
+		return define.DefaultHealthMaxLogCount
+	}
+	return *c.config.HealthMaxLogCount
+}
+
+// healthMaxLogSize returns the maximum number of bytes of the output of a
+// healthcheck.  0 means unlimited.
+func (c *Container) healthMaxLogSize() uint {
+	if c.config.HealthMaxLogSize == nil {
+		return define.DefaultHealthMaxLogSize
+	}
+	return *c.config.HealthMaxLogSize
+}
+
+// trimHealthCheckLog drops the oldest entries of the log if it has more than
+// maxCount entries.  A maxCount of 0 means unlimited.
+func trimHealthCheckLog(log []define.HealthCheckLog, maxCount uint) []define.HealthCheckLog {
+	if maxCount == 0 || uint(len(log)) <= maxCount {
+		return log
+	}
+	return log[uint(len(log))-maxCount:]
+}
+
+// writeHealthCheckLog writes the result of a healthcheck to the destination of
+// the container's healthcheck log unless it is a file.  The last results are
+// always kept in the healthcheck log file by updateHealthCheckLog.
+func (c *Container) writeHealthCheckLog(hcl define.HealthCheckLog, status string) error {
+	switch c.healthLogDestination() {
+	case define.HealthCheckLogDestinationJournald:
+		return c.writeHealthCheckLogToJournald(hcl, status)
+	case define.HealthCheckLogDestinationEvents:
+		return c.newContainerHealthLogEvent(hcl, status)
+	default:
+		return nil
+	}
+}
+
+// HealthCheckLog returns the healthcheck results of the container which
+// started at or after since.  The results are read from the destination of
+// the container's healthcheck log.
+func (c *Container) HealthCheckLog(ctx context.Context, since time.Time) ([]define.HealthCheckLog, error) {
+	if !c.HasHealthCheck() {
+		return nil, fmt.Errorf("container %s has no defined healthcheck: %w", c.ID(), define.ErrInvalidArg)
+	}
+
+	var (
+		log []define.HealthCheckLog
+		err error
+	)
+	switch c.healthLogDestination() {
+	case define.HealthCheckLogDestinationJournald:
+		log, err = c.readHealthCheckLogFromJournald(ctx, since)
+	case define.HealthCheckLogDestinationEvents:
+		log, err = c.readHealthCheckLogFromEvents(ctx)
+	default:
+		log, err = c.readHealthCheckLogFromFile()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]define.HealthCheckLog, 0, len(log))
+	for _, entry := range log {
+		if !since.IsZero() {
+			start, err := time.Parse(time.RFC3339Nano, entry.Start)
+			if err != nil || start.Before(since) {
+				continue
+			}
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered, nil
+}
+
+// readHealthCheckLogFromFile returns the entries of the healthcheck log file.
+func (c *Container) readHealthCheckLogFromFile() ([]define.HealthCheckLog, error) {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := c.getHealthCheckLog()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get healthcheck log for %s: %w", c.ID(), err)
+	}
+	return results.Log, nil
+}
+
+// readHealthCheckLogFromEvents returns the healthcheck results recorded in
+// the health_status events of the container.
+func (c *Container) readHealthCheckLogFromEvents(ctx context.Context) ([]define.HealthCheckLog, error) {
+	filters := []string{
+		"container=" + c.ID(),
+		"event=" + events.HealthStatus.String(),
+	}
+	healthEvents, err := c.runtime.GetEvents(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("reading health_status events of container %s: %w", c.ID(), err)
+	}
+
+	var log []define.HealthCheckLog
+	for _, e := range healthEvents {
+		if e.HealthLog == "" {
+			continue
+		}
+		var entry define.HealthCheckLog
+		if err := json.Unmarshal([]byte(e.HealthLog), &entry); err != nil {
+			logrus.Debugf("Decoding healthcheck log of event %s: %v", e.ID, err)
+			continue
+		}
+		log = append(log, entry)
+	}
+	return log, nil
+}
@@ -0,0 +1,98 @@
+//go:build linux && systemd
+// +build linux,systemd
+
+package libpod
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/containers/podman/v4/libpod/events"
+	"github.com/containers/podman/v4/pkg/rootless"
+	"github.com/coreos/go-systemd/v22/journal"
+	"github.com/coreos/go-systemd/v22/sdjournal"
+	"github.com/sirupsen/logrus"
+)
+
+// journaldHealthCheckLogField is the journal field holding the JSON-encoded
+// healthcheck result.
+const journaldHealthCheckLogField = "PODMAN_HEALTHCHECK_LOG"
+
+// writeHealthCheckLogToJournald writes the result of a healthcheck to the
+// journal.
+func (c *Container) writeHealthCheckLogToJournald(hcl define.HealthCheckLog, status string) error {
+	rawLog, err := json.Marshal(hcl)
+	if err != nil {
+		return err
+	}
+	vars := map[string]string{
+		"SYSLOG_IDENTIFIER":         "podman",
+		"PODMAN_ID":                 c.ID(),
+		"PODMAN_NAME":               c.Name(),
+		"PODMAN_HEALTH_STATUS":      status,
+		journaldHealthCheckLogField: string(rawLog),
+	}
+	priority := journal.PriInfo
+	if hcl.ExitCode != 0 {
+		priority = journal.PriWarning
+	}
+	message := fmt.Sprintf("healthcheck of container %s exited with %d: %s", c.Name(), hcl.ExitCode, hcl.Output)
+	return journal.Send(message, priority, vars)
+}
+
+// readHealthCheckLogFromJournald returns the healthcheck results of the
+// container in the journal which were recorded at or after since.
+func (c *Container) readHealthCheckLogFromJournald(ctx context.Context, since time.Time) ([]define.HealthCheckLog, error) {
+	j, err := sdjournal.NewJournal()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := j.Close(); err != nil {
+			logrus.Errorf("Unable to close journal: %v", err)
+		}
+	}()
+	if err := j.SetDataThreshold(0); err != nil {
+		logrus.Warnf("cannot set data threshold: %v", err)
+	}
+
+	matches := []sdjournal.Match{
+		{Field: "SYSLOG_IDENTIFIER", Value: "podman"},
+		{Field: "PODMAN_ID", Value: c.ID()},
+		{Field: "_UID", Value: strconv.Itoa(rootless.GetRootlessUID())},
+	}
+	for _, match := range matches {
+		if err := j.AddMatch(match.String()); err != nil {
+			return nil, fmt.Errorf("adding %s journal filter for healthcheck log: %w", match.Field, err)
+		}
+	}
+	if !since.IsZero() {
+		if err := j.SeekRealtimeUsec(uint64(since.UnixMicro())); err != nil {
+			return nil, err
+		}
+	}
+
+	var log []define.HealthCheckLog
+	for {
+		entry, err := events.GetNextEntry(ctx, j, false, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return log, nil
+		}
+		rawLog, ok := entry.Fields[journaldHealthCheckLogField]
+		if !ok {
+			continue
+		}
+		var hcl define.HealthCheckLog
+		if err := json.Unmarshal([]byte(rawLog), &hcl); err != nil {
+			logrus.Debugf("Decoding healthcheck log in journal: %v", err)
+			continue
+		}
+		log = append(log, hcl)
+	}
+}
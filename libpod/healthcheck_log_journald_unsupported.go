@@ -0,0 +1,20 @@
+//go:build !linux || !systemd
+// +build !linux !systemd
+
+package libpod
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+)
+
+func (c *Container) writeHealthCheckLogToJournald(hcl define.HealthCheckLog, status string) error {
+	return fmt.Errorf("writing healthcheck log to journald is not supported in this build: %w", define.ErrNotImplemented)
+}
+
+func (c *Container) readHealthCheckLogFromJournald(ctx context.Context, since time.Time) ([]define.HealthCheckLog, error) {
+	return nil, fmt.Errorf("reading healthcheck log from journald is not supported in this build: %w", define.ErrNotImplemented)
+}
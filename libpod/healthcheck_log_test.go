@@ -0,0 +1,42 @@
+package libpod
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheckOutput(t *testing.T) {
+	output := healthCheckOutput{maxSize: 5}
+	n, err := output.Write([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	// Writes beyond the limit are discarded but reported as written to
+	// not break the copy of the output.
+	n, err = output.Write([]byte("defgh"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, "abcde", output.String())
+
+	unlimited := healthCheckOutput{}
+	_, err = unlimited.Write([]byte("first line\nsecond line\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "first line\nsecond line", unlimited.String())
+}
+
+func TestTrimHealthCheckLog(t *testing.T) {
+	log := make([]define.HealthCheckLog, 10)
+	for i := range log {
+		log[i].Output = fmt.Sprint(i)
+	}
+
+	trimmed := trimHealthCheckLog(log, 3)
+	assert.Len(t, trimmed, 3)
+	assert.Equal(t, "7", trimmed[0].Output)
+	assert.Equal(t, "9", trimmed[2].Output)
+
+	assert.Len(t, trimHealthCheckLog(log, 10), 10)
+	assert.Len(t, trimHealthCheckLog(log, 0), 10)
+}
@@ -7,6 +7,12 @@ import (
 	"context"
 )
 
+// systemdHealthCheckTimers returns whether healthchecks are run by transient
+// systemd timers
+func systemdHealthCheckTimers() bool {
+	return false
+}
+
 // createTimer systemd timers for healthchecks of a container
 func (c *Container) createTimer(interval string, isStartup bool) error {
 	return nil
@@ -22,3 +28,20 @@ func (c *Container) startTimer(isStartup bool) error {
 func (c *Container) removeTransientFiles(ctx context.Context, isStartup bool) error {
 	return nil
 }
+
+// createReadinessTimer creates a systemd timer for the readiness check of a
+// container
+func (c *Container) createReadinessTimer() error {
+	return nil
+}
+
+// startReadinessTimer starts a systemd timer for the readiness check
+func (c *Container) startReadinessTimer() error {
+	return nil
+}
+
+// removeReadinessTransientFiles removes the systemd timer and unit files of
+// the readiness check for the container
+func (c *Container) removeReadinessTransientFiles(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,142 @@
+package libpod
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// probeDialFunc establishes a network connection for a health probe.
+type probeDialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// runHealthProbe performs the specified health probe in the network namespace
+// of the container and writes the result to the specified writer.  It returns
+// 0 if the probe succeeded and 1 otherwise.  An error is only returned if the
+// probe could not be performed at all.
+func (c *Container) runHealthProbe(ctx context.Context, probe *define.HealthProbe, timeout time.Duration, output io.Writer) (int, error) {
+	netNSPath, err := c.NamespacePath(NetNS)
+	if err != nil {
+		return -1, err
+	}
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialInNetNS(ctx, netNSPath, network, address)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result, err := performHealthProbe(ctx, probe, dial)
+	if err != nil {
+		fmt.Fprintln(output, err)
+		return 1, nil
+	}
+	fmt.Fprintln(output, result)
+	return 0, nil
+}
+
+// performHealthProbe performs the specified probe using the specified dial
+// function.  It returns a description of the result on success.
+func performHealthProbe(ctx context.Context, probe *define.HealthProbe, dial probeDialFunc) (string, error) {
+	switch probe.Type {
+	case define.HealthConfigTestHTTPGet:
+		return httpHealthProbe(ctx, probe, dial)
+	case define.HealthConfigTestTCP:
+		return tcpHealthProbe(ctx, probe, dial)
+	case define.HealthConfigTestGRPC:
+		return grpcHealthProbe(ctx, probe, dial)
+	default:
+		return "", fmt.Errorf("unsupported health probe %q", probe.Type)
+	}
+}
+
+// httpHealthProbe performs an HTTP GET request.  Like Kubernetes, the
+// certificates of HTTPS endpoints are not verified.
+func httpHealthProbe(ctx context.Context, probe *define.HealthProbe, dial probeDialFunc) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.Address, nil)
+	if err != nil {
+		return "", err
+	}
+	for _, header := range probe.Headers {
+		name, value, _ := strings.Cut(header, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Add(name, value)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext:       dial,
+			DisableKeepAlives: true,
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // Health probes do not verify certificates
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTP GET %s: %w", probe.Address, err)
+	}
+	defer resp.Body.Close()
+
+	result := fmt.Sprintf("HTTP GET %s: %s", probe.Address, resp.Status)
+	success := resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest
+	if probe.Status != 0 {
+		success = resp.StatusCode == probe.Status
+	}
+	if !success {
+		return "", errors.New(result)
+	}
+	return result, nil
+}
+
+// tcpHealthProbe opens a TCP connection.
+func tcpHealthProbe(ctx context.Context, probe *define.HealthProbe, dial probeDialFunc) (string, error) {
+	conn, err := dial(ctx, "tcp", probe.Address)
+	if err != nil {
+		return "", fmt.Errorf("TCP %s: %w", probe.Address, err)
+	}
+	conn.Close()
+	return fmt.Sprintf("TCP %s: connection established", probe.Address), nil
+}
+
+// grpcHealthProbe calls the Check method of the gRPC health checking protocol
+// (grpc.health.v1.Health) without TLS.
+func grpcHealthProbe(ctx context.Context, probe *define.HealthProbe, dial probeDialFunc) (string, error) {
+	dialer := func(ctx context.Context, address string) (net.Conn, error) {
+		return dial(ctx, "tcp", address)
+	}
+	conn, err := grpc.DialContext(ctx, probe.Address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialer),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("gRPC %s: %w", probe.Address, err)
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: probe.Service})
+	if err != nil {
+		return "", fmt.Errorf("gRPC %s: %w", probe.Address, err)
+	}
+	result := fmt.Sprintf("gRPC %s: %s", probe.Address, resp.GetStatus())
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return "", errors.New(result)
+	}
+	return result, nil
+}
@@ -0,0 +1,28 @@
+//go:build linux
+// +build linux
+
+package libpod
+
+import (
+	"context"
+	"net"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+)
+
+// dialInNetNS establishes a network connection in the specified network
+// namespace.  The socket is created by the OS thread locked to the namespace,
+// so the connection stays in the namespace once the thread has left it.
+// Note that host names are resolved on the host.
+func dialInNetNS(ctx context.Context, netNSPath, network, address string) (net.Conn, error) {
+	// Disable the fallback of dual-stack dialing which would create the
+	// socket in another goroutine and hence outside of the namespace.
+	dialer := net.Dialer{FallbackDelay: -1}
+	var conn net.Conn
+	err := ns.WithNetNSPath(netNSPath, func(_ ns.NetNS) error {
+		var err error
+		conn, err = dialer.DialContext(ctx, network, address)
+		return err
+	})
+	return conn, err
+}
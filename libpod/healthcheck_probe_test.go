@@ -0,0 +1,115 @@
+package libpod
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testHealthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (s *testHealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if req.Service == "broken" {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return &healthpb.HealthCheckResponse{Status: status}, nil
+}
+
+func TestPerformHealthProbe(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/healthz" && r.Header.Get("X-Token") == "secret":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/created":
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer httpServer.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, &testHealthServer{})
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	defer grpcServer.Stop()
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closedListener.Addr().String()
+	closedListener.Close()
+
+	tests := []struct {
+		name    string
+		probe   define.HealthProbe
+		success bool
+	}{
+		{"http", define.HealthProbe{Type: define.HealthConfigTestHTTPGet, Address: httpServer.URL + "/healthz", Headers: []string{"X-Token: secret"}}, true},
+		{"http missing header", define.HealthProbe{Type: define.HealthConfigTestHTTPGet, Address: httpServer.URL + "/healthz"}, false},
+		{"http status", define.HealthProbe{Type: define.HealthConfigTestHTTPGet, Address: httpServer.URL + "/created"}, true},
+		{"http unexpected status", define.HealthProbe{Type: define.HealthConfigTestHTTPGet, Address: httpServer.URL + "/created", Status: http.StatusOK}, false},
+		{"tcp", define.HealthProbe{Type: define.HealthConfigTestTCP, Address: listener.Addr().String()}, true},
+		{"tcp closed", define.HealthProbe{Type: define.HealthConfigTestTCP, Address: closedAddress}, false},
+		{"grpc", define.HealthProbe{Type: define.HealthConfigTestGRPC, Address: listener.Addr().String()}, true},
+		{"grpc not serving", define.HealthProbe{Type: define.HealthConfigTestGRPC, Address: listener.Addr().String(), Service: "broken"}, false},
+	}
+
+	var dialer net.Dialer
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err := performHealthProbe(ctx, &test.probe, dialer.DialContext)
+			if test.success {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestParseHealthProbe(t *testing.T) {
+	probe, err := define.NewHealthProbe(define.HealthConfigTestTCP, "5432")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:5432", probe.Address)
+
+	probe = &define.HealthProbe{
+		Type:    define.HealthConfigTestHTTPGet,
+		Address: "http://localhost:8080/healthz",
+		Status:  204,
+		Headers: []string{"X-Token: secret"},
+	}
+	test := probe.Test()
+	assert.Equal(t, []string{"HTTP-GET", "http://localhost:8080/healthz", "status=204", "header=X-Token: secret"}, test)
+	parsed, err := define.ParseHealthProbe(test)
+	require.NoError(t, err)
+	assert.Equal(t, probe, parsed)
+
+	for _, invalid := range [][]string{
+		{"HTTP-GET", "localhost:8080"},
+		{"HTTP-GET", "http://localhost:8080", "header=broken"},
+		{"TCP", "localhost"},
+		{"TCP", "localhost:80", "service=foo"},
+		{"GRPC", "localhost:50051", "unknown=option"},
+	} {
+		_, err := define.ParseHealthProbe(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
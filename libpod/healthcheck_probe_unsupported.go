@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package libpod
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// dialInNetNS establishes a network connection in the specified network
+// namespace.
+func dialInNetNS(ctx context.Context, netNSPath, network, address string) (net.Conn, error) {
+	return nil, errors.New("health probes are not supported on this platform")
+}
@@ -0,0 +1,268 @@
+package libpod
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/containers/storage/pkg/lockfile"
+	"github.com/sirupsen/logrus"
+)
+
+// healthCheckSchedulerResync is the interval at which the scheduler looks up
+// the running containers in the database to (un)schedule their checks.
+const healthCheckSchedulerResync = 5 * time.Second
+
+// healthCheckScheduler periodically runs the healthchecks and readiness checks
+// of all running containers.  It is used on hosts where the checks cannot be
+// run by transient systemd timers.  All timers are derived from the container
+// state in the database, so they are recovered after a reboot or a restart of
+// the process owning the scheduler.
+type healthCheckScheduler struct {
+	runtime *Runtime
+	// lock ensures that only one scheduler runs per runtime.  Other
+	// schedulers wait until the lock is released and take over.
+	lock   *lockfile.LockFile
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	// checks maps the IDs of the containers (with a "-readiness" suffix
+	// for readiness checks) to their scheduled checks.  It must only be
+	// accessed by the resync loop.
+	checks map[string]*scheduledCheck
+}
+
+// scheduledCheck is a periodically run check of a container.
+type scheduledCheck struct {
+	cancel context.CancelFunc
+	// startedTime of the container when the check was scheduled.  The
+	// check is rescheduled if the container has been restarted since.
+	startedTime time.Time
+}
+
+// StartHealthCheckScheduler starts running the healthchecks and readiness
+// checks of all running containers in the background until the runtime is
+// shut down.  It is meant to be called by long-lived processes such as the
+// API service.  Nothing is done if the checks are run by systemd timers.
+func (r *Runtime) StartHealthCheckScheduler() error {
+	if !r.valid {
+		return define.ErrRuntimeStopped
+	}
+	if systemdHealthCheckTimers() {
+		logrus.Debugf("Healthchecks are run by systemd timers, not starting the healthcheck scheduler")
+		return nil
+	}
+	if r.healthCheckScheduler != nil {
+		return nil
+	}
+
+	lock, err := lockfile.GetLockFile(filepath.Join(r.config.Engine.TmpDir, "healthcheck-scheduler.lock"))
+	if err != nil {
+		return fmt.Errorf("creating healthcheck scheduler lock: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &healthCheckScheduler{
+		runtime: r,
+		lock:    lock,
+		cancel:  cancel,
+		checks:  make(map[string]*scheduledCheck),
+	}
+	r.healthCheckScheduler = s
+
+	s.wg.Add(1)
+	go s.run(ctx)
+	return nil
+}
+
+// run waits for the scheduler lock and resyncs the scheduled checks until
+// the context is canceled.
+func (s *healthCheckScheduler) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	locked := make(chan struct{})
+	go func() {
+		s.lock.Lock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-ctx.Done():
+		// Release the lock once it has been acquired.
+		go func() {
+			<-locked
+			s.lock.Unlock()
+		}()
+		return
+	}
+	defer s.lock.Unlock()
+	logrus.Debugf("Healthcheck scheduler started")
+
+	for {
+		s.resync(ctx)
+		select {
+		case <-ctx.Done():
+			for _, check := range s.checks {
+				check.cancel()
+			}
+			return
+		case <-time.After(healthCheckSchedulerResync):
+		}
+	}
+}
+
+// stop stops all scheduled checks and waits for them to finish.
+func (s *healthCheckScheduler) stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// resync schedules the checks of all running containers and unschedules the
+// checks of containers which are not running anymore.
+func (s *healthCheckScheduler) resync(ctx context.Context) {
+	ctrs, err := s.runtime.GetAllContainers()
+	if err != nil {
+		logrus.Errorf("Healthcheck scheduler: retrieving containers: %v", err)
+		return
+	}
+
+	wanted := make(map[string]bool)
+	for _, ctr := range ctrs {
+		state, err := ctr.State()
+		if err != nil || state != define.ContainerStateRunning {
+			continue
+		}
+		startedTime, err := ctr.StartedTime()
+		if err != nil {
+			continue
+		}
+		for _, readiness := range []bool{false, true} {
+			interval, err := ctr.scheduledCheckInterval(readiness)
+			if err != nil || interval <= 0 {
+				continue
+			}
+			key := ctr.ID()
+			if readiness {
+				key += "-readiness"
+			}
+			wanted[key] = true
+			if check, ok := s.checks[key]; ok {
+				if check.startedTime.Equal(startedTime) {
+					continue
+				}
+				// The container has been restarted.
+				check.cancel()
+			}
+			checkCtx, cancel := context.WithCancel(ctx)
+			s.checks[key] = &scheduledCheck{cancel: cancel, startedTime: startedTime}
+			s.wg.Add(1)
+			go s.runCheck(checkCtx, ctr, readiness)
+		}
+	}
+
+	for key, check := range s.checks {
+		if !wanted[key] {
+			check.cancel()
+			delete(s.checks, key)
+		}
+	}
+}
+
+// runCheck runs the healthcheck or readiness check of the container at its
+// interval until the context is canceled.  Like systemd timers, the interval
+// is the time between the end of a check and the start of the next one.
+func (s *healthCheckScheduler) runCheck(ctx context.Context, ctr *Container, readiness bool) {
+	defer s.wg.Done()
+
+	next := time.Now()
+	if !readiness {
+		// Resume the timer of the previous process if there is one.
+		last, err := ctr.lastHealthCheck()
+		if err != nil {
+			logrus.Debugf("Healthcheck scheduler: reading healthcheck log of container %s: %v", ctr.ID(), err)
+		}
+		if interval, err := ctr.scheduledCheckInterval(false); err == nil && !last.IsZero() {
+			next = last.Add(interval)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		var err error
+		if readiness {
+			_, err = s.runtime.ReadinessCheck(ctx, ctr.ID())
+		} else {
+			_, err = s.runtime.HealthCheck(ctx, ctr.ID())
+		}
+		if err != nil {
+			logrus.Debugf("Healthcheck scheduler: running check of container %s: %v", ctr.ID(), err)
+		}
+
+		// The interval changes once the startup healthcheck passed.
+		interval, err := ctr.scheduledCheckInterval(readiness)
+		if err != nil || interval <= 0 {
+			return
+		}
+		next = time.Now().Add(interval)
+	}
+}
+
+// scheduledCheckInterval returns the current interval of the container's
+// healthcheck or readiness check.  The interval of the healthcheck is the one
+// of the startup healthcheck until it passed.  An interval of 0 indicates
+// that the check is not run periodically.
+func (c *Container) scheduledCheckInterval(readiness bool) (time.Duration, error) {
+	if readiness {
+		if c.config.ReadinessCheckConfig == nil {
+			return 0, nil
+		}
+		return c.config.ReadinessCheckConfig.Interval, nil
+	}
+	if !c.HasHealthCheck() {
+		return 0, nil
+	}
+	if c.config.StartupHealthCheckConfig != nil {
+		passed, err := c.StartupHCPassed()
+		if err != nil {
+			return 0, err
+		}
+		if !passed {
+			return c.config.StartupHealthCheckConfig.Interval, nil
+		}
+	}
+	return c.HealthCheckConfig().Interval, nil
+}
+
+// lastHealthCheck returns the time at which the last healthcheck of the
+// container since its start ended.  The zero time is returned if there is
+// none.
+func (c *Container) lastHealthCheck() (time.Time, error) {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	results, err := c.getHealthCheckLog()
+	if err != nil || len(results.Log) == 0 {
+		return time.Time{}, err
+	}
+	end, err := time.Parse(time.RFC3339Nano, results.Log[len(results.Log)-1].End)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end.Before(c.state.StartedTime) {
+		return time.Time{}, nil
+	}
+	return end, nil
+}
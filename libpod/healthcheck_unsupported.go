@@ -8,6 +8,12 @@ import (
 	"errors"
 )
 
+// systemdHealthCheckTimers returns whether healthchecks are run by transient
+// systemd timers
+func systemdHealthCheckTimers() bool {
+	return false
+}
+
 // createTimer systemd timers for healthchecks of a container
 func (c *Container) createTimer(interval string, isStartup bool) error {
 	return errors.New("not implemented (*Container) createTimer")
@@ -23,3 +29,20 @@ func (c *Container) startTimer(isStartup bool) error {
 func (c *Container) removeTransientFiles(ctx context.Context, isStartup bool) error {
 	return errors.New("not implemented (*Container) removeTransientFiles")
 }
+
+// createReadinessTimer creates a systemd timer for the readiness check of a
+// container
+func (c *Container) createReadinessTimer() error {
+	return errors.New("not implemented (*Container) createReadinessTimer")
+}
+
+// startReadinessTimer starts a systemd timer for the readiness check
+func (c *Container) startReadinessTimer() error {
+	return errors.New("not implemented (*Container) startReadinessTimer")
+}
+
+// removeReadinessTransientFiles removes the systemd timer and unit files of
+// the readiness check for the container
+func (c *Container) removeReadinessTransientFiles(ctx context.Context) error {
+	return errors.New("not implemented (*Container) removeReadinessTransientFiles")
+}
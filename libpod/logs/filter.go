@@ -0,0 +1,120 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// StdoutStream is the device of log lines written to stdout.
+	StdoutStream = "stdout"
+	// StderrStream is the device of log lines written to stderr.
+	StderrStream = "stderr"
+)
+
+// ValidateStream returns an error if the stream is neither stdout nor
+// stderr.  An empty stream selects both.
+func ValidateStream(stream string) error {
+	switch stream {
+	case "", StdoutStream, StderrStream:
+		return nil
+	default:
+		return fmt.Errorf("invalid stream %q: must be %s or %s", stream, StdoutStream, StderrStream)
+	}
+}
+
+// LogFilter filters the log lines of a container by their stream and message.
+// Partial lines are held back until the line is complete, so that the
+// message of the entire line is matched.  A LogFilter must only be used for
+// the lines of a single container.
+type LogFilter struct {
+	options *LogOptions
+	partial map[string][]*LogLine
+}
+
+// NewLogFilter returns a filter for the stream and grep options or nil if
+// the options do not filter any lines.
+func NewLogFilter(options *LogOptions) *LogFilter {
+	if options.Stream == "" && options.Grep == nil {
+		return nil
+	}
+	return &LogFilter{
+		options: options,
+		partial: make(map[string][]*LogLine),
+	}
+}
+
+// Filter returns the lines to output for the log line.  This is either none,
+// or the line itself along with the preceding parts of the line.
+func (f *LogFilter) Filter(line *LogLine) []*LogLine {
+	if f.options.Stream != "" && line.Device != f.options.Stream {
+		return nil
+	}
+	if f.options.Grep == nil {
+		return []*LogLine{line}
+	}
+	lines := append(f.partial[line.Device], line)
+	if line.Partial() {
+		f.partial[line.Device] = lines
+		return nil
+	}
+	delete(f.partial, line.Device)
+	return f.match(lines)
+}
+
+// Flush returns the matching parts of lines which were not completed.
+func (f *LogFilter) Flush() []*LogLine {
+	var lines []*LogLine
+	for _, stream := range []string{StdoutStream, StderrStream} {
+		lines = append(lines, f.match(f.partial[stream])...)
+		delete(f.partial, stream)
+	}
+	return lines
+}
+
+// match returns the parts of a line if their message matches.
+func (f *LogFilter) match(lines []*LogLine) []*LogLine {
+	if len(lines) == 0 {
+		return nil
+	}
+	var msg strings.Builder
+	for _, l := range lines {
+		msg.WriteString(l.Msg)
+	}
+	if !f.options.Grep.MatchString(msg.String()) {
+		return nil
+	}
+	return lines
+}
+
+// JSONLogOutput is the structured output of a log line.
+type JSONLogOutput struct {
+	// Time the line was logged.
+	Time time.Time `json:"time"`
+	// Stream is stdout or stderr.
+	Stream string `json:"stream"`
+	// ContainerID is the ID of the container which logged the line.
+	ContainerID string `json:"containerId"`
+	// ContainerName is the name of the container which logged the line.
+	ContainerName string `json:"containerName,omitempty"`
+	// Partial is set if the line is continued by the next line of the
+	// stream.
+	Partial bool `json:"partial"`
+	// Message is the logged message without the trailing newline.
+	Message string `json:"message"`
+}
+
+// ToJSONString returns the log line as a JSON object.
+func (l *LogLine) ToJSONString() (string, error) {
+	b, err := json.Marshal(&JSONLogOutput{
+		Time:          l.Time,
+		Stream:        l.Device,
+		ContainerID:   l.CID,
+		ContainerName: l.CName,
+		Partial:       l.Partial(),
+		Message:       l.Msg,
+	})
+	return string(b), err
+}
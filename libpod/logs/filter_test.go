@@ -0,0 +1,61 @@
+package logs
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFilter(t *testing.T) {
+	assert.Nil(t, NewLogFilter(&LogOptions{}))
+
+	input := []string{
+		"2023-05-01T10:00:00.000000000Z stdout F first error",
+		"2023-05-01T10:00:01.000000000Z stderr F second error",
+		"2023-05-01T10:00:02.000000000Z stdout P third ",
+		"2023-05-01T10:00:03.000000000Z stderr F fourth",
+		"2023-05-01T10:00:04.000000000Z stdout F err",
+		"2023-05-01T10:00:05.000000000Z stdout P fifth error",
+	}
+	filterLines := func(options *LogOptions) []string {
+		filter := NewLogFilter(options)
+		require.NotNil(t, filter)
+		var lines []*LogLine
+		for _, s := range input {
+			l, err := NewLogLine(s)
+			require.NoError(t, err)
+			lines = append(lines, filter.Filter(l)...)
+		}
+		return messages(append(lines, filter.Flush()...))
+	}
+
+	assert.Equal(t, []string{"first error", "third ", "err", "fifth error"}, filterLines(&LogOptions{Stream: StdoutStream}))
+	assert.Equal(t, []string{"second error", "fourth"}, filterLines(&LogOptions{Stream: StderrStream}))
+	// Partial lines are matched as a whole.
+	assert.Equal(t, []string{"first error", "second error", "third ", "err", "fifth error"}, filterLines(&LogOptions{Grep: regexp.MustCompile("error$|d err")}))
+	assert.Equal(t, []string{"second error"}, filterLines(&LogOptions{Stream: StderrStream, Grep: regexp.MustCompile("error")}))
+}
+
+func TestValidateStream(t *testing.T) {
+	for _, stream := range []string{"", StdoutStream, StderrStream} {
+		assert.NoError(t, ValidateStream(stream))
+	}
+	assert.Error(t, ValidateStream("stdin"))
+}
+
+func TestLogLineToJSONString(t *testing.T) {
+	l := &LogLine{
+		Device:       StderrStream,
+		ParseLogType: PartialLogType,
+		Time:         time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+		Msg:          `say "hi"`,
+		CID:          "abc",
+		CName:        "ctr",
+	}
+	s, err := l.ToJSONString()
+	require.NoError(t, err)
+	assert.Equal(t, `{"time":"2023-05-01T10:00:00Z","stream":"stderr","containerId":"abc","containerName":"ctr","partial":true,"message":"say \"hi\""}`, s)
+}
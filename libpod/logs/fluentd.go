@@ -0,0 +1,153 @@
+package logs
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/containers/podman/v4/libpod/define"
+)
+
+const (
+	// FluentdAddressOption is the log option setting the address of the
+	// fluentd server.
+	FluentdAddressOption = define.FluentdLogging + "-" + addressOptionSuffix
+
+	// defaultFluentdPort is the port of fluentd servers if the address
+	// does not specify one.
+	defaultFluentdPort = "24224"
+)
+
+// fluentdOptions are the log options of the fluentd log driver.
+type fluentdOptions struct {
+	*remoteOptions
+	// network and addr of the fluentd server.
+	network string
+	addr    string
+}
+
+// parseFluentdOptions parses the log options of the fluentd log driver.  The
+// address is either a unix:// or tcp:// URL or host[:port].
+func parseFluentdOptions(options map[string]string) (*fluentdOptions, error) {
+	remote, err := parseRemoteOptions(define.FluentdLogging, options)
+	if err != nil {
+		return nil, err
+	}
+	opts := &fluentdOptions{
+		remoteOptions: remote,
+		network:       "tcp",
+	}
+	address := remote.Address
+	if address == "" {
+		address = "localhost"
+		remote.Address = net.JoinHostPort(address, defaultFluentdPort)
+	}
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		opts.network, opts.addr = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		u, err := url.Parse(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for log option %s: %w", address, FluentdAddressOption, err)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid value %q for log option %s: missing host", address, FluentdAddressOption)
+		}
+		address = u.Host
+		fallthrough
+	default:
+		opts.addr = address
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			opts.addr = net.JoinHostPort(address, defaultFluentdPort)
+		}
+	}
+	if opts.addr == "" || strings.Contains(opts.addr, "://") {
+		return nil, fmt.Errorf("invalid value %q for log option %s: must be host[:port], a tcp:// or a unix:// URL", remote.Address, FluentdAddressOption)
+	}
+	return opts, nil
+}
+
+// newFluentdWriter returns a writer sending the log lines with the tag to the
+// fluentd server of the log options using the message mode of the Forward
+// protocol.
+func newFluentdWriter(options map[string]string, tag, containerID, containerName string) (*remoteWriter, error) {
+	opts, err := parseFluentdOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	dial := func() (net.Conn, error) {
+		return net.DialTimeout(opts.network, opts.addr, remoteIOTimeout)
+	}
+	encode := func(line *LogLine) ([]byte, error) {
+		record := map[string]string{
+			"container_id":   containerID,
+			"container_name": containerName,
+			"source":         line.Device,
+			"log":            line.Msg,
+		}
+		if line.Partial() {
+			record["partial_message"] = "true"
+		}
+		return encodeFluentdMessage(tag, line, record), nil
+	}
+	return newRemoteWriter(opts.remoteOptions, dial, encode), nil
+}
+
+// encodeFluentdMessage returns the MessagePack encoded Forward protocol
+// message [tag, time, record] with the time of the line as EventTime.
+func encodeFluentdMessage(tag string, line *LogLine, record map[string]string) []byte {
+	b := []byte{0x93} // array of three elements
+	b = appendMsgpackString(b, tag)
+
+	// EventTime is the extension type 0 with the seconds and nanoseconds
+	// as 32 bit big endian integers.
+	b = append(b, 0xd7, 0x00)
+	b = appendUint32(b, uint32(line.Time.Unix()))
+	b = appendUint32(b, uint32(line.Time.Nanosecond()))
+
+	keys := make([]string, 0, len(record))
+	for key := range record {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	b = appendMsgpackMapHeader(b, len(keys))
+	for _, key := range keys {
+		b = appendMsgpackString(b, key)
+		b = appendMsgpackString(b, record[key])
+	}
+	return b
+}
+
+// appendMsgpackMapHeader appends the MessagePack header of a map with n
+// entries.
+func appendMsgpackMapHeader(b []byte, n int) []byte {
+	if n < 16 {
+		return append(b, 0x80|byte(n))
+	}
+	b = append(b, 0xde)
+	return append(b, byte(n>>8), byte(n))
+}
+
+// appendMsgpackString appends the MessagePack encoding of the string.
+func appendMsgpackString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n < 1<<8:
+		b = append(b, 0xd9, byte(n))
+	case n < 1<<16:
+		b = append(b, 0xda)
+		b = append(b, byte(n>>8), byte(n))
+	default:
+		b = append(b, 0xdb)
+		b = appendUint32(b, uint32(n))
+	}
+	return append(b, s...)
+}
+
+// appendUint32 appends the big endian encoding of the integer.
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
@@ -0,0 +1,291 @@
+//go:build linux || freebsd
+// +build linux freebsd
+
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/containers/storage/pkg/reexec"
+	"github.com/sirupsen/logrus"
+	logrusSyslog "github.com/sirupsen/logrus/hooks/syslog"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// ForwarderReexecKey is the argv[0] of the log forwarder process.
+	ForwarderReexecKey = "podman-log-forwarder"
+
+	// forwarderFifoFd is the file descriptor of the FIFO in the log
+	// forwarder process.  0, 1 and 2 are stdin, stdout and stderr.
+	forwarderFifoFd = 3
+)
+
+// ForwarderConfig is the configuration of the log forwarder process.  It is
+// passed to the process as JSON on stdin.
+type ForwarderConfig struct {
+	// ContainerID is the ID of the container whose log is forwarded.
+	ContainerID string
+	// ContainerName is the name of the container.
+	ContainerName string
+	// Driver is the log driver of the container.
+	Driver string
+	// Options are the driver specific log options.
+	Options map[string]string
+	// Tag identifies the container in the log of the syslog and fluentd
+	// log drivers.
+	Tag string
+	// Path is the path of the log file.  The syslog and fluentd log
+	// drivers keep a copy of the log in the k8s-file format in it in the
+	// dual log mode.
+	Path string
+	// MaxSize is the size in bytes at which the log file is rotated.
+	MaxSize int64
+	// MaxFile is the maximum number of log files kept.
+	MaxFile int
+	// Compress enables the compression of rotated log files.
+	Compress bool
+	// LogLevel is the log level of the forwarder process.
+	LogLevel string
+}
+
+// LogWriter writes the log lines forwarded from conmon to the destination
+// of a log driver.
+type LogWriter interface {
+	WriteLine(line *LogLine) error
+	Close() error
+}
+
+func init() {
+	reexec.Register(ForwarderReexecKey, forwarderMain)
+}
+
+// StartForwarder creates a FIFO at fifoPath and starts a process forwarding
+// the k8s-file formatted log written to the FIFO by conmon to the destination
+// of the log driver.  The process exits once conmon closed the FIFO.
+func StartForwarder(config *ForwarderConfig, fifoPath string) (*os.Process, error) {
+	if err := os.Remove(fifoPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("removing log FIFO: %w", err)
+	}
+	if err := unix.Mkfifo(fifoPath, 0600); err != nil {
+		return nil, fmt.Errorf("creating log FIFO %s: %w", fifoPath, err)
+	}
+	// Opening the read end must not wait for conmon to open the write end.
+	fd, err := unix.Open(fifoPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("opening log FIFO %s: %w", fifoPath, err)
+	}
+	fifo := os.NewFile(uintptr(fd), fifoPath)
+	defer fifo.Close()
+	// The lock is inherited by the forwarder and released once it exited,
+	// see WaitForwarder.
+	if err := unix.Flock(fd, unix.LOCK_EX); err != nil {
+		return nil, fmt.Errorf("locking log FIFO %s: %w", fifoPath, err)
+	}
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	configR, configW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating pipe for log forwarder config: %w", err)
+	}
+	defer configR.Close()
+	// The config is small enough to fit into the pipe buffer, so write it
+	// before starting the process.
+	_, err = configW.Write(configJSON)
+	configW.Close()
+	if err != nil {
+		return nil, fmt.Errorf("writing log forwarder config: %w", err)
+	}
+
+	cmd := reexec.Command(ForwarderReexecKey)
+	cmd.Stdin = configR
+	cmd.ExtraFiles = []*os.File{fifo}
+	// The forwarder must outlive this process, just like conmon.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting log forwarder: %w", err)
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			logrus.Debugf("Log forwarder of container %s exited: %v", config.ContainerID, err)
+		}
+	}()
+	return cmd.Process, nil
+}
+
+// WaitForwarder waits until the log forwarder reading from the FIFO exited,
+// i.e., until it wrote the entire log of the stopped container.  It returns an
+// error if the forwarder is still running after the timeout.
+func WaitForwarder(fifoPath string, timeout time.Duration) error {
+	fd, err := unix.Open(fifoPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			return nil
+		}
+		return fmt.Errorf("opening log FIFO %s: %w", fifoPath, err)
+	}
+	defer unix.Close(fd)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := unix.Flock(fd, unix.LOCK_SH|unix.LOCK_NB)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, unix.EWOULDBLOCK) {
+			return fmt.Errorf("locking log FIFO %s: %w", fifoPath, err)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for log forwarder reading from %s", fifoPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// forwarderMain is the entry point of the log forwarder process.
+func forwarderMain() {
+	if hook, err := logrusSyslog.NewSyslogHook("", "", syslog.LOG_INFO, ""); err == nil {
+		logrus.AddHook(hook)
+	}
+	if err := runForwarder(); err != nil {
+		logrus.Errorf("Forwarding container log: %v", err)
+		os.Exit(1)
+	}
+}
+
+func runForwarder() error {
+	var config ForwarderConfig
+	if err := json.NewDecoder(os.Stdin).Decode(&config); err != nil {
+		return fmt.Errorf("decoding log forwarder config: %w", err)
+	}
+	if level, err := logrus.ParseLevel(config.LogLevel); err == nil {
+		logrus.SetLevel(level)
+	}
+
+	writer, err := newLogWriter(&config)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := writer.Close(); err != nil {
+			logrus.Errorf("Closing log of container %s: %v", config.ContainerID, err)
+		}
+	}()
+	logrus.Debugf("Forwarding log of container %s to %s", config.ContainerID, config.Driver)
+	return forwardLog(&fifoReader{fd: forwarderFifoFd}, writer)
+}
+
+// newLogWriter returns the writer of the log driver.
+func newLogWriter(config *ForwarderConfig) (LogWriter, error) {
+	var remote LogWriter
+	var err error
+	switch config.Driver {
+	case define.JSONLogging:
+		return NewJSONFileWriter(config.Path, config.MaxSize, config.MaxFile, config.Compress)
+	case define.SyslogLogging:
+		remote, err = newSyslogWriter(config.Options, config.Tag)
+	case define.FluentdLogging:
+		remote, err = newFluentdWriter(config.Options, config.Tag, config.ContainerID, config.ContainerName)
+	default:
+		return nil, fmt.Errorf("log driver %q does not support forwarding", config.Driver)
+	}
+	if err != nil || !IsDualLogMode(config.Options) {
+		return remote, err
+	}
+	local, err := NewK8sFileWriter(config.Path, config.MaxSize)
+	if err != nil {
+		remote.Close()
+		return nil, err
+	}
+	return &dualLogWriter{local: local, remote: remote}, nil
+}
+
+// dualLogWriter writes the log lines to a local log file along with a remote
+// destination.
+type dualLogWriter struct {
+	local  LogWriter
+	remote LogWriter
+}
+
+func (w *dualLogWriter) WriteLine(line *LogLine) error {
+	localErr := w.local.WriteLine(line)
+	if err := w.remote.WriteLine(line); err != nil {
+		return err
+	}
+	return localErr
+}
+
+func (w *dualLogWriter) Close() error {
+	localErr := w.local.Close()
+	if err := w.remote.Close(); err != nil {
+		return err
+	}
+	return localErr
+}
+
+// forwardLog reads k8s-file formatted log lines and writes them to the log
+// writer until the reader is closed.  Lines which cannot be written are
+// dropped, so that conmon is never blocked by the forwarder.
+func forwardLog(r io.Reader, w LogWriter) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line = strings.TrimSuffix(line, "\n"); line != "" {
+			nll, parseErr := NewLogLine(line)
+			if parseErr != nil {
+				logrus.Errorf("Getting new log line: %v", parseErr)
+			} else if writeErr := w.WriteLine(nll); writeErr != nil {
+				logrus.Errorf("Writing log line: %v", writeErr)
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// fifoReader reads from the read end of a FIFO until the writer closed it.
+// Unlike a plain read, it does not return EOF before a writer opened the FIFO.
+type fifoReader struct {
+	fd int
+}
+
+func (r *fifoReader) Read(p []byte) (int, error) {
+	for {
+		// POLLHUP is only reported once a writer closed the FIFO.
+		fds := []unix.PollFd{{Fd: int32(r.fd), Events: unix.POLLIN}}
+		if _, err := unix.Poll(fds, -1); err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			return 0, err
+		}
+		n, err := unix.Read(r.fd, p)
+		switch {
+		case n > 0:
+			return n, nil
+		case errors.Is(err, unix.EAGAIN), errors.Is(err, unix.EINTR):
+			continue
+		case err != nil:
+			return 0, err
+		case fds[0].Revents&unix.POLLHUP != 0:
+			return 0, io.EOF
+		}
+	}
+}
@@ -0,0 +1,119 @@
+//go:build linux || freebsd
+// +build linux freebsd
+
+package logs
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestForwardLog(t *testing.T) {
+	dir := t.TempDir()
+	fifoPath := filepath.Join(dir, "ctr.log.fifo")
+	require.NoError(t, unix.Mkfifo(fifoPath, 0600))
+	fd, err := unix.Open(fifoPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	require.NoError(t, err)
+	defer unix.Close(fd)
+
+	logPath := filepath.Join(dir, "ctr.log")
+	writer, err := NewJSONFileWriter(logPath, 0, 1, false)
+	require.NoError(t, err)
+	done := make(chan error, 1)
+	go func() {
+		done <- forwardLog(&fifoReader{fd: fd}, writer)
+	}()
+
+	// The forwarder must wait for the writer to open the FIFO.
+	select {
+	case err := <-done:
+		t.Fatalf("forwarder returned before the FIFO was opened: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	fifo, err := os.OpenFile(fifoPath, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = fifo.WriteString("2023-05-01T10:00:00.000000000Z stdout F hello\n2023-05-01T10:00:01.000000000Z stderr P wor")
+	require.NoError(t, err)
+	_, err = fifo.WriteString("ld\n2023-05-01T10:00:02.000000000Z stderr F \n")
+	require.NoError(t, err)
+	require.NoError(t, fifo.Close())
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("forwarder did not return after the FIFO was closed")
+	}
+	require.NoError(t, writer.Close())
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, `{"log":"hello\n","stream":"stdout","time":"2023-05-01T10:00:00Z"}
+{"log":"world","stream":"stderr","time":"2023-05-01T10:00:01Z"}
+{"log":"\n","stream":"stderr","time":"2023-05-01T10:00:02Z"}
+`, string(content))
+}
+
+func TestNewLogWriterDual(t *testing.T) {
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udp.Close()
+	logPath := filepath.Join(t.TempDir(), "ctr.log")
+	config := &ForwarderConfig{
+		ContainerID: "id",
+		Driver:      define.SyslogLogging,
+		Options:     map[string]string{SyslogAddressOption: "udp://" + udp.LocalAddr().String()},
+		Tag:         "tag",
+		Path:        logPath,
+	}
+
+	// Without the dual mode, no local copy is kept.
+	writer, err := newLogWriter(config)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteLine(testLogLine(StdoutStream, FullLogType, "remote")))
+	require.NoError(t, writer.Close())
+	_, err = os.Stat(logPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	config.Options[LogModeOption] = DualLogMode
+	writer, err = newLogWriter(config)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteLine(testLogLine(StdoutStream, FullLogType, "dual")))
+	require.NoError(t, writer.Close())
+	_, history, err := GetLogFile(logPath, &LogOptions{Tail: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dual"}, messages(history))
+
+	buf := make([]byte, 1024)
+	require.NoError(t, udp.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for _, msg := range []string{"remote", "dual"} {
+		n, _, err := udp.ReadFrom(buf)
+		require.NoError(t, err)
+		assert.True(t, strings.HasSuffix(string(buf[:n]), " "+msg), string(buf[:n]))
+	}
+}
+
+func TestWaitForwarder(t *testing.T) {
+	fifoPath := filepath.Join(t.TempDir(), "ctr.log.fifo")
+	// Nothing to wait for without a FIFO.
+	require.NoError(t, WaitForwarder(fifoPath, time.Second))
+
+	require.NoError(t, unix.Mkfifo(fifoPath, 0600))
+	fd, err := unix.Open(fifoPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	require.NoError(t, err)
+	require.NoError(t, unix.Flock(fd, unix.LOCK_EX))
+	assert.Error(t, WaitForwarder(fifoPath, 50*time.Millisecond))
+
+	require.NoError(t, unix.Close(fd))
+	assert.NoError(t, WaitForwarder(fifoPath, time.Second))
+}
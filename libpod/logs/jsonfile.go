@@ -0,0 +1,305 @@
+package logs
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// JSONFileMaxFileOption is the log option setting the maximum number
+	// of log files kept by the json-file log driver.
+	JSONFileMaxFileOption = "max-file"
+	// JSONFileCompressOption is the log option enabling the compression of
+	// rotated log files of the json-file log driver.
+	JSONFileCompressOption = "compress"
+
+	// compressedLogSuffix is the suffix of compressed rotated log files.
+	compressedLogSuffix = ".gz"
+)
+
+// JSONLogLine is a line of a log file in the format of Docker's json-file
+// log driver.  Log messages are terminated by a newline unless they are
+// partial.
+type JSONLogLine struct {
+	Log    string    `json:"log"`
+	Stream string    `json:"stream"`
+	Time   time.Time `json:"time"`
+}
+
+// NewJSONLogLine creates a logLine struct from a line of a json-file log.
+func NewJSONLogLine(line string) (*LogLine, error) {
+	var jsonLine JSONLogLine
+	if err := json.Unmarshal([]byte(line), &jsonLine); err != nil {
+		return nil, fmt.Errorf("'%s' is not a valid json-file log line: %w", line, err)
+	}
+	l := LogLine{
+		Time:         jsonLine.Time,
+		Device:       jsonLine.Stream,
+		ParseLogType: PartialLogType,
+		Msg:          jsonLine.Log,
+	}
+	if strings.HasSuffix(l.Msg, "\n") {
+		l.ParseLogType = FullLogType
+		l.Msg = strings.TrimSuffix(l.Msg, "\n")
+	}
+	return &l, nil
+}
+
+// ParseLogLine creates a logLine struct from a line of a container log file
+// in either the k8s-file or the json-file format.
+func ParseLogLine(line string) (*LogLine, error) {
+	if strings.HasPrefix(line, "{") {
+		return NewJSONLogLine(line)
+	}
+	return NewLogLine(line)
+}
+
+// ParseJSONFileOptions parses the max-file and compress log options of the
+// json-file log driver.  By default, only a single log file is kept.
+func ParseJSONFileOptions(options map[string]string) (maxFile int, compress bool, err error) {
+	maxFile = 1
+	if value, ok := options[JSONFileMaxFileOption]; ok {
+		maxFile, err = strconv.Atoi(value)
+		if err != nil || maxFile < 1 {
+			return 0, false, fmt.Errorf("invalid value %q for log option %s: must be a positive integer", value, JSONFileMaxFileOption)
+		}
+	}
+	if value, ok := options[JSONFileCompressOption]; ok {
+		compress, err = strconv.ParseBool(value)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid value %q for log option %s: %w", value, JSONFileCompressOption, err)
+		}
+	}
+	return maxFile, compress, nil
+}
+
+// LogFileWriter writes log lines to a log file.  When the log file exceeds its
+// maximum size, it is rotated to path.1, path.2, and so on, up to the maximum
+// number of files.  Rotated files are optionally compressed.
+type LogFileWriter struct {
+	path     string
+	maxSize  int64
+	maxFile  int
+	compress bool
+	encode   func(line *LogLine) ([]byte, error)
+	file     *os.File
+	size     int64
+}
+
+// NewJSONFileWriter opens the log file at the path for appending log lines in
+// the json-file format.  A maxSize of zero or less disables the rotation.
+func NewJSONFileWriter(path string, maxSize int64, maxFile int, compress bool) (*LogFileWriter, error) {
+	return newLogFileWriter(path, maxSize, maxFile, compress, encodeJSONLogLine)
+}
+
+// NewK8sFileWriter opens the log file at the path for appending log lines in
+// the k8s-file format.  Like conmon, the log file is truncated when it
+// exceeds maxSize, unless it is zero or less.
+func NewK8sFileWriter(path string, maxSize int64) (*LogFileWriter, error) {
+	return newLogFileWriter(path, maxSize, 1, false, encodeK8sLogLine)
+}
+
+func newLogFileWriter(path string, maxSize int64, maxFile int, compress bool, encode func(line *LogLine) ([]byte, error)) (*LogFileWriter, error) {
+	w := &LogFileWriter{
+		path:     path,
+		maxSize:  maxSize,
+		maxFile:  maxFile,
+		compress: compress,
+		encode:   encode,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// encodeJSONLogLine returns the line of a json-file log for the log line.
+func encodeJSONLogLine(line *LogLine) ([]byte, error) {
+	jsonLine := JSONLogLine{
+		Log:    line.Msg,
+		Stream: line.Device,
+		Time:   line.Time,
+	}
+	if !line.Partial() {
+		jsonLine.Log += "\n"
+	}
+	b, err := json.Marshal(&jsonLine)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// encodeK8sLogLine returns the line of a k8s-file log for the log line.
+func encodeK8sLogLine(line *LogLine) ([]byte, error) {
+	return []byte(fmt.Sprintf("%s %s %s %s\n", line.Time.Format(LogTimeFormat), line.Device, line.ParseLogType, line.Msg)), nil
+}
+
+func (w *LogFileWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+// WriteLine writes the log line to the log file and rotates it first if the
+// line would exceed the maximum size.
+func (w *LogFileWriter) WriteLine(line *LogLine) error {
+	b, err := w.encode(line)
+	if err != nil {
+		return err
+	}
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(b)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return fmt.Errorf("rotating log file %s: %w", w.path, err)
+		}
+	}
+	n, err := w.file.Write(b)
+	w.size += int64(n)
+	return err
+}
+
+// rotate shifts the rotated log files by one generation, removing the oldest
+// one, and starts a new log file.  If only a single file is kept, the log
+// file is truncated.
+func (w *LogFileWriter) rotate() error {
+	if w.maxFile <= 1 {
+		if err := w.file.Truncate(0); err != nil {
+			return err
+		}
+		w.size = 0
+		return nil
+	}
+
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	for _, name := range []string{rotatedLogFile(w.path, w.maxFile-1), rotatedLogFile(w.path, w.maxFile-1) + compressedLogSuffix} {
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	for i := w.maxFile - 2; i >= 1; i-- {
+		for _, suffix := range []string{"", compressedLogSuffix} {
+			err := os.Rename(rotatedLogFile(w.path, i)+suffix, rotatedLogFile(w.path, i+1)+suffix)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	if err := os.Rename(w.path, rotatedLogFile(w.path, 1)); err != nil {
+		return err
+	}
+	if w.compress {
+		if err := compressLogFile(rotatedLogFile(w.path, 1)); err != nil {
+			logrus.Errorf("Compressing rotated log file: %v", err)
+		}
+	}
+	return w.open()
+}
+
+// Close closes the log file.
+func (w *LogFileWriter) Close() error {
+	return w.file.Close()
+}
+
+// rotatedLogFile returns the path of the specified generation of a rotated
+// log file.
+func rotatedLogFile(path string, generation int) string {
+	return path + "." + strconv.Itoa(generation)
+}
+
+// compressLogFile replaces the file with a gzip compressed copy.
+func compressLogFile(path string) (retErr error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+compressedLogSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			dst.Close()
+			os.Remove(dst.Name())
+		}
+	}()
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// rotatedLogFiles returns the paths of the existing rotated generations of
+// the log file, starting with the oldest one.
+func rotatedLogFiles(path string) []string {
+	var files []string
+	for i := 1; ; i++ {
+		name := rotatedLogFile(path, i)
+		if _, err := os.Stat(name); err != nil {
+			name += compressedLogSuffix
+			if _, err := os.Stat(name); err != nil {
+				break
+			}
+		}
+		files = append([]string{name}, files...)
+	}
+	return files
+}
+
+// readRotatedLogFile returns the lines of a rotated log file, which may be
+// compressed.
+func readRotatedLogFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var reader io.Reader = f
+	if strings.HasSuffix(path, compressedLogSuffix) {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing log file %s: %w", path, err)
+		}
+		defer zr.Close()
+		reader = zr
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("reading log file %s: %w", path, err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,150 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJSONLogLine(t *testing.T) {
+	l, err := ParseLogLine(`{"log":"hello\n","stream":"stderr","time":"2023-05-01T10:00:00.123456789Z"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", l.Msg)
+	assert.Equal(t, "stderr", l.Device)
+	assert.False(t, l.Partial())
+	assert.Equal(t, time.Date(2023, 5, 1, 10, 0, 0, 123456789, time.UTC), l.Time.UTC())
+
+	l, err = ParseLogLine(`{"log":"hel","stream":"stdout","time":"2023-05-01T10:00:00Z"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "hel", l.Msg)
+	assert.True(t, l.Partial())
+
+	l, err = ParseLogLine("2023-05-01T10:00:00.000000000Z stdout F k8s file")
+	require.NoError(t, err)
+	assert.Equal(t, "k8s file", l.Msg)
+
+	_, err = ParseLogLine(`{"log":`)
+	assert.Error(t, err)
+}
+
+func TestParseJSONFileOptions(t *testing.T) {
+	maxFile, compress, err := ParseJSONFileOptions(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, maxFile)
+	assert.False(t, compress)
+
+	maxFile, compress, err = ParseJSONFileOptions(map[string]string{"max-file": "3", "compress": "true", "tag": "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, maxFile)
+	assert.True(t, compress)
+
+	for _, options := range []map[string]string{{"max-file": "0"}, {"max-file": "many"}, {"compress": "maybe"}} {
+		_, _, err := ParseJSONFileOptions(options)
+		assert.Error(t, err, "%v", options)
+	}
+}
+
+// writeJSONLog writes the numbered messages to a json-file log with the
+// rotation options and returns its path.
+func writeJSONLog(t *testing.T, start time.Time, count int, maxFile int, compress bool) string {
+	path := filepath.Join(t.TempDir(), "ctr.log")
+	// Every line is about 80 bytes, so each file holds three lines.
+	w, err := NewJSONFileWriter(path, 250, maxFile, compress)
+	require.NoError(t, err)
+	for i := 0; i < count; i++ {
+		require.NoError(t, w.WriteLine(&LogLine{
+			Device:       "stdout",
+			ParseLogType: FullLogType,
+			Time:         start.Add(time.Duration(i) * time.Second),
+			Msg:          fmt.Sprintf("message %02d", i),
+		}))
+	}
+	require.NoError(t, w.Close())
+	return path
+}
+
+func messages(lines []*LogLine) []string {
+	var msgs []string
+	for _, l := range lines {
+		msgs = append(msgs, l.Msg)
+	}
+	return msgs
+}
+
+func TestLogFileWriterRotation(t *testing.T) {
+	start := time.Now()
+	for _, compress := range []bool{false, true} {
+		path := writeJSONLog(t, start, 11, 3, compress)
+
+		suffix := ""
+		if compress {
+			suffix = compressedLogSuffix
+		}
+		assert.Equal(t, []string{path + ".2" + suffix, path + ".1" + suffix}, rotatedLogFiles(path))
+		_, err := os.Stat(path + ".3" + suffix)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+
+		// The history spans the rotated files.
+		_, history, err := GetLogFile(path, &LogOptions{Tail: -1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"message 03", "message 04", "message 05", "message 06", "message 07", "message 08"}, messages(history))
+
+		tail, err := getTailLog(path, 4)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"message 07", "message 08", "message 09", "message 10"}, messages(tail))
+
+		tail, err = getTailLog(path, 100)
+		require.NoError(t, err)
+		assert.Len(t, tail, 8)
+		assert.Equal(t, "message 03", tail[0].Msg)
+	}
+}
+
+func TestLogFileWriterTruncate(t *testing.T) {
+	path := writeJSONLog(t, time.Now(), 7, 1, false)
+	assert.Empty(t, rotatedLogFiles(path))
+	tail, err := getTailLog(path, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"message 06"}, messages(tail))
+}
+
+func TestK8sFileWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ctr.log")
+	w, err := NewK8sFileWriter(path, 0)
+	require.NoError(t, err)
+	start := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, w.WriteLine(&LogLine{Device: "stdout", ParseLogType: PartialLogType, Time: start, Msg: "hel"}))
+	require.NoError(t, w.WriteLine(&LogLine{Device: "stdout", ParseLogType: FullLogType, Time: start.Add(time.Second), Msg: "lo"}))
+	require.NoError(t, w.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "2023-05-01T10:00:00.000000000Z stdout P hel\n2023-05-01T10:00:01.000000000Z stdout F lo\n", string(content))
+	tail, err := getTailLog(path, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hello"}, messages(tail))
+}
+
+func TestGetTailLogPartial(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ctr.log")
+	content := "" +
+		"2023-05-01T10:00:00.000000000Z stdout F first\n" +
+		"2023-05-01T10:00:01.000000000Z stdout P sec\n" +
+		"2023-05-01T10:00:02.000000000Z stdout P on\n" +
+		"2023-05-01T10:00:03.000000000Z stdout F d\n" +
+		"2023-05-01T10:00:04.000000000Z stdout F third\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	tail, err := getTailLog(path, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"second", "third"}, messages(tail))
+
+	tail, err = getTailLog(path, 5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "third"}, messages(tail))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -44,6 +45,8 @@ type LogOptions struct {
 	Multi      bool
 	WaitGroup  *sync.WaitGroup
 	UseName    bool
+	Stream     string
+	Grep       *regexp.Regexp
 }
 
 // LogLine describes the information for each line of a log
@@ -57,7 +60,9 @@ type LogLine struct {
 	ColorID      int64
 }
 
-// GetLogFile returns an hp tail for a container given options
+// GetLogFile returns an hp tail for a container given options.  Lines of
+// rotated log files (path.1, path.2, and so on) are returned along with the
+// tail of the log, so that the history spans all of them.
 func GetLogFile(path string, options *LogOptions) (*tail.Tail, []*LogLine, error) {
 	var (
 		whence  int
@@ -74,6 +79,12 @@ func GetLogFile(path string, options *LogOptions) (*tail.Tail, []*LogLine, error
 			return nil, nil, err
 		}
 	}
+	if options.Tail < 0 {
+		logTail, err = getRotatedLog(path)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 	seek := tail.SeekInfo{
 		Offset: 0,
 		Whence: whence,
@@ -83,88 +94,113 @@ func GetLogFile(path string, options *LogOptions) (*tail.Tail, []*LogLine, error
 	return t, logTail, err
 }
 
+// getRotatedLog returns all lines of the rotated log files, starting with the
+// oldest one.
+func getRotatedLog(path string) ([]*LogLine, error) {
+	var rotatedLog []*LogLine
+	for _, file := range rotatedLogFiles(path) {
+		lines, err := readRotatedLogFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			nll, err := ParseLogLine(line)
+			if err != nil {
+				return nil, err
+			}
+			rotatedLog = append(rotatedLog, nll)
+		}
+	}
+	return rotatedLog, nil
+}
+
 func getTailLog(path string, tail int) ([]*LogLine, error) {
 	var (
-		nlls       []*LogLine
 		nllCounter int
-		leftover   string
-		partial    string
 		tailLog    []*LogLine
 	)
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
+	// Lines are read in reverse, so partial lines are prepended to the
+	// message of the line following them.  Reading stops at the first full
+	// line exceeding the desired tail, as all of its parts are read.
+	addLine := func(line string) (bool, error) {
+		// lines that are "" are junk
+		if len(line) < 1 {
+			return false, nil
+		}
+		nll, err := ParseLogLine(line)
+		if err != nil {
+			return false, err
+		}
+		if nll.Partial() && len(tailLog) > 0 {
+			last := tailLog[len(tailLog)-1]
+			last.Msg = nll.Msg + last.Msg
+			return false, nil
+		}
+		if !nll.Partial() {
+			if nllCounter == tail {
+				return true, nil
+			}
+			nllCounter++
+		}
+		tailLog = append(tailLog, nll)
+		return false, nil
 	}
-	rr, err := reversereader.NewReverseReader(f)
+
+	done, err := readLogReverse(path, addLine)
 	if err != nil {
 		return nil, err
 	}
-
-	inputs := make(chan []string)
-	go func() {
-		for {
-			s, err := rr.Read()
-			if err != nil {
-				if errors.Is(err, io.EOF) {
-					inputs <- []string{leftover}
-				} else {
-					logrus.Error(err)
-				}
-				close(inputs)
-				if err := f.Close(); err != nil {
-					logrus.Error(err)
-				}
-				break
-			}
-			line := strings.Split(s+leftover, "\n")
-			if len(line) > 1 {
-				inputs <- line[1:]
-			}
-			leftover = line[0]
+	// Continue with the rotated log files, starting with the newest one.
+	rotated := rotatedLogFiles(path)
+	for i := len(rotated) - 1; i >= 0 && !done; i-- {
+		lines, err := readRotatedLogFile(rotated[i])
+		if err != nil {
+			return nil, err
 		}
-	}()
-
-	for i := range inputs {
-		// the incoming array is FIFO; we want FIFO so
-		// reverse the slice read order
-		for j := len(i) - 1; j >= 0; j-- {
-			// lines that are "" are junk
-			if len(i[j]) < 1 {
-				continue
-			}
-			// read the content in reverse and add each nll until we have the same
-			// number of F type messages as the desired tail
-			nll, err := NewLogLine(i[j])
-			if err != nil {
+		for j := len(lines) - 1; j >= 0 && !done; j-- {
+			if done, err = addLine(lines[j]); err != nil {
 				return nil, err
 			}
-			nlls = append(nlls, nll)
-			if !nll.Partial() {
-				nllCounter++
-			}
-		}
-		// if we have enough log lines, we can hang up
-		if nllCounter >= tail {
-			break
 		}
 	}
 
-	// re-assemble the log lines and trim (if needed) to the
-	// tail length
-	for _, nll := range nlls {
-		if nll.Partial() {
-			partial += nll.Msg
-		} else {
-			nll.Msg += partial
-			// prepend because we need to reverse the order again to FIFO
-			tailLog = append([]*LogLine{nll}, tailLog...)
-			partial = ""
+	// reverse the lines to FIFO order
+	for i, j := 0, len(tailLog)-1; i < j; i, j = i+1, j-1 {
+		tailLog[i], tailLog[j] = tailLog[j], tailLog[i]
+	}
+	return tailLog, nil
+}
+
+// readLogReverse calls the function for each line of the log file, starting
+// with the last one, until it returns true.
+func readLogReverse(path string, fn func(string) (bool, error)) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	rr, err := reversereader.NewReverseReader(f)
+	if err != nil {
+		return false, err
+	}
+
+	var leftover string
+	for {
+		s, err := rr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fn(leftover)
+			}
+			return false, err
 		}
-		if len(tailLog) == tail {
-			break
+		lines := strings.Split(s+leftover, "\n")
+		for i := len(lines) - 1; i >= 1; i-- {
+			if done, err := fn(lines[i]); done || err != nil {
+				return done, err
+			}
 		}
+		leftover = lines[0]
 	}
-	return tailLog, nil
 }
 
 // getColor returns an ANSI escape code for color based on the colorID
@@ -0,0 +1,173 @@
+package logs
+
+import (
+	"context"
+	"time"
+)
+
+// MergeWindow is the maximum time a log line of a followed container is held
+// back to order it by time with the lines of the other containers.
+const MergeWindow = 250 * time.Millisecond
+
+// mergeInput is the state of a container log merged by MergeLogs.
+type mergeInput struct {
+	// lines received but not yet written, along with their arrival.
+	lines   []*LogLine
+	arrival []time.Time
+	closed  bool
+}
+
+// mergeItem is a line received from an input or, if nil, the notification
+// that the input was closed.
+type mergeItem struct {
+	index int
+	line  *LogLine
+}
+
+// logMerger merges the log lines of several containers.
+type logMerger struct {
+	inputs []mergeInput
+	window time.Duration
+	// partial is the index of the input whose last written line was
+	// partial or -1.
+	partial int
+}
+
+// MergeLogs writes the log lines of several containers to the output channel
+// ordered by their time, using a k-way merge of the inputs, each of which must
+// be ordered by time.  A line is written once every open input provided a
+// line to compare it with.  If the window is greater than zero, a line is
+// written at the latest once it was held back for the window, so that inputs
+// without new lines do not block the others while following the logs.  The
+// parts of a partial line are written without the lines of other inputs in
+// between if possible.  MergeLogs returns once all inputs are closed and
+// their lines are written or the context is done.  In the latter case, the
+// inputs are still received from and their lines dropped until they are
+// closed, so that their writers are never blocked.
+func MergeLogs(ctx context.Context, inputs []chan *LogLine, output chan<- *LogLine, window time.Duration) {
+	// Receive from all inputs, so that a reader is never blocked by the
+	// merge waiting for the lines of another one.
+	items := make(chan mergeItem)
+	for i, input := range inputs {
+		go func(i int, input chan *LogLine) {
+			for line := range input {
+				select {
+				case items <- mergeItem{index: i, line: line}:
+				case <-ctx.Done():
+					// Drain the input until its reader closes it.
+					for range input {
+					}
+					return
+				}
+			}
+			select {
+			case items <- mergeItem{index: i}:
+			case <-ctx.Done():
+			}
+		}(i, input)
+	}
+
+	m := &logMerger{
+		inputs:  make([]mergeInput, len(inputs)),
+		window:  window,
+		partial: -1,
+	}
+	for {
+		for {
+			i := m.next(time.Now())
+			if i < 0 {
+				break
+			}
+			select {
+			case output <- m.pop(i):
+			case <-ctx.Done():
+				return
+			}
+		}
+		if m.done() {
+			return
+		}
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if oldest := m.oldest(); oldest >= 0 && m.window > 0 {
+			timer = time.NewTimer(time.Until(m.inputs[oldest].arrival[0].Add(m.window)))
+			timeout = timer.C
+		}
+		select {
+		case item := <-items:
+			input := &m.inputs[item.index]
+			if item.line == nil {
+				input.closed = true
+			} else {
+				input.lines = append(input.lines, item.line)
+				input.arrival = append(input.arrival, time.Now())
+			}
+		case <-timeout:
+		case <-ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// oldest returns the index of the input with the oldest first line or -1 if
+// there are no lines.
+func (m *logMerger) oldest() int {
+	oldest := -1
+	for i := range m.inputs {
+		input := &m.inputs[i]
+		if len(input.lines) > 0 && (oldest < 0 || input.lines[0].Time.Before(m.inputs[oldest].lines[0].Time)) {
+			oldest = i
+		}
+	}
+	return oldest
+}
+
+// next returns the index of the input whose first line is written next or -1
+// if no line can be written yet.
+func (m *logMerger) next(now time.Time) int {
+	if m.partial >= 0 && len(m.inputs[m.partial].lines) > 0 {
+		return m.partial
+	}
+	oldest := m.oldest()
+	if oldest < 0 {
+		return -1
+	}
+	if m.window > 0 && now.Sub(m.inputs[oldest].arrival[0]) >= m.window {
+		return oldest
+	}
+	for i := range m.inputs {
+		if len(m.inputs[i].lines) == 0 && !m.inputs[i].closed {
+			return -1
+		}
+	}
+	return oldest
+}
+
+// pop removes the first line of the input and returns it.
+func (m *logMerger) pop(i int) *LogLine {
+	input := &m.inputs[i]
+	line := input.lines[0]
+	input.lines = input.lines[1:]
+	input.arrival = input.arrival[1:]
+	m.partial = -1
+	if line.Partial() {
+		m.partial = i
+	}
+	return line
+}
+
+// done returns true if all inputs are closed and their lines are written.
+func (m *logMerger) done() bool {
+	for i := range m.inputs {
+		if !m.inputs[i].closed || len(m.inputs[i].lines) > 0 {
+			return false
+		}
+	}
+	return true
+}
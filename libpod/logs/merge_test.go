@@ -0,0 +1,127 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mergeLines merges the k8s-file formatted log lines of the inputs.
+func mergeLines(t *testing.T, window time.Duration, inputs ...[]string) []string {
+	channels := make([]chan *LogLine, len(inputs))
+	for i, input := range inputs {
+		channels[i] = make(chan *LogLine, len(input))
+		for _, s := range input {
+			l, err := NewLogLine(s)
+			require.NoError(t, err)
+			channels[i] <- l
+		}
+		close(channels[i])
+	}
+	output := make(chan *LogLine)
+	go func() {
+		MergeLogs(context.Background(), channels, output, window)
+		close(output)
+	}()
+	var msgs []string
+	for l := range output {
+		msgs = append(msgs, l.Msg)
+	}
+	return msgs
+}
+
+func TestMergeLogs(t *testing.T) {
+	a := []string{
+		"2023-05-01T10:00:00.000000000Z stdout F a1",
+		"2023-05-01T10:00:03.000000000Z stdout F a3",
+		"2023-05-01T10:00:04.000000000Z stdout F a4",
+	}
+	b := []string{
+		"2023-05-01T10:00:01.000000000Z stdout F b1",
+		"2023-05-01T10:00:02.000000000Z stderr F b2",
+		"2023-05-01T10:00:05.000000000Z stdout F b5",
+	}
+	expected := []string{"a1", "b1", "b2", "a3", "a4", "b5"}
+	assert.Equal(t, expected, mergeLines(t, 0, a, b))
+	assert.Equal(t, expected, mergeLines(t, MergeWindow, a, b))
+	assert.Equal(t, []string{"a1", "a3", "a4"}, mergeLines(t, 0, a, nil))
+	assert.Empty(t, mergeLines(t, 0))
+
+	// The parts of a partial line are not separated.
+	c := []string{
+		"2023-05-01T10:00:00.500000000Z stdout P c",
+		"2023-05-01T10:00:03.500000000Z stdout F 1",
+	}
+	assert.Equal(t, []string{"a1", "c", "1", "b1", "b2", "a3", "a4", "b5"}, mergeLines(t, 0, a, b, c))
+}
+
+func TestMergeLogsFollow(t *testing.T) {
+	start := time.Now()
+	idle := make(chan *LogLine)
+	busy := make(chan *LogLine, 1)
+	output := make(chan *LogLine)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go MergeLogs(ctx, []chan *LogLine{idle, busy}, output, 100*time.Millisecond)
+
+	// Without a line of the idle input, the line is written after the
+	// window.
+	busy <- &LogLine{Time: start, Msg: "busy", ParseLogType: FullLogType}
+	select {
+	case l := <-output:
+		assert.Equal(t, "busy", l.Msg)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	case <-time.After(5 * time.Second):
+		t.Fatal("line was not written after the window")
+	}
+
+	// Lines of all inputs are ordered.
+	for i := 2; i >= 1; i-- {
+		l := &LogLine{Time: start.Add(time.Duration(i) * time.Second), Msg: fmt.Sprintf("line %d", i), ParseLogType: FullLogType}
+		if i == 1 {
+			idle <- l
+		} else {
+			busy <- l
+		}
+	}
+	for _, msg := range []string{"line 1", "line 2"} {
+		select {
+		case l := <-output:
+			assert.Equal(t, msg, l.Msg)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s was not written", msg)
+		}
+	}
+}
+
+func TestMergeLogsCancel(t *testing.T) {
+	input := make(chan *LogLine)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		MergeLogs(ctx, []chan *LogLine{input, make(chan *LogLine)}, make(chan *LogLine), 0)
+		close(done)
+	}()
+
+	input <- &LogLine{Time: time.Now(), Msg: "line", ParseLogType: FullLogType}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("merge did not return after the context was done")
+	}
+
+	// The writer of the input is not blocked after the merge returned.
+	for i := 0; i < 10; i++ {
+		select {
+		case input <- &LogLine{Time: time.Now(), Msg: "line", ParseLogType: FullLogType}:
+		case <-time.After(5 * time.Second):
+			t.Fatal("writer of the input is blocked")
+		}
+	}
+	close(input)
+}
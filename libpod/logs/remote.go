@@ -0,0 +1,285 @@
+package logs
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LogModeOption is the log option selecting whether the syslog and
+	// fluentd log drivers keep a local copy of the log.
+	LogModeOption = "mode"
+	// DualLogMode keeps a local copy of the log in the k8s-file format
+	// along with sending it to the remote destination, so that it can be
+	// read by podman logs.
+	DualLogMode = "dual"
+	// RemoteLogMode only sends the log to the remote destination.  It is
+	// the default.
+	RemoteLogMode = "remote"
+
+	// The suffixes of the log options of the syslog and fluentd log
+	// drivers, which are prefixed with the name of the driver.
+	addressOptionSuffix     = "address"
+	bufferLimitOptionSuffix = "buffer-limit"
+	retryWaitOptionSuffix   = "retry-wait"
+	maxRetriesOptionSuffix  = "max-retries"
+
+	// defaultBufferLimit is the default number of log lines buffered while
+	// the remote destination is not reachable.
+	defaultBufferLimit = 8192
+	// defaultRetryWait is the default time to wait before reconnecting.
+	defaultRetryWait = time.Second
+	// defaultMaxRetries is the default number of failed attempts to send a
+	// log line, after which the buffered lines are dropped.
+	defaultMaxRetries = 10
+	// remoteFlushTimeout is the maximum time to send the buffered log lines
+	// when closing a remote log writer.
+	remoteFlushTimeout = 10 * time.Second
+	// remoteIOTimeout is the maximum time to connect to the remote
+	// destination or to send a log line.
+	remoteIOTimeout = 10 * time.Second
+)
+
+// IsDualLogMode returns true if the log options select the dual mode.
+func IsDualLogMode(options map[string]string) bool {
+	return options[LogModeOption] == DualLogMode
+}
+
+// ValidateLogOptions returns an error if the driver specific log options are
+// invalid.
+func ValidateLogOptions(driver string, options map[string]string) error {
+	var err error
+	switch driver {
+	case define.JSONLogging:
+		_, _, err = ParseJSONFileOptions(options)
+	case define.SyslogLogging:
+		_, err = parseSyslogOptions(options)
+	case define.FluentdLogging:
+		_, err = parseFluentdOptions(options)
+	}
+	return err
+}
+
+// remoteOptions are the log options shared by the syslog and fluentd log
+// drivers.
+type remoteOptions struct {
+	// Address of the remote destination.
+	Address string
+	// BufferLimit is the maximum number of buffered log lines.  If the
+	// buffer is full, the oldest line is dropped.
+	BufferLimit int
+	// RetryWait is the time to wait before reconnecting.
+	RetryWait time.Duration
+	// MaxRetries is the number of failed attempts to send a log line
+	// after which the buffered lines are dropped.  Zero retries forever.
+	MaxRetries int
+}
+
+// parseRemoteOptions parses the log options shared by the remote log drivers,
+// which are prefixed by the driver name.  Options with the prefix which are
+// not in known are rejected.
+func parseRemoteOptions(driver string, options map[string]string, known ...string) (*remoteOptions, error) {
+	opts := &remoteOptions{
+		BufferLimit: defaultBufferLimit,
+		RetryWait:   defaultRetryWait,
+		MaxRetries:  defaultMaxRetries,
+	}
+	prefix := driver + "-"
+	for key, value := range options {
+		var err error
+		switch key {
+		case LogModeOption:
+			if value != DualLogMode && value != RemoteLogMode {
+				err = fmt.Errorf("must be %s or %s", RemoteLogMode, DualLogMode)
+			}
+		case prefix + addressOptionSuffix:
+			opts.Address = value
+		case prefix + bufferLimitOptionSuffix:
+			opts.BufferLimit, err = strconv.Atoi(value)
+			if err == nil && opts.BufferLimit < 1 {
+				err = fmt.Errorf("must be a positive integer")
+			}
+		case prefix + retryWaitOptionSuffix:
+			opts.RetryWait, err = time.ParseDuration(value)
+			if err == nil && opts.RetryWait <= 0 {
+				err = fmt.Errorf("must be a positive duration")
+			}
+		case prefix + maxRetriesOptionSuffix:
+			opts.MaxRetries, err = strconv.Atoi(value)
+			if err == nil && opts.MaxRetries < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+		default:
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			isKnown := false
+			for _, k := range known {
+				isKnown = isKnown || key == prefix+k
+			}
+			if !isKnown {
+				return nil, fmt.Errorf("unknown log option %s for the %s log driver", key, driver)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for log option %s: %w", value, key, err)
+		}
+	}
+	return opts, nil
+}
+
+// remoteWriter sends encoded log lines to a remote destination.  The lines are
+// buffered, so that writing them never blocks on the network.  If sending a
+// line fails, the writer reconnects after the retry wait.
+type remoteWriter struct {
+	options *remoteOptions
+	dial    func() (net.Conn, error)
+	encode  func(line *LogLine) ([]byte, error)
+
+	mutex sync.Mutex
+	cond  *sync.Cond
+	// buffer holds the encoded lines which were not yet sent.
+	buffer [][]byte
+	// first is the sequence number of the first line of the buffer.
+	first   uint64
+	dropped int
+	closing bool
+	done    chan struct{}
+}
+
+func newRemoteWriter(options *remoteOptions, dial func() (net.Conn, error), encode func(line *LogLine) ([]byte, error)) *remoteWriter {
+	w := &remoteWriter{
+		options: options,
+		dial:    dial,
+		encode:  encode,
+		done:    make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mutex)
+	go w.send()
+	return w
+}
+
+// WriteLine adds the log line to the buffer of lines to send.  If the buffer
+// is full, the oldest line is dropped.
+func (w *remoteWriter) WriteLine(line *LogLine) error {
+	msg, err := w.encode(line)
+	if err != nil {
+		return err
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if len(w.buffer) >= w.options.BufferLimit {
+		w.buffer = w.buffer[1:]
+		w.first++
+		w.dropped++
+	}
+	w.buffer = append(w.buffer, msg)
+	w.cond.Signal()
+	return nil
+}
+
+// Close sends the buffered lines, waiting at most for the flush timeout, and
+// closes the connection.
+func (w *remoteWriter) Close() error {
+	w.mutex.Lock()
+	w.closing = true
+	w.cond.Signal()
+	w.mutex.Unlock()
+	select {
+	case <-w.done:
+		return nil
+	case <-time.After(remoteFlushTimeout):
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		return fmt.Errorf("timed out sending %d buffered log lines to %s", len(w.buffer), w.options.Address)
+	}
+}
+
+// next waits for a line to send and returns it along with its sequence
+// number, or returns nil if the writer is closed and all lines were sent.
+func (w *remoteWriter) next() ([]byte, uint64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for len(w.buffer) == 0 && !w.closing {
+		w.cond.Wait()
+	}
+	if w.dropped > 0 {
+		logrus.Warnf("Dropped %d log lines for %s because the buffer was full", w.dropped, w.options.Address)
+		w.dropped = 0
+	}
+	if len(w.buffer) == 0 {
+		return nil, 0
+	}
+	return w.buffer[0], w.first
+}
+
+// sent removes the sent line from the buffer unless it was dropped in the
+// meantime.
+func (w *remoteWriter) sent(seq uint64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if len(w.buffer) > 0 && w.first == seq {
+		w.buffer = w.buffer[1:]
+		w.first++
+	}
+}
+
+// drop removes all buffered lines.
+func (w *remoteWriter) drop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	logrus.Errorf("Dropped %d log lines for %s after %d failed attempts to send them", len(w.buffer), w.options.Address, w.options.MaxRetries)
+	w.first += uint64(len(w.buffer))
+	w.buffer = nil
+}
+
+// send sends the buffered lines until the writer is closed.
+func (w *remoteWriter) send() {
+	defer close(w.done)
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+	failures := 0
+	for {
+		msg, seq := w.next()
+		if msg == nil {
+			return
+		}
+		var err error
+		if conn == nil {
+			conn, err = w.dial()
+		}
+		if err == nil {
+			if err = conn.SetWriteDeadline(time.Now().Add(remoteIOTimeout)); err == nil {
+				_, err = conn.Write(msg)
+			}
+		}
+		if err == nil {
+			w.sent(seq)
+			failures = 0
+			continue
+		}
+
+		logrus.Debugf("Sending log line to %s: %v", w.options.Address, err)
+		if conn != nil {
+			conn.Close()
+			conn = nil
+		}
+		failures++
+		if w.options.MaxRetries > 0 && failures >= w.options.MaxRetries {
+			w.drop()
+			failures = 0
+		}
+		time.Sleep(w.options.RetryWait)
+	}
+}
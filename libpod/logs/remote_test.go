@@ -0,0 +1,160 @@
+package logs
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLogOptions(t *testing.T) {
+	for _, test := range []struct {
+		driver  string
+		options map[string]string
+		valid   bool
+	}{
+		{define.SyslogLogging, nil, true},
+		{define.SyslogLogging, map[string]string{"tag": "foo", "mode": "dual", "syslog-address": "tcp://localhost", "syslog-facility": "local3"}, true},
+		{define.SyslogLogging, map[string]string{"syslog-address": "udp://127.0.0.1:1514", "syslog-buffer-limit": "10", "syslog-retry-wait": "10ms", "syslog-max-retries": "0"}, true},
+		{define.SyslogLogging, map[string]string{"syslog-address": "unix:///dev/log"}, true},
+		{define.SyslogLogging, map[string]string{"syslog-address": "http://localhost"}, false},
+		{define.SyslogLogging, map[string]string{"syslog-address": "tcp://"}, false},
+		{define.SyslogLogging, map[string]string{"syslog-facility": "nope"}, false},
+		{define.SyslogLogging, map[string]string{"syslog-adress": "tcp://localhost"}, false},
+		{define.SyslogLogging, map[string]string{"mode": "blocking"}, false},
+		{define.FluentdLogging, map[string]string{"fluentd-address": "fluentd.example.com"}, true},
+		{define.FluentdLogging, map[string]string{"fluentd-address": "tcp://[::1]:24224", "mode": "remote"}, true},
+		{define.FluentdLogging, map[string]string{"fluentd-address": "unix:///run/fluentd.sock"}, true},
+		{define.FluentdLogging, map[string]string{"fluentd-address": "udp://localhost"}, false},
+		{define.FluentdLogging, map[string]string{"fluentd-address": "tcp://"}, false},
+		{define.FluentdLogging, map[string]string{"fluentd-buffer-limit": "0"}, false},
+		{define.FluentdLogging, map[string]string{"fluentd-retry-wait": "1"}, false},
+		{define.FluentdLogging, map[string]string{"fluentd-max-retries": "-1"}, false},
+		{define.FluentdLogging, map[string]string{"syslog-facility": "nope"}, true},
+		{define.JSONLogging, map[string]string{"max-file": "0"}, false},
+		{define.KubernetesLogging, map[string]string{"max-file": "0"}, true},
+	} {
+		err := ValidateLogOptions(test.driver, test.options)
+		if test.valid {
+			assert.NoError(t, err, "%s %v", test.driver, test.options)
+		} else {
+			assert.Error(t, err, "%s %v", test.driver, test.options)
+		}
+	}
+}
+
+func testLogLine(device, logType, msg string) *LogLine {
+	return &LogLine{
+		Device:       device,
+		ParseLogType: logType,
+		Time:         time.Date(2023, 5, 1, 10, 0, 0, 123456789, time.UTC),
+		Msg:          msg,
+	}
+}
+
+func TestFormatSyslogMessage(t *testing.T) {
+	msg := formatSyslogMessage(testLogLine(StderrStream, FullLogType, "hello world"), syslogFacilities["local0"], "host", "my app")
+	assert.Equal(t, "<131>1 2023-05-01T10:00:00.123456Z host my_app - stderr - hello world", string(msg))
+
+	msg = formatSyslogMessage(testLogLine(StdoutStream, FullLogType, ""), syslogFacilities["daemon"], "", strings.Repeat("x", 60))
+	assert.Equal(t, "<30>1 2023-05-01T10:00:00.123456Z - "+strings.Repeat("x", 48)+" - stdout - ", string(msg))
+}
+
+func TestSyslogWriter(t *testing.T) {
+	// Datagrams are sent as is.
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udp.Close()
+	w, err := newSyslogWriter(map[string]string{SyslogAddressOption: "udp://" + udp.LocalAddr().String()}, "tag")
+	require.NoError(t, err)
+	require.NoError(t, w.WriteLine(testLogLine(StdoutStream, FullLogType, "over udp")))
+	require.NoError(t, w.Close())
+	buf := make([]byte, 1024)
+	require.NoError(t, udp.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := udp.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Regexp(t, `^<30>1 2023-05-01T10:00:00.123456Z \S+ tag - stdout - over udp$`, string(buf[:n]))
+
+	// Messages sent over streams are framed by octet counting.
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer tcp.Close()
+	w, err = newSyslogWriter(map[string]string{SyslogAddressOption: "tcp://" + tcp.Addr().String()}, "tag")
+	require.NoError(t, err)
+	require.NoError(t, w.WriteLine(testLogLine(StdoutStream, FullLogType, "over tcp")))
+	conn, err := tcp.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, w.Close())
+	frame, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	msg := formatSyslogMessage(testLogLine(StdoutStream, FullLogType, "over tcp"), syslogFacilities["daemon"], hostname, "tag")
+	assert.Equal(t, strconv.Itoa(len(msg))+" "+string(msg), string(frame))
+}
+
+func TestEncodeFluentdMessage(t *testing.T) {
+	msg := encodeFluentdMessage("tag", testLogLine(StdoutStream, FullLogType, "hi"), map[string]string{"log": "hi", "source": "stdout"})
+	expected := []byte{
+		0x93,
+		0xa3, 't', 'a', 'g',
+		0xd7, 0x00, 0x64, 0x4f, 0x8d, 0xa0, 0x07, 0x5b, 0xcd, 0x15,
+		0x82,
+		0xa3, 'l', 'o', 'g', 0xa2, 'h', 'i',
+		0xa6, 's', 'o', 'u', 'r', 'c', 'e', 0xa6, 's', 't', 'd', 'o', 'u', 't',
+	}
+	assert.Equal(t, expected, msg)
+
+	assert.Equal(t, []byte{0xd9, 32}, appendMsgpackString(nil, strings.Repeat("x", 32))[:2])
+	assert.Equal(t, []byte{0xda, 0x01, 0x00}, appendMsgpackString(nil, strings.Repeat("x", 256))[:3])
+	assert.Equal(t, []byte{0xdb, 0x00, 0x01, 0x00, 0x00}, appendMsgpackString(nil, strings.Repeat("x", 1<<16))[:5])
+}
+
+func TestFluentdWriterReconnect(t *testing.T) {
+	// Reserve an address on which nothing listens yet.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	w, err := newFluentdWriter(map[string]string{
+		FluentdAddressOption:   address,
+		"fluentd-retry-wait":   "10ms",
+		"fluentd-max-retries":  "0",
+		"fluentd-buffer-limit": "2",
+	}, "tag", "id", "name")
+	require.NoError(t, err)
+	// The oldest line is dropped from the full buffer.
+	for _, msg := range []string{"dropped", "first", "second"} {
+		require.NoError(t, w.WriteLine(testLogLine(StdoutStream, FullLogType, msg)))
+	}
+	// Wait for failed attempts to connect.
+	time.Sleep(50 * time.Millisecond)
+
+	l, err = net.Listen("tcp", address)
+	require.NoError(t, err)
+	defer l.Close()
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, w.Close())
+
+	record := func(msg string) map[string]string {
+		return map[string]string{"container_id": "id", "container_name": "name", "source": StdoutStream, "log": msg}
+	}
+	var expected []byte
+	expected = append(expected, encodeFluentdMessage("tag", testLogLine(StdoutStream, FullLogType, "first"), record("first"))...)
+	expected = append(expected, encodeFluentdMessage("tag", testLogLine(StdoutStream, FullLogType, "second"), record("second"))...)
+	received, err := io.ReadAll(bufio.NewReader(conn))
+	require.NoError(t, err)
+	assert.Equal(t, expected, received)
+}
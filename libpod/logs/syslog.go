@@ -0,0 +1,214 @@
+package logs
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/containers/podman/v4/libpod/define"
+)
+
+const (
+	// SyslogAddressOption is the log option setting the address of the
+	// syslog server.
+	SyslogAddressOption = define.SyslogLogging + "-" + addressOptionSuffix
+	// SyslogFacilityOption is the log option setting the syslog facility.
+	SyslogFacilityOption = define.SyslogLogging + "-facility"
+
+	// defaultSyslogPort is the port of syslog servers if the address does
+	// not specify one.
+	defaultSyslogPort = "514"
+	// syslogTimeFormat is the RFC 5424 timestamp format with the maximum
+	// precision of microseconds.
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+	// maxSyslogAppName is the maximum length of the RFC 5424 APP-NAME.
+	maxSyslogAppName = 48
+	// syslogSeverityInfo and syslogSeverityErr are the severities of
+	// lines logged to stdout and stderr respectively.
+	syslogSeverityErr  = 3
+	syslogSeverityInfo = 6
+)
+
+// syslogFacilities are the syslog facilities by name.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// localSyslogSockets are the sockets of the local syslog daemon.
+var localSyslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// syslogOptions are the log options of the syslog log driver.
+type syslogOptions struct {
+	*remoteOptions
+	// network and addr of the syslog server.  The network is empty for
+	// the local syslog daemon.
+	network  string
+	addr     string
+	facility int
+}
+
+// parseSyslogOptions parses the log options of the syslog log driver.
+func parseSyslogOptions(options map[string]string) (*syslogOptions, error) {
+	remote, err := parseRemoteOptions(define.SyslogLogging, options, "facility")
+	if err != nil {
+		return nil, err
+	}
+	opts := &syslogOptions{
+		remoteOptions: remote,
+		facility:      syslogFacilities["daemon"],
+	}
+	if value, ok := options[SyslogFacilityOption]; ok {
+		facility, ok := syslogFacilities[value]
+		if !ok {
+			return nil, fmt.Errorf("invalid value %q for log option %s: unknown syslog facility", value, SyslogFacilityOption)
+		}
+		opts.facility = facility
+	}
+	if remote.Address == "" {
+		remote.Address = "local syslog"
+		return opts, nil
+	}
+	u, err := url.Parse(remote.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q for log option %s: %w", remote.Address, SyslogAddressOption, err)
+	}
+	switch u.Scheme {
+	case "unix", "unixgram":
+		opts.network, opts.addr = u.Scheme, u.Path
+	case "udp", "tcp":
+		opts.network, opts.addr = u.Scheme, u.Host
+		if u.Hostname() == "" {
+			opts.addr = ""
+		} else if u.Port() == "" {
+			opts.addr = net.JoinHostPort(u.Hostname(), defaultSyslogPort)
+		}
+	default:
+		return nil, fmt.Errorf("invalid value %q for log option %s: must be a unix://, unixgram://, udp:// or tcp:// URL", remote.Address, SyslogAddressOption)
+	}
+	if opts.addr == "" {
+		return nil, fmt.Errorf("invalid value %q for log option %s: missing address", remote.Address, SyslogAddressOption)
+	}
+	return opts, nil
+}
+
+// newSyslogWriter returns a writer sending the log lines as RFC 5424 messages
+// with the tag as APP-NAME to the syslog server of the log options.
+func newSyslogWriter(options map[string]string, tag string) (*remoteWriter, error) {
+	opts, err := parseSyslogOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	encode := func(line *LogLine) ([]byte, error) {
+		return formatSyslogMessage(line, opts.facility, hostname, tag), nil
+	}
+	return newRemoteWriter(opts.remoteOptions, opts.dial, encode), nil
+}
+
+// dial connects to the syslog server.  Messages sent over stream sockets are
+// framed by octet counting as specified by RFC 6587.
+func (o *syslogOptions) dial() (net.Conn, error) {
+	var conn net.Conn
+	var err error
+	stream := o.network == "tcp"
+	switch o.network {
+	case "":
+		for _, path := range localSyslogSockets {
+			if conn, stream, err = dialUnix(path); err == nil {
+				break
+			}
+		}
+	case "unix":
+		conn, stream, err = dialUnix(o.addr)
+	default:
+		conn, err = net.DialTimeout(o.network, o.addr, remoteIOTimeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stream {
+		conn = &octetCountingConn{Conn: conn}
+	}
+	return conn, nil
+}
+
+// dialUnix connects to a unix datagram socket or, if that fails, a unix
+// stream socket.  It returns true if the connection is a stream.
+func dialUnix(path string) (net.Conn, bool, error) {
+	conn, err := net.DialTimeout("unixgram", path, remoteIOTimeout)
+	if err == nil {
+		return conn, false, nil
+	}
+	conn, err = net.DialTimeout("unix", path, remoteIOTimeout)
+	return conn, true, err
+}
+
+// octetCountingConn prefixes every message with its length.
+type octetCountingConn struct {
+	net.Conn
+}
+
+func (c *octetCountingConn) Write(p []byte) (int, error) {
+	frame := append([]byte(strconv.Itoa(len(p))+" "), p...)
+	if _, err := c.Conn.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// formatSyslogMessage returns the RFC 5424 message of the log line.  The
+// stream is used as MSGID, and lines logged to stderr have the severity
+// error instead of info.
+func formatSyslogMessage(line *LogLine, facility int, hostname, tag string) []byte {
+	severity := syslogSeverityInfo
+	if line.Device == StderrStream {
+		severity = syslogSeverityErr
+	}
+	return []byte(fmt.Sprintf("<%d>1 %s %s %s - %s - %s",
+		facility*8+severity,
+		line.Time.Format(syslogTimeFormat),
+		syslogHeaderField(hostname, 255),
+		syslogHeaderField(tag, maxSyslogAppName),
+		syslogHeaderField(line.Device, 32),
+		line.Msg))
+}
+
+// syslogHeaderField returns the value as field of the RFC 5424 header, which
+// consists of at most maxLen printable ASCII characters and is "-" if empty.
+func syslogHeaderField(value string, maxLen int) string {
+	field := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, value)
+	if len(field) > maxLen {
+		field = field[:maxLen]
+	}
+	if field == "" {
+		return "-"
+	}
+	return field
+}
@@ -1039,7 +1039,7 @@ func (r *ConmonOCIRuntime) getLogTag(ctr *Container) (string, error) {
 }
 
 // createOCIContainer generates this container's main conmon instance and prepares it for starting
-func (r *ConmonOCIRuntime) createOCIContainer(ctr *Container, restoreOptions *ContainerCheckpointOptions) (int64, error) {
+func (r *ConmonOCIRuntime) createOCIContainer(ctr *Container, restoreOptions *ContainerCheckpointOptions) (_ int64, retErr error) {
 	var stderrBuf bytes.Buffer
 
 	runtimeDir, err := util.GetRuntimeDir()
@@ -1081,7 +1081,26 @@ func (r *ConmonOCIRuntime) createOCIContainer(ctr *Container, restoreOptions *Co
 		pidfile = filepath.Join(ctr.state.RunDir, "pidfile")
 	}
 
-	args := r.sharedConmonArgs(ctr, ctr.ID(), ctr.bundlePath(), pidfile, ctr.LogPath(), r.exitsDir, ociLog, ctr.LogDriver(), logTag)
+	logPath := ctr.LogPath()
+	var forwarder *os.Process
+	if usesLogForwarder(ctr.logFormatDriver()) {
+		// conmon writes the k8s-file log to a FIFO from which the log
+		// forwarder writes the log of the driver.
+		logPath = ctr.logFifoPath()
+		forwarder, err = r.startLogForwarder(ctr, logPath, logTag)
+		if err != nil {
+			return 0, err
+		}
+		defer func() {
+			if retErr != nil {
+				if err := forwarder.Kill(); err != nil {
+					logrus.Errorf("Killing log forwarder of container %s: %v", ctr.ID(), err)
+				}
+			}
+		}()
+	}
+
+	args := r.sharedConmonArgs(ctr, ctr.ID(), ctr.bundlePath(), pidfile, logPath, r.exitsDir, ociLog, ctr.logFormatDriver(), logTag)
 
 	if ctr.config.SdNotifyMode == define.SdNotifyModeContainer && ctr.config.SdNotifySocket != "" {
 		args = append(args, fmt.Sprintf("--sdnotify-socket=%s", ctr.config.SdNotifySocket))
@@ -1287,6 +1306,9 @@ func (r *ConmonOCIRuntime) createOCIContainer(ctr *Container, restoreOptions *Co
 		// conmon not having a pid file is a valid state, so don't set it if we don't have it
 		logrus.Infof("Got Conmon PID as %d", conmonPID)
 		ctr.state.ConmonPID = conmonPID
+		if forwarder != nil {
+			moveLogForwarderToConmonCgroup(ctr, conmonPID, forwarder)
+		}
 	}
 
 	runtimeRestoreDuration := func() int64 {
@@ -1305,6 +1327,35 @@ func (r *ConmonOCIRuntime) createOCIContainer(ctr *Container, restoreOptions *Co
 	return runtimeRestoreDuration, nil
 }
 
+// startLogForwarder starts the process forwarding the log written by conmon to
+// the FIFO to the destination of the log driver.  The log tag defaults to the
+// short container ID.
+func (r *ConmonOCIRuntime) startLogForwarder(ctr *Container, fifoPath, logTag string) (*os.Process, error) {
+	maxFile, compress, err := logs.ParseJSONFileOptions(ctr.config.LogOptions)
+	if err != nil {
+		return nil, err
+	}
+	size := r.logSizeMax
+	if ctr.config.LogSize > 0 {
+		size = ctr.config.LogSize
+	}
+	if logTag == "" {
+		logTag = ctr.ID()[:12]
+	}
+	return logs.StartForwarder(&logs.ForwarderConfig{
+		ContainerID:   ctr.ID(),
+		ContainerName: ctr.Name(),
+		Driver:        ctr.logFormatDriver(),
+		Options:       ctr.config.LogOptions,
+		Tag:           logTag,
+		Path:          ctr.LogPath(),
+		MaxSize:       size,
+		MaxFile:       maxFile,
+		Compress:      compress,
+		LogLevel:      logrus.GetLevel().String(),
+	}, fifoPath)
+}
+
 // configureConmonEnv gets the environment values to add to conmon's exec struct
 // TODO this may want to be less hardcoded/more configurable in the future
 func (r *ConmonOCIRuntime) configureConmonEnv(runtimeDir string) []string {
@@ -1370,14 +1421,16 @@ func (r *ConmonOCIRuntime) sharedConmonArgs(ctr *Container, cuuid, bundlePath, p
 		logDriverArg = define.PassthroughLogging
 	//lint:ignore ST1015 the default case has to be here
 	default: //nolint:gocritic
-		// No case here should happen except JSONLogging, but keep this here in case the options are extended
+		// No case here should happen, but keep this here in case the options are extended
 		logrus.Errorf("%s logging specified but not supported. Choosing k8s-file logging instead", ctr.LogDriver())
 		fallthrough
 	case "":
 		// to get here, either a user would specify `--log-driver ""`, or this came from another place in libpod
 		// since the former case is obscure, and the latter case isn't an error, let's silently fallthrough
 		fallthrough
-	case define.JSONLogging:
+	case define.JSONLogging, define.SyslogLogging, define.FluentdLogging:
+		// conmon writes to the FIFO of the log forwarder, which
+		// rotates the log file itself.
 		fallthrough
 	case define.KubernetesLogging:
 		logDriverArg = fmt.Sprintf("%s:%s", define.KubernetesLogging, logPath)
@@ -1394,7 +1447,7 @@ func (r *ConmonOCIRuntime) sharedConmonArgs(ctr *Container, cuuid, bundlePath, p
 	if ctr.config.LogSize > 0 {
 		size = ctr.config.LogSize
 	}
-	if size > 0 {
+	if size > 0 && !usesLogForwarder(logDriver) {
 		args = append(args, "--log-size-max", fmt.Sprintf("%v", size))
 	}
 
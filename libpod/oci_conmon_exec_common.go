@@ -691,7 +691,7 @@ func (c *Container) prepareProcessExec(options *ExecOptions, env []string, sessi
 		return nil, err
 	}
 	for name, secr := range c.config.EnvSecrets {
-		_, data, err := manager.LookupSecretData(secr.Name)
+		data, err := c.lookupSecretData(manager, secr.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -25,3 +25,7 @@ func (r *ConmonOCIRuntime) moveConmonToCgroupAndSignal(ctr *Container, cmd *exec
 	}
 	return nil
 }
+
+// moveLogForwarderToConmonCgroup is a no-op on FreeBSD which has no cgroups.
+func moveLogForwarderToConmonCgroup(ctr *Container, conmonPid int, forwarder *os.Process) {
+}
@@ -168,6 +168,28 @@ func (r *ConmonOCIRuntime) moveConmonToCgroupAndSignal(ctr *Container, cmd *exec
 	return nil
 }
 
+// moveLogForwarderToConmonCgroup moves the log forwarder of the container to
+// the cgroup of conmon.  Otherwise, it would be killed along with the cgroup
+// of this process (e.g., when the login session ends) while conmon keeps
+// writing the log of the container to its FIFO.
+func moveLogForwarderToConmonCgroup(ctr *Container, conmonPid int, forwarder *os.Process) {
+	conmonCgroup, err := utils.GetCgroupProcess(conmonPid)
+	if err != nil {
+		logrus.Warnf("Getting cgroup of conmon of container %s: %v", ctr.ID(), err)
+		return
+	}
+	if forwarderCgroup, err := utils.GetCgroupProcess(forwarder.Pid); err == nil && forwarderCgroup == conmonCgroup {
+		return
+	}
+	logLevel := logrus.WarnLevel
+	if rootless.IsRootless() {
+		logLevel = logrus.InfoLevel
+	}
+	if err := utils.MoveUnderCgroupOfProcess(conmonPid, []uint32{uint32(forwarder.Pid)}); err != nil {
+		logrus.StandardLogger().Logf(logLevel, "Failed to move log forwarder of container %s to the cgroup of conmon: %v", ctr.ID(), err)
+	}
+}
+
 // GetLimits converts spec resource limits to cgroup consumable limits
 func GetLimits(resource *spec.LinuxResources) (runcconfig.Resources, error) {
 	if resource == nil {
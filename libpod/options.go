@@ -533,9 +533,55 @@ func WithMigrateRuntime(requestedRuntime string) RuntimeOption {
 	}
 }
 
+// WithMigrateDB instructs libpod to copy the BoltDB state into a new SQLite
+// database during a migration and to switch the database backend to SQLite.
+// The BoltDB state is left untouched, so that the migration can be rolled
+// back. This is not used if `WithMigrate()` is not also passed.
+func WithMigrateDB() RuntimeOption {
+	return func(rt *Runtime) error {
+		if rt.valid {
+			return define.ErrRuntimeFinalized
+		}
+
+		rt.migrateDB = true
+
+		return nil
+	}
+}
+
+// WithRollbackDB instructs libpod to switch the database backend back to
+// BoltDB during a migration, undoing an earlier `WithMigrateDB()`. This is not
+// used if `WithMigrate()` is not also passed.
+func WithRollbackDB() RuntimeOption {
+	return func(rt *Runtime) error {
+		if rt.valid {
+			return define.ErrRuntimeFinalized
+		}
+
+		rt.rollbackDB = true
+
+		return nil
+	}
+}
+
+// WithMigrateDryRun instructs libpod to only report what a migration of the
+// database backend would do, without stopping containers or changing the
+// database backend. This is not used if `WithMigrate()` is not also passed.
+func WithMigrateDryRun() RuntimeOption {
+	return func(rt *Runtime) error {
+		if rt.valid {
+			return define.ErrRuntimeFinalized
+		}
+
+		rt.migrateDryRun = true
+
+		return nil
+	}
+}
+
 // WithEventsLogger sets the events backend to use.
-// Currently supported values are "file" for file backend and "journald" for
-// journald backend.
+// Currently supported values are "file" for file backend, "journald" for
+// journald backend and "sqlite" for the SQLite backend.
 func WithEventsLogger(logger string) RuntimeOption {
 	return func(rt *Runtime) error {
 		if rt.valid {
@@ -1138,7 +1184,7 @@ func WithLogDriver(driver string) CtrCreateOption {
 		switch driver {
 		case "":
 			return fmt.Errorf("log driver must be set: %w", define.ErrInvalidArg)
-		case define.JournaldLogging, define.KubernetesLogging, define.JSONLogging, define.NoLogging, define.PassthroughLogging:
+		case define.JournaldLogging, define.KubernetesLogging, define.JSONLogging, define.SyslogLogging, define.FluentdLogging, define.NoLogging, define.PassthroughLogging:
 			break
 		default:
 			return fmt.Errorf("invalid log driver: %w", define.ErrInvalidArg)
@@ -1182,6 +1228,22 @@ func WithLogTag(tag string) CtrCreateOption {
 	}
 }
 
+// WithLogOptions sets the log driver specific options.
+func WithLogOptions(options map[string]string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+
+		ctr.config.LogOptions = make(map[string]string, len(options))
+		for key, value := range options {
+			ctr.config.LogOptions[key] = value
+		}
+
+		return nil
+	}
+}
+
 // WithCgroupsMode disables the creation of Cgroups for the conmon process.
 func WithCgroupsMode(mode string) CtrCreateOption {
 	return func(ctr *Container) error {
@@ -1967,6 +2029,59 @@ func WithStartupHealthcheck(startupHC *define.StartupHealthCheck) CtrCreateOptio
 	}
 }
 
+// WithHealthLogDestination sets the destination of the container's
+// healthcheck log.
+func WithHealthLogDestination(destination string) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+		if err := define.ValidateHealthCheckLogDestination(destination); err != nil {
+			return err
+		}
+		ctr.config.HealthLogDestination = destination
+		return nil
+	}
+}
+
+// WithHealthMaxLogCount sets the maximum number of entries of the container's
+// healthcheck log file.  0 means unlimited.
+func WithHealthMaxLogCount(maxCount uint) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+		ctr.config.HealthMaxLogCount = &maxCount
+		return nil
+	}
+}
+
+// WithHealthMaxLogSize sets the maximum number of bytes of the output of a
+// healthcheck stored in the container's healthcheck log.  0 means unlimited.
+func WithHealthMaxLogSize(maxSize uint) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+		ctr.config.HealthMaxLogSize = &maxSize
+		return nil
+	}
+}
+
+// WithReadinessCheck sets a readiness check for the container.
+func WithReadinessCheck(readinessCheck *define.ReadinessCheck) CtrCreateOption {
+	return func(ctr *Container) error {
+		if ctr.valid {
+			return define.ErrCtrFinalized
+		}
+		ctr.config.ReadinessCheckConfig = new(define.ReadinessCheck)
+		if err := JSONDeepCopy(readinessCheck, ctr.config.ReadinessCheckConfig); err != nil {
+			return fmt.Errorf("error copying readiness check into container: %w", err)
+		}
+		return nil
+	}
+}
+
 // Pod Creation Options
 
 // WithPodCreateCommand adds the full command plus arguments of the current
@@ -2292,6 +2407,31 @@ func WithServiceContainer(id string) PodCreateOption {
 	}
 }
 
+// WithPodKubeYAML sets the Kubernetes YAML the pod is created from.
+func WithPodKubeYAML(yaml string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return define.ErrPodFinalized
+		}
+
+		pod.config.KubeYAML = yaml
+		return nil
+	}
+}
+
+// WithPodKubePlayOptions sets the JSON-encoded options of `podman-play-kube`
+// the pod is created with.
+func WithPodKubePlayOptions(options string) PodCreateOption {
+	return func(pod *Pod) error {
+		if pod.valid {
+			return define.ErrPodFinalized
+		}
+
+		pod.config.KubePlayOptions = options
+		return nil
+	}
+}
+
 // WithPodResources sets resource limits to be applied to the pod's cgroup
 // these will be inherited by all containers unless overridden.
 func WithPodResources(resources specs.LinuxResources) PodCreateOption {
@@ -71,6 +71,18 @@ type PodConfig struct {
 	// life cycle of service which may be started via `podman-play-kube`.
 	ServiceContainerID string `json:"serviceContainerID,omitempty"`
 
+	// KubeYAML is the Kubernetes YAML the pod has been created from via
+	// `podman-play-kube`, i.e., the document of the pod along with the
+	// ConfigMaps of the YAML but without its Secrets.  It is only set for
+	// pods with auto-update annotations and allows for replaying the YAML
+	// when auto-updating the pod outside of systemd.
+	KubeYAML string `json:"kubeYAML,omitempty"`
+
+	// KubePlayOptions are the JSON-encoded options of
+	// `podman-play-kube` the pod has been created with.  They allow for
+	// replaying the KubeYAML with the same options.
+	KubePlayOptions string `json:"kubePlayOptions,omitempty"`
+
 	// Time pod was created
 	CreatedTime time.Time `json:"created"`
 
@@ -402,6 +414,19 @@ func (p *Pod) allContainers() ([]*Container, error) {
 	return p.runtime.state.PodContainers(p)
 }
 
+// KubeYAML returns the Kubernetes YAML the pod has been created from.  If the
+// pod has not been created via `podman-play-kube`, an empty string is returned.
+func (p *Pod) KubeYAML() string {
+	return p.config.KubeYAML
+}
+
+// KubePlayOptions returns the JSON-encoded options of `podman-play-kube` the
+// pod has been created with.  If the pod has not been created via
+// `podman-play-kube`, an empty string is returned.
+func (p *Pod) KubePlayOptions() string {
+	return p.config.KubePlayOptions
+}
+
 // HasInfraContainer returns whether the pod will create an infra container
 func (p *Pod) HasInfraContainer() bool {
 	return p.config.HasInfra
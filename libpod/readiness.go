@@ -0,0 +1,242 @@
+package libpod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultReadinessWaitInterval is the interval at which the readiness of a
+// dependency is checked if its readiness check has no interval.
+const defaultReadinessWaitInterval = time.Second
+
+// defaultReadinessWaitTimeout bounds the wait for a dependency to become ready
+// if its readiness check has no retries and could thus fail forever.
+const defaultReadinessWaitTimeout = 5 * time.Minute
+
+// ReadinessCheck runs the readiness check of the specified container and
+// updates the readiness of the container accordingly.  It returns
+// define.HealthCheckSuccess if the container is ready after the check and
+// define.HealthCheckFailure otherwise.
+func (r *Runtime) ReadinessCheck(ctx context.Context, name string) (define.HealthCheckStatus, error) {
+	container, err := r.LookupContainer(name)
+	if err != nil {
+		return define.HealthCheckContainerNotFound, fmt.Errorf("unable to look up %s to perform a readiness check: %w", name, err)
+	}
+
+	cstate, err := container.State()
+	if err != nil {
+		return define.HealthCheckInternalError, err
+	}
+	if cstate != define.ContainerStateRunning {
+		return define.HealthCheckContainerStopped, fmt.Errorf("container %s is not running", container.ID())
+	}
+	if container.config.ReadinessCheckConfig == nil {
+		return define.HealthCheckNotDefined, fmt.Errorf("container %s has no defined readiness check", container.ID())
+	}
+
+	if _, err := container.runReadinessCheck(ctx); err != nil {
+		return define.HealthCheckInternalError, err
+	}
+	ready, err := container.Ready()
+	if err != nil {
+		return define.HealthCheckInternalError, err
+	}
+	if !ready {
+		return define.HealthCheckFailure, nil
+	}
+	return define.HealthCheckSuccess, nil
+}
+
+// runReadinessCheck executes the readiness check of the container and
+// updates the readiness of the container.  It returns whether the check
+// succeeded, which does not imply that the container is ready if more than
+// one success is required.
+func (c *Container) runReadinessCheck(ctx context.Context) (bool, error) {
+	config := c.config.ReadinessCheckConfig
+	command, probe, err := c.healthCheckCommand(config.Test)
+	if err != nil {
+		return false, err
+	}
+
+	timeStart := time.Now()
+	exitCode, output, err := c.execHealthCheck(ctx, command, probe, config.Timeout)
+	success := err == nil && exitCode == 0
+	if err != nil {
+		logrus.Debugf("Readiness check of container %s failed: %v", c.ID(), err)
+	} else if exitCode != 0 {
+		logrus.Debugf("Readiness check of container %s failed with exit code %d: %s", c.ID(), exitCode, output)
+	}
+	if config.Timeout > 0 && time.Since(timeStart) > config.Timeout {
+		logrus.Debugf("Readiness check of container %s exceeded timeout of %s", c.ID(), config.Timeout)
+		success = false
+	}
+
+	if err := c.updateReadiness(success); err != nil {
+		return success, err
+	}
+	return success, nil
+}
+
+// updateReadiness updates the readiness of the container after its readiness
+// check succeeded or failed.
+func (c *Container) updateReadiness(success bool) error {
+	if !c.batched {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if err := c.syncContainer(); err != nil {
+			return err
+		}
+	}
+
+	config := c.config.ReadinessCheckConfig
+	if success {
+		c.state.ReadinessFailureCount = 0
+		c.state.ReadinessSuccessCount++
+		if !c.state.Ready && (config.Successes == 0 || c.state.ReadinessSuccessCount >= config.Successes) {
+			logrus.Infof("Container %s passed its readiness check and is ready", c.ID())
+			c.state.Ready = true
+		}
+	} else {
+		c.state.ReadinessSuccessCount = 0
+		c.state.ReadinessFailureCount++
+		if c.state.Ready && (config.Retries == 0 || c.state.ReadinessFailureCount >= config.Retries) {
+			logrus.Infof("Container %s failed its readiness check and is not ready anymore", c.ID())
+			c.state.Ready = false
+		}
+	}
+
+	return c.save()
+}
+
+// isReady returns whether the container is ready.  A running container
+// without a readiness check is always ready.
+func (c *Container) isReady() bool {
+	if c.state.State != define.ContainerStateRunning {
+		return false
+	}
+	return c.config.ReadinessCheckConfig == nil || c.state.Ready
+}
+
+// resetReadiness marks the container as not ready.  It must be called when
+// the container is (re)started.
+func (c *Container) resetReadiness() {
+	c.state.Ready = false
+	c.state.ReadinessSuccessCount = 0
+	c.state.ReadinessFailureCount = 0
+}
+
+// waitForReady blocks until the container is ready.  As the readiness check
+// may not run periodically (e.g., on hosts without systemd), it is executed
+// directly.  An error is returned if the container stops or if the check
+// fails for more than the configured number of retries after the start
+// period.  The container must not be locked.
+func (c *Container) waitForReady(ctx context.Context) error {
+	config := c.config.ReadinessCheckConfig
+	if config == nil {
+		return nil
+	}
+	interval := config.Interval
+	if interval <= 0 {
+		interval = defaultReadinessWaitInterval
+	}
+
+	failures := 0
+	for {
+		ready, err := c.Ready()
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+
+		state, err := c.State()
+		if err != nil {
+			return err
+		}
+		if state != define.ContainerStateRunning {
+			return fmt.Errorf("container %s is not running and cannot become ready: %w", c.ID(), define.ErrCtrStateInvalid)
+		}
+
+		startedTime, err := c.StartedTime()
+		if err != nil {
+			return err
+		}
+		success, err := c.runReadinessCheck(ctx)
+		if err != nil {
+			return err
+		}
+		if !success && time.Since(startedTime) >= config.StartPeriod {
+			failures++
+			if config.Retries > 0 && failures >= config.Retries {
+				return fmt.Errorf("container %s did not become ready: readiness check failed %d times: %w", c.ID(), failures, define.ErrCtrStateInvalid)
+			}
+		}
+
+		ready, err = c.Ready()
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// readinessWaitTimeout returns how long to wait for a container with the
+// specified readiness check to become ready: the start period plus enough
+// time for the check to either succeed or exhaust its retries.
+func readinessWaitTimeout(config *define.ReadinessCheck) time.Duration {
+	if config.Retries <= 0 {
+		return config.StartPeriod + defaultReadinessWaitTimeout
+	}
+	interval := config.Interval
+	if interval <= 0 {
+		interval = defaultReadinessWaitInterval
+	}
+	checks := config.Retries + config.Successes
+	if config.Successes <= 0 {
+		checks++
+	}
+	return config.StartPeriod + time.Duration(checks)*(interval+config.Timeout)
+}
+
+// waitForDependenciesReady blocks until all containers required by the
+// container (i.e., --requires) which have a readiness check are ready.  The
+// wait for each dependency is bounded by readinessWaitTimeout.  Neither the
+// container nor its dependencies may be locked.
+func (c *Container) waitForDependenciesReady(ctx context.Context) error {
+	for _, dep := range c.config.Dependencies {
+		depCtr, err := c.runtime.state.Container(dep)
+		if err != nil {
+			return fmt.Errorf("retrieving dependency %s of container %s from state: %w", dep, c.ID(), err)
+		}
+		config := depCtr.config.ReadinessCheckConfig
+		if config == nil {
+			continue
+		}
+		timeout := readinessWaitTimeout(config)
+		logrus.Debugf("Waiting up to %s for dependency %s of container %s to become ready", timeout, dep, c.ID())
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		err = depCtr.waitForReady(waitCtx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				err = fmt.Errorf("timed out after %s: %w", timeout, define.ErrCtrStateInvalid)
+			}
+			return fmt.Errorf("waiting for dependency %s of container %s to become ready: %w", dep, c.ID(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package libpod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadinessWaitTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		config define.ReadinessCheck
+		want   time.Duration
+	}{
+		{
+			name: "no retries",
+			config: define.ReadinessCheck{Schema2HealthConfig: manifest.Schema2HealthConfig{
+				StartPeriod: 10 * time.Second,
+			}},
+			want: 10*time.Second + defaultReadinessWaitTimeout,
+		},
+		{
+			name: "retries",
+			config: define.ReadinessCheck{Schema2HealthConfig: manifest.Schema2HealthConfig{
+				Interval: 2 * time.Second,
+				Timeout:  time.Second,
+				Retries:  3,
+			}},
+			want: 4 * 3 * time.Second,
+		},
+		{
+			name: "retries and successes",
+			config: define.ReadinessCheck{
+				Schema2HealthConfig: manifest.Schema2HealthConfig{
+					StartPeriod: 5 * time.Second,
+					Retries:     2,
+				},
+				Successes: 3,
+			},
+			want: 5*time.Second + 5*defaultReadinessWaitInterval,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, readinessWaitTimeout(&tt.config))
+		})
+	}
+}
@@ -81,6 +81,10 @@ type Runtime struct {
 	workerChannel chan func()
 	workerGroup   sync.WaitGroup
 
+	// healthCheckScheduler runs healthchecks if systemd timers are not
+	// available.  Only set if started via StartHealthCheckScheduler.
+	healthCheckScheduler *healthCheckScheduler
+
 	// syslog describes whenever logrus should log to the syslog as well.
 	// Note that the syslog hook will be enabled early in cmd/podman/syslog_linux.go
 	// This bool is just needed so that we can set it for netavark interface.
@@ -101,6 +105,14 @@ type Runtime struct {
 	// We make no promises that these migrated containers work on the new
 	// runtime, though.
 	migrateRuntime string
+	// System migrate can also move the state from BoltDB to SQLite, or
+	// roll such a migration back, and merely report what would be done.
+	migrateDB     bool
+	rollbackDB    bool
+	migrateDryRun bool
+	// dbMigrationReport describes the migration of the database backend
+	// done during initialization, if any.
+	dbMigrationReport *DBMigrationReport
 
 	// valid indicates whether the runtime is ready to use.
 	// valid is set to true when a runtime is returned from GetRuntime(),
@@ -110,6 +122,9 @@ type Runtime struct {
 
 	// mechanism to read and write even logs
 	eventer events.Eventer
+	// eventForwarder forwards events to the sinks configured in
+	// containers.conf.  It is also the eventer if sinks are configured.
+	eventForwarder *events.ForwardingEventer
 
 	// noStore indicates whether we need to interact with a store or not
 	noStore bool
@@ -339,13 +354,7 @@ func makeRuntime(runtime *Runtime) (retErr error) {
 	}
 	switch backend {
 	case config.DBBackendBoltDB:
-		baseDir := runtime.config.Engine.StaticDir
-		if runtime.storageConfig.TransientStore {
-			baseDir = runtime.config.Engine.TmpDir
-		}
-		dbPath := filepath.Join(baseDir, "bolt_state.db")
-
-		state, err := NewBoltState(dbPath, runtime)
+		state, err := NewBoltState(boltStatePath(runtime), runtime)
 		if err != nil {
 			return err
 		}
@@ -656,8 +665,21 @@ func makeRuntime(runtime *Runtime) (retErr error) {
 	runtime.valid = true
 
 	if runtime.doMigrate {
-		if err := runtime.migrate(); err != nil {
-			return err
+		// A dry run must not stop any container.
+		if !runtime.migrateDryRun {
+			if err := runtime.migrate(); err != nil {
+				return err
+			}
+		}
+		switch {
+		case runtime.migrateDB:
+			if err := runtime.migrateDBToSqlite(); err != nil {
+				return err
+			}
+		case runtime.rollbackDB:
+			if err := runtime.rollbackDBToBoltDB(); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -779,6 +801,10 @@ func (r *Runtime) Shutdown(force bool) error {
 		return nil
 	}
 
+	if r.healthCheckScheduler != nil {
+		r.healthCheckScheduler.stop()
+	}
+
 	if r.workerChannel != nil {
 		r.workerGroup.Wait()
 		close(r.workerChannel)
@@ -817,6 +843,11 @@ func (r *Runtime) Shutdown(force bool) error {
 			lastError = fmt.Errorf("shutting down container storage: %w", err)
 		}
 	}
+	if r.eventForwarder != nil {
+		if err := r.eventForwarder.Close(eventSinksShutdownTimeout); err != nil {
+			logrus.Warnf("Forwarding events: %v", err)
+		}
+	}
 	if err := r.state.Close(); err != nil {
 		if lastError != nil {
 			logrus.Error(lastError)
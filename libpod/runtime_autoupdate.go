@@ -0,0 +1,27 @@
+package libpod
+
+import (
+	"github.com/containers/podman/v4/libpod/define"
+)
+
+// Contains the public Runtime API for auto-update records
+
+// AddAutoUpdateRecord adds a record of an update performed by auto-update to
+// the database.
+func (r *Runtime) AddAutoUpdateRecord(record *define.AutoUpdateRecord) error {
+	if !r.valid {
+		return define.ErrRuntimeStopped
+	}
+
+	return r.state.AddAutoUpdateRecord(record)
+}
+
+// AutoUpdateRecords returns all records of updates performed by auto-update
+// in the order they were performed.
+func (r *Runtime) AutoUpdateRecords() ([]*define.AutoUpdateRecord, error) {
+	if !r.valid {
+		return nil, define.ErrRuntimeStopped
+	}
+
+	return r.state.AllAutoUpdateRecords()
+}
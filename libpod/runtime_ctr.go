@@ -18,6 +18,7 @@ import (
 	cutil "github.com/containers/common/pkg/util"
 	"github.com/containers/podman/v4/libpod/define"
 	"github.com/containers/podman/v4/libpod/events"
+	"github.com/containers/podman/v4/libpod/logs"
 	"github.com/containers/podman/v4/libpod/shutdown"
 	"github.com/containers/podman/v4/pkg/domain/entities/reports"
 	"github.com/containers/podman/v4/pkg/rootless"
@@ -547,9 +548,13 @@ func (r *Runtime) setupContainer(ctx context.Context, ctr *Container) (_ *Contai
 	case define.NoLogging, define.PassthroughLogging, define.JournaldLogging:
 		break
 	default:
+		if err := logs.ValidateLogOptions(ctr.config.LogDriver, ctr.config.LogOptions); err != nil {
+			return nil, fmt.Errorf("%v: %w", err, define.ErrInvalidArg)
+		}
 		if ctr.config.LogPath == "" {
 			ctr.config.LogPath = filepath.Join(ctr.config.StaticDir, "ctr.log")
 		}
+		ctr.config.JSONFileLogFormat = ctr.config.LogDriver == define.JSONLogging
 	}
 
 	if useDevShm && !MountExists(ctr.config.Spec.Mounts, "/dev/shm") && ctr.config.ShmDir == "" && !ctr.config.NoShm {
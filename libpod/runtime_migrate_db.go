@@ -0,0 +1,581 @@
+package libpod
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/containers/common/libnetwork/types"
+	"github.com/containers/common/pkg/config"
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// migrateDBSuffix is appended to the path of the SQLite database to
+	// get the path of the database the BoltDB state is copied to, before
+	// it replaces the SQLite database.
+	migrateDBSuffix = ".migrate"
+	// rollbackDBSuffix is appended to the path of the SQLite database to
+	// get the path it is moved to when the migration is rolled back.
+	rollbackDBSuffix = ".rollback"
+)
+
+// DBMigrationReport describes the migration of the database backend, or the
+// rollback of such a migration, done by a runtime created with
+// WithMigrateDB() or WithRollbackDB().
+type DBMigrationReport struct {
+	// From is the database backend before the migration.
+	From config.DBBackend
+	// To is the database backend after the migration.
+	To config.DBBackend
+	// DryRun is set if the database backend was not changed.
+	DryRun bool
+	// DBPath is the path of the SQLite database.
+	DBPath string
+	// BackupPath is the path the SQLite database is moved to when rolling
+	// back.
+	BackupPath string
+
+	copied *dbMigrationCounts
+}
+
+// String returns a summary of the migration.
+func (r *DBMigrationReport) String() string {
+	if r.copied == nil {
+		if r.DryRun {
+			return fmt.Sprintf("Would roll back the database backend from %s to %s and move %s to %s", r.From, r.To, r.DBPath, r.BackupPath)
+		}
+		return fmt.Sprintf("Rolled back the database backend from %s to %s and moved %s to %s", r.From, r.To, r.DBPath, r.BackupPath)
+	}
+	if r.DryRun {
+		return fmt.Sprintf("Would migrate %s from %s to %s", r.copied, r.From, r.To)
+	}
+	return fmt.Sprintf("Migrated %s from %s to %s", r.copied, r.From, r.To)
+}
+
+// dbMigrationCounts counts the objects copied by a migration of the state.
+type dbMigrationCounts struct {
+	containers        int
+	pods              int
+	volumes           int
+	networks          int
+	execSessions      int
+	exitCodes         int
+	autoUpdateRecords int
+}
+
+func (r *dbMigrationCounts) String() string {
+	return fmt.Sprintf("%d containers, %d pods, %d volumes, %d network attachments, %d exec sessions, %d exit codes and %d auto-update records",
+		r.containers, r.pods, r.volumes, r.networks, r.execSessions, r.exitCodes, r.autoUpdateRecords)
+}
+
+// migrateDBToSqlite copies the BoltDB state into a new SQLite database and
+// switches the database backend in containers.conf to SQLite.  The state is
+// copied to a temporary database, which replaces the SQLite database only
+// after it was validated.  The BoltDB state is opened read-only, so that the
+// migration can be rolled back with rollbackDBToBoltDB.
+func (r *Runtime) migrateDBToSqlite() (retErr error) {
+	if err := r.checkDBBackend(config.DBBackendBoltDB); err != nil {
+		return err
+	}
+
+	src := newReadOnlyBoltState(boltStatePath(r), r)
+
+	dbPath := sqliteStatePath(r)
+	if err := checkSqliteStateEmpty(dbPath, r); err != nil {
+		return err
+	}
+
+	tmpPath := dbPath + migrateDBSuffix
+	// Remove the leftovers of an interrupted migration.
+	if err := removeSqliteFiles(tmpPath); err != nil {
+		return err
+	}
+	dst, err := newSqliteState(tmpPath, r)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if dst.valid {
+			if err := dst.Close(); err != nil {
+				logrus.Errorf("Closing database %s: %v", tmpPath, err)
+			}
+		}
+		if retErr != nil || r.migrateDryRun {
+			if err := removeSqliteFiles(tmpPath); err != nil {
+				logrus.Error(err)
+			}
+		}
+	}()
+
+	if err := dst.ValidateDBConfig(r); err != nil {
+		return err
+	}
+	copied, err := copyState(src, dst)
+	if err != nil {
+		return fmt.Errorf("copying the database to %s: %w", config.DBBackendSQLite, err)
+	}
+	if err := validateCopiedState(src, dst); err != nil {
+		return fmt.Errorf("validating the %s database: %w", config.DBBackendSQLite, err)
+	}
+
+	report := &DBMigrationReport{
+		From:   config.DBBackendBoltDB,
+		To:     config.DBBackendSQLite,
+		DryRun: r.migrateDryRun,
+		DBPath: dbPath,
+		copied: copied,
+	}
+	if r.migrateDryRun {
+		r.dbMigrationReport = report
+		return nil
+	}
+
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("closing database %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, dbPath); err != nil {
+		return fmt.Errorf("replacing database %s: %w", dbPath, err)
+	}
+	if err := writeDBBackend(config.DBBackendSQLite); err != nil {
+		// Roll back, the BoltDB state is still in use.
+		if err := removeSqliteFiles(dbPath); err != nil {
+			logrus.Error(err)
+		}
+		return fmt.Errorf("switching the database backend to %s: %w", config.DBBackendSQLite, err)
+	}
+
+	logrus.Debugf("Migrated %s from %s to %s", copied, config.DBBackendBoltDB, config.DBBackendSQLite)
+	r.dbMigrationReport = report
+	return nil
+}
+
+// rollbackDBToBoltDB switches the database backend in containers.conf back to
+// BoltDB after a migration to SQLite.  The BoltDB state was not modified since
+// the migration, so the rollback is refused if containers, pods or volumes
+// were created or removed since then.  The SQLite database is moved aside.
+func (r *Runtime) rollbackDBToBoltDB() error {
+	if err := r.checkDBBackend(config.DBBackendSQLite); err != nil {
+		return err
+	}
+
+	boltPath := boltStatePath(r)
+	if _, err := os.Stat(boltPath); err != nil {
+		return fmt.Errorf("cannot roll back to %s: %w", config.DBBackendBoltDB, err)
+	}
+	if err := compareStateObjects(newReadOnlyBoltState(boltPath, r), r.state); err != nil {
+		return fmt.Errorf("cannot roll back to %s, the state changed since the migration: %w", config.DBBackendBoltDB, err)
+	}
+
+	dbPath := sqliteStatePath(r)
+	backupPath := dbPath + rollbackDBSuffix
+	report := &DBMigrationReport{
+		From:       config.DBBackendSQLite,
+		To:         config.DBBackendBoltDB,
+		DryRun:     r.migrateDryRun,
+		DBPath:     dbPath,
+		BackupPath: backupPath,
+	}
+	if r.migrateDryRun {
+		r.dbMigrationReport = report
+		return nil
+	}
+
+	if err := os.Rename(dbPath, backupPath); err != nil {
+		return fmt.Errorf("moving database %s: %w", dbPath, err)
+	}
+	if err := writeDBBackend(config.DBBackendBoltDB); err != nil {
+		if err := os.Rename(backupPath, dbPath); err != nil {
+			logrus.Errorf("Moving database %s back: %v", backupPath, err)
+		}
+		return fmt.Errorf("switching the database backend to %s: %w", config.DBBackendBoltDB, err)
+	}
+
+	logrus.Debugf("Rolled back the database backend from %s to %s", config.DBBackendSQLite, config.DBBackendBoltDB)
+	r.dbMigrationReport = report
+	return nil
+}
+
+// DBMigrationReport returns the report of the migration of the database
+// backend done while creating the runtime, or nil if none was done.
+func (r *Runtime) DBMigrationReport() *DBMigrationReport {
+	return r.dbMigrationReport
+}
+
+// checkDBBackend returns an error if the runtime does not use the backend.
+func (r *Runtime) checkDBBackend(backend config.DBBackend) error {
+	current, err := config.ParseDBBackend(r.config.Engine.DBBackend)
+	if err != nil {
+		return err
+	}
+	if current != backend {
+		return fmt.Errorf("the database backend is %s instead of %s: %w", current, backend, define.ErrInvalidArg)
+	}
+	return nil
+}
+
+// checkSqliteStateEmpty returns an error if the SQLite database at the path
+// holds any container, pod or volume.  An empty database, e.g. created by a
+// single podman --db-backend=sqlite command, may be replaced.
+func checkSqliteStateEmpty(path string, rt *Runtime) error {
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	state, err := newSqliteState(path, rt)
+	if err != nil {
+		return err
+	}
+	defer state.Close()
+
+	objects, err := stateObjects(state)
+	if err != nil {
+		return err
+	}
+	if len(objects) > 0 {
+		return fmt.Errorf("database %s already holds containers, pods or volumes: %w", path, define.ErrInvalidArg)
+	}
+	return nil
+}
+
+// removeSqliteFiles removes the SQLite database at the path along with its
+// journal.
+func removeSqliteFiles(path string) error {
+	for _, p := range []string{path, path + "-journal"} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("removing database file %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// writeDBBackend sets the database backend in the containers.conf file which
+// podman writes to, e.g. for podman system connection, by replacing the file
+// atomically.  As a containers.conf file read after it may set the backend as
+// well, the file is restored and an error is returned if the backend of the
+// resulting configuration differs.
+func writeDBBackend(backend config.DBBackend) error {
+	cfg, err := config.ReadCustomConfig()
+	if err != nil {
+		return err
+	}
+	previous := cfg.Engine.DBBackend
+	cfg.Engine.DBBackend = backend.String()
+	if err := cfg.Write(); err != nil {
+		return err
+	}
+
+	err = checkConfiguredDBBackend(backend)
+	if err != nil {
+		cfg.Engine.DBBackend = previous
+		if err := cfg.Write(); err != nil {
+			logrus.Errorf("Restoring the database backend in containers.conf: %v", err)
+		}
+	}
+	return err
+}
+
+// checkConfiguredDBBackend returns an error if the database backend set by
+// the containers.conf files is not the specified one.
+func checkConfiguredDBBackend(backend config.DBBackend) error {
+	cfg, err := config.NewConfig("")
+	if err != nil {
+		return fmt.Errorf("reading containers.conf: %w", err)
+	}
+	current, err := cfg.DBBackend()
+	if err != nil {
+		return err
+	}
+	if current != backend {
+		return fmt.Errorf("the database backend is set to %s by another containers.conf file, e.g. in a containers.conf.d directory or in $CONTAINERS_CONF_OVERRIDE: %w", current, define.ErrInvalidArg)
+	}
+	return nil
+}
+
+// copyState copies all containers, pods, volumes, exec sessions, exit codes
+// and auto-update records of the BoltDB state into the empty SQLite state.
+func copyState(src *BoltState, dst *SQLiteState) (*dbMigrationCounts, error) {
+	report := new(dbMigrationCounts)
+
+	volumes, err := src.AllVolumes()
+	if err != nil {
+		return nil, err
+	}
+	for _, vol := range volumes {
+		if err := dst.AddVolume(vol); err != nil {
+			return nil, fmt.Errorf("adding volume %s: %w", vol.Name(), err)
+		}
+	}
+	report.volumes = len(volumes)
+
+	pods, err := src.AllPods()
+	if err != nil {
+		return nil, err
+	}
+	podsByID := make(map[string]*Pod, len(pods))
+	for _, pod := range pods {
+		if err := src.UpdatePod(pod); err != nil {
+			return nil, err
+		}
+		// The infra container does not exist yet, it is set once all
+		// containers were added.
+		infraID := pod.state.InfraContainerID
+		pod.state.InfraContainerID = ""
+		err := dst.AddPod(pod)
+		pod.state.InfraContainerID = infraID
+		if err != nil {
+			return nil, fmt.Errorf("adding pod %s: %w", pod.ID(), err)
+		}
+		podsByID[pod.ID()] = pod
+	}
+	report.pods = len(pods)
+
+	ctrs, err := src.AllContainers(true)
+	if err != nil {
+		return nil, err
+	}
+	ctrs, err = sortByDependencies(ctrs)
+	if err != nil {
+		return nil, err
+	}
+	for _, ctr := range ctrs {
+		networks, err := src.GetNetworks(ctr)
+		if err != nil {
+			return nil, err
+		}
+		ctr.config.Networks = withoutShortIDAlias(ctr, networks)
+		report.networks += len(networks)
+
+		if ctr.config.Pod == "" {
+			err = dst.AddContainer(ctr)
+		} else {
+			pod, ok := podsByID[ctr.config.Pod]
+			if !ok {
+				return nil, fmt.Errorf("container %s is part of pod %s which does not exist: %w", ctr.ID(), ctr.config.Pod, define.ErrNoSuchPod)
+			}
+			err = dst.AddContainerToPod(pod, ctr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("adding container %s: %w", ctr.ID(), err)
+		}
+
+		sessions, err := src.GetContainerExecSessions(ctr)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range sessions {
+			if err := dst.AddExecSession(ctr, &ExecSession{Id: id}); err != nil {
+				return nil, err
+			}
+		}
+		report.execSessions += len(sessions)
+	}
+	report.containers = len(ctrs)
+
+	for _, pod := range pods {
+		if pod.state.InfraContainerID != "" {
+			if err := dst.SavePod(pod); err != nil {
+				return nil, fmt.Errorf("saving pod %s: %w", pod.ID(), err)
+			}
+		}
+	}
+
+	exitCodes, err := src.allContainerExitCodes()
+	if err != nil {
+		return nil, err
+	}
+	for id, exitCode := range exitCodes {
+		if err := dst.addContainerExitCode(id, exitCode.exitCode, exitCode.timeStamp); err != nil {
+			return nil, err
+		}
+	}
+	report.exitCodes = len(exitCodes)
+
+	records, err := src.AllAutoUpdateRecords()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if err := dst.AddAutoUpdateRecord(record); err != nil {
+			return nil, err
+		}
+	}
+	report.autoUpdateRecords = len(records)
+
+	return report, nil
+}
+
+// sortByDependencies orders the containers such that all dependencies of a
+// container precede it.
+func sortByDependencies(ctrs []*Container) ([]*Container, error) {
+	sorted := make([]*Container, 0, len(ctrs))
+	added := make(map[string]bool, len(ctrs))
+	for len(sorted) < len(ctrs) {
+		progress := false
+		for _, ctr := range ctrs {
+			if added[ctr.ID()] {
+				continue
+			}
+			ready := true
+			for _, dep := range ctr.Dependencies() {
+				ready = ready && added[dep]
+			}
+			if ready {
+				sorted = append(sorted, ctr)
+				added[ctr.ID()] = true
+				progress = true
+			}
+		}
+		if !progress {
+			return nil, fmt.Errorf("containers have missing or circular dependencies: %w", define.ErrInternal)
+		}
+	}
+	return sorted, nil
+}
+
+// withoutShortIDAlias returns a copy of the networks of the container without
+// the alias of its short ID, which the states add to every network.
+func withoutShortIDAlias(ctr *Container, networks map[string]types.PerNetworkOptions) map[string]types.PerNetworkOptions {
+	if len(networks) == 0 {
+		return nil
+	}
+	result := make(map[string]types.PerNetworkOptions, len(networks))
+	for name, opts := range networks {
+		if n := len(opts.Aliases); n > 0 && opts.Aliases[n-1] == ctr.ID()[:12] {
+			opts.Aliases = opts.Aliases[: n-1 : n-1]
+		}
+		result[name] = opts
+	}
+	return result
+}
+
+// validateCopiedState returns an error if the SQLite state differs from the
+// BoltDB state it was copied from.
+func validateCopiedState(src *BoltState, dst *SQLiteState) error {
+	if err := compareStateObjects(src, dst); err != nil {
+		return err
+	}
+
+	ctrs, err := src.AllContainers(false)
+	if err != nil {
+		return err
+	}
+	for _, ctr := range ctrs {
+		srcNetworks, err := src.GetNetworks(ctr)
+		if err != nil {
+			return err
+		}
+		dstNetworks, err := dst.GetNetworks(ctr)
+		if err != nil {
+			return err
+		}
+		if (len(srcNetworks) > 0 || len(dstNetworks) > 0) && !reflect.DeepEqual(srcNetworks, dstNetworks) {
+			return fmt.Errorf("networks of container %s differ: %w", ctr.ID(), define.ErrInternal)
+		}
+
+		srcSessions, err := src.GetContainerExecSessions(ctr)
+		if err != nil {
+			return err
+		}
+		dstSessions, err := dst.GetContainerExecSessions(ctr)
+		if err != nil {
+			return err
+		}
+		sort.Strings(srcSessions)
+		sort.Strings(dstSessions)
+		if (len(srcSessions) > 0 || len(dstSessions) > 0) && !reflect.DeepEqual(srcSessions, dstSessions) {
+			return fmt.Errorf("exec sessions of container %s differ: %w", ctr.ID(), define.ErrInternal)
+		}
+	}
+
+	exitCodes, err := src.allContainerExitCodes()
+	if err != nil {
+		return err
+	}
+	for id, exitCode := range exitCodes {
+		dstExitCode, err := dst.GetContainerExitCode(id)
+		if err != nil {
+			return err
+		}
+		if dstExitCode != exitCode.exitCode {
+			return fmt.Errorf("exit code of container %s differs: %w", id, define.ErrInternal)
+		}
+	}
+
+	srcRecords, err := src.AllAutoUpdateRecords()
+	if err != nil {
+		return err
+	}
+	dstRecords, err := dst.AllAutoUpdateRecords()
+	if err != nil {
+		return err
+	}
+	if len(srcRecords) != len(dstRecords) {
+		return fmt.Errorf("number of auto-update records differs: %w", define.ErrInternal)
+	}
+	for i, record := range srcRecords {
+		if !reflect.DeepEqual(record, dstRecords[i]) {
+			return fmt.Errorf("auto-update record %d of container %s differs: %w", i, record.ContainerID, define.ErrInternal)
+		}
+	}
+
+	return nil
+}
+
+// stateObjects returns the set of the containers, pods and volumes of the
+// state.
+func stateObjects(state State) (map[string]bool, error) {
+	objects := make(map[string]bool)
+	ctrs, err := state.AllContainers(false)
+	if err != nil {
+		return nil, err
+	}
+	for _, ctr := range ctrs {
+		objects["container "+ctr.ID()] = true
+	}
+	pods, err := state.AllPods()
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods {
+		objects["pod "+pod.ID()] = true
+	}
+	volumes, err := state.AllVolumes()
+	if err != nil {
+		return nil, err
+	}
+	for _, vol := range volumes {
+		objects["volume "+vol.Name()] = true
+	}
+	return objects, nil
+}
+
+// compareStateObjects returns an error if the BoltDB and the SQLite state do
+// not hold the same containers, pods and volumes.
+func compareStateObjects(boltState, sqliteState State) error {
+	boltObjects, err := stateObjects(boltState)
+	if err != nil {
+		return err
+	}
+	sqliteObjects, err := stateObjects(sqliteState)
+	if err != nil {
+		return err
+	}
+	for object := range boltObjects {
+		if !sqliteObjects[object] {
+			return fmt.Errorf("%s only exists in the %s database", object, config.DBBackendBoltDB)
+		}
+	}
+	for object := range sqliteObjects {
+		if !boltObjects[object] {
+			return fmt.Errorf("%s only exists in the %s database", object, config.DBBackendSQLite)
+		}
+	}
+	return nil
+}
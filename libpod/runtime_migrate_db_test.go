@@ -0,0 +1,160 @@
+package libpod
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containers/common/libnetwork/types"
+	"github.com/containers/common/pkg/config"
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/containers/podman/v4/libpod/lock"
+	"github.com/containers/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyState(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager, err := lock.NewInMemoryManager(16)
+	require.NoError(t, err)
+	runtime := &Runtime{
+		config:        new(config.Config),
+		storageConfig: storage.StoreOptions{},
+		lockManager:   manager,
+	}
+	boltState, err := NewBoltState(filepath.Join(tmpDir, "bolt_state.db"), runtime)
+	require.NoError(t, err)
+	defer boltState.Close()
+
+	// A volume, a pod with its infra container and a container using the
+	// network namespace of the infra container, and a container connected
+	// to a network.
+	vol := newVolume(runtime)
+	vol.config.Name = "vol"
+	volLock, err := manager.AllocateLock()
+	require.NoError(t, err)
+	vol.lock = volLock
+	vol.config.LockID = volLock.ID()
+	vol.valid = true
+	require.NoError(t, boltState.AddVolume(vol))
+
+	pod, err := getTestPod1(manager)
+	require.NoError(t, err)
+	require.NoError(t, boltState.AddPod(pod))
+	infra, err := getTestCtrN("2", manager)
+	require.NoError(t, err)
+	infra.config.Pod = pod.ID()
+	require.NoError(t, boltState.AddContainerToPod(pod, infra))
+	pod.state.InfraContainerID = infra.ID()
+	require.NoError(t, boltState.SavePod(pod))
+	podCtr, err := getTestCtrN("3", manager)
+	require.NoError(t, err)
+	podCtr.config.Pod = pod.ID()
+	podCtr.config.NetNsCtr = infra.ID()
+	require.NoError(t, boltState.AddContainerToPod(pod, podCtr))
+
+	ctr, err := getTestCtrN("4", manager)
+	require.NoError(t, err)
+	ctr.config.NetMode = "bridge"
+	ctr.config.Networks = map[string]types.PerNetworkOptions{
+		"podman": {InterfaceName: "eth0", Aliases: []string{"web"}},
+	}
+	ctr.config.NamedVolumes = []*ContainerNamedVolume{{Name: vol.Name(), Dest: "/data"}}
+	require.NoError(t, boltState.AddContainer(ctr))
+	require.NoError(t, boltState.AddExecSession(ctr, &ExecSession{Id: "exec"}))
+	require.NoError(t, boltState.AddContainerExitCode(podCtr.ID(), 42))
+	record := &define.AutoUpdateRecord{ContainerID: ctr.ID(), Time: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)}
+	require.NoError(t, boltState.AddAutoUpdateRecord(record))
+
+	src := newReadOnlyBoltState(filepath.Join(tmpDir, "bolt_state.db"), runtime)
+	dst, err := newSqliteState(filepath.Join(tmpDir, "db.sql"), runtime)
+	require.NoError(t, err)
+	defer dst.Close()
+	require.NoError(t, dst.ValidateDBConfig(runtime))
+
+	report, err := copyState(src, dst)
+	require.NoError(t, err)
+	assert.Equal(t, &dbMigrationCounts{containers: 3, pods: 1, volumes: 1, networks: 1, execSessions: 1, exitCodes: 1, autoUpdateRecords: 1}, report)
+	require.NoError(t, validateCopiedState(src, dst))
+
+	copiedCtr, err := dst.Container(ctr.ID())
+	require.NoError(t, err)
+	networks, err := dst.GetNetworks(copiedCtr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", ctr.ID()[:12]}, networks["podman"].Aliases)
+	inUse, err := dst.VolumeInUse(vol)
+	require.NoError(t, err)
+	assert.Equal(t, []string{ctr.ID()}, inUse)
+
+	copiedPod, err := dst.Pod(pod.ID())
+	require.NoError(t, err)
+	require.NoError(t, dst.UpdatePod(copiedPod))
+	assert.Equal(t, infra.ID(), copiedPod.state.InfraContainerID)
+	podCtrs, err := dst.PodContainersByID(copiedPod)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{infra.ID(), podCtr.ID()}, podCtrs)
+
+	exitCode, err := dst.GetContainerExitCode(podCtr.ID())
+	require.NoError(t, err)
+	assert.Equal(t, int32(42), exitCode)
+	records, err := dst.AllAutoUpdateRecords()
+	require.NoError(t, err)
+	assert.Equal(t, []*define.AutoUpdateRecord{record}, records)
+
+	// Changing an auto-update record is detected.
+	_, err = dst.conn.Exec("UPDATE AutoUpdateRecord SET JSON = json_set(JSON, '$.result', 'rolled back');")
+	require.NoError(t, err)
+	assert.ErrorContains(t, validateCopiedState(src, dst), "auto-update record 0 of container "+ctr.ID()+" differs")
+
+	// The read-only state cannot be modified.
+	assert.Error(t, src.AddContainerExitCode(ctr.ID(), 1))
+
+	// Removing a container from either state is detected.
+	require.NoError(t, dst.RemoveContainerExecSessions(copiedCtr))
+	require.NoError(t, dst.RemoveContainer(copiedCtr))
+	assert.EqualError(t, compareStateObjects(src, dst), "container "+ctr.ID()+" only exists in the boltdb database")
+}
+
+func TestSortByDependencies(t *testing.T) {
+	manager, err := lock.NewInMemoryManager(16)
+	require.NoError(t, err)
+	var ctrs []*Container
+	for _, n := range []string{"1", "2", "3"} {
+		ctr, err := getTestCtrN(n, manager)
+		require.NoError(t, err)
+		ctrs = append(ctrs, ctr)
+	}
+	ctrs[0].config.IPCNsCtr = ctrs[1].ID()
+	ctrs[1].config.NetNsCtr = ctrs[2].ID()
+
+	sorted, err := sortByDependencies(ctrs)
+	require.NoError(t, err)
+	assert.Equal(t, []*Container{ctrs[2], ctrs[1], ctrs[0]}, sorted)
+
+	ctrs[2].config.UTSNsCtr = ctrs[0].ID()
+	_, err = sortByDependencies(ctrs)
+	assert.Error(t, err)
+}
+
+func TestWriteDBBackend(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CONTAINERS_CONF", filepath.Join(dir, "containers.conf"))
+	t.Setenv("CONTAINERS_CONF_OVERRIDE", "")
+
+	require.NoError(t, writeDBBackend(config.DBBackendSQLite))
+	cfg, err := config.ReadCustomConfig()
+	require.NoError(t, err)
+	assert.Equal(t, config.DBBackendSQLite.String(), cfg.Engine.DBBackend)
+
+	// A file read after the written one sets the backend as well.
+	overridePath := filepath.Join(dir, "override.conf")
+	require.NoError(t, os.WriteFile(overridePath, []byte("[engine]\ndatabase_backend = \"sqlite\"\n"), 0o644))
+	t.Setenv("CONTAINERS_CONF_OVERRIDE", overridePath)
+	err = writeDBBackend(config.DBBackendBoltDB)
+	assert.ErrorIs(t, err, define.ErrInvalidArg)
+	cfg, err = config.ReadCustomConfig()
+	require.NoError(t, err)
+	assert.Equal(t, config.DBBackendSQLite.String(), cfg.Engine.DBBackend)
+}
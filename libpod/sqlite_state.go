@@ -20,7 +20,7 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-const schemaVersion = 1
+const schemaVersion = 2
 
 // SQLiteState is a state implementation backed by a SQLite database
 type SQLiteState struct {
@@ -39,8 +39,11 @@ const (
 	// Make sure that transactions happen exclusively.
 	sqliteOptionTXLock = "&_txlock=exclusive"
 
+	// Name of the database file.
+	sqliteDBName = "db.sql"
+
 	// Assembled sqlite options used when opening the database.
-	sqliteOptions = "db.sql?" +
+	sqliteOptions = "?" +
 		sqliteOptionLocation +
 		sqliteOptionSynchronous +
 		sqliteOptionForeignKeys +
@@ -48,22 +51,38 @@ const (
 )
 
 // NewSqliteState creates a new SQLite-backed state database.
-func NewSqliteState(runtime *Runtime) (_ State, defErr error) {
-	state := new(SQLiteState)
-
-	basePath := runtime.storageConfig.GraphRoot
-	if runtime.storageConfig.TransientStore {
-		basePath = runtime.storageConfig.RunRoot
-	}
+func NewSqliteState(runtime *Runtime) (State, error) {
+	dbPath := sqliteStatePath(runtime)
 
 	// c/storage is set up *after* the DB - so even though we use the c/s
 	// root (or, for transient, runroot) dir, we need to make the dir
 	// ourselves.
-	if err := os.MkdirAll(basePath, 0700); err != nil {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0700); err != nil {
 		return nil, fmt.Errorf("creating root directory: %w", err)
 	}
 
-	conn, err := sql.Open("sqlite3", filepath.Join(basePath, sqliteOptions))
+	state, err := newSqliteState(dbPath, runtime)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// sqliteStatePath returns the path of the SQLite database of the runtime.
+func sqliteStatePath(runtime *Runtime) string {
+	basePath := runtime.storageConfig.GraphRoot
+	if runtime.storageConfig.TransientStore {
+		basePath = runtime.storageConfig.RunRoot
+	}
+	return filepath.Join(basePath, sqliteDBName)
+}
+
+// newSqliteState opens the SQLite database at the given path, creating it if
+// it does not exist.
+func newSqliteState(path string, runtime *Runtime) (_ *SQLiteState, defErr error) {
+	state := new(SQLiteState)
+
+	conn, err := sql.Open("sqlite3", path+sqliteOptions)
 	if err != nil {
 		return nil, fmt.Errorf("initializing sqlite database: %w", err)
 	}
@@ -862,7 +881,7 @@ func (s *SQLiteState) GetContainerConfig(id string) (*ContainerConfig, error) {
 }
 
 // AddContainerExitCode adds the exit code for the specified container to the database.
-func (s *SQLiteState) AddContainerExitCode(id string, exitCode int32) (defErr error) {
+func (s *SQLiteState) AddContainerExitCode(id string, exitCode int32) error {
 	if len(id) == 0 {
 		return define.ErrEmptyID
 	}
@@ -871,6 +890,12 @@ func (s *SQLiteState) AddContainerExitCode(id string, exitCode int32) (defErr er
 		return define.ErrDBClosed
 	}
 
+	return s.addContainerExitCode(id, exitCode, time.Now())
+}
+
+// addContainerExitCode adds the exit code for the specified container with the
+// given time stamp to the database.
+func (s *SQLiteState) addContainerExitCode(id string, exitCode int32, timeStamp time.Time) (defErr error) {
 	tx, err := s.conn.Begin()
 	if err != nil {
 		return fmt.Errorf("beginning transaction to add exit code: %w", err)
@@ -883,7 +908,7 @@ func (s *SQLiteState) AddContainerExitCode(id string, exitCode int32) (defErr er
 		}
 	}()
 
-	if _, err := tx.Exec("INSERT OR REPLACE INTO ContainerExitCode VALUES (?, ?, ?);", id, time.Now().Unix(), exitCode); err != nil {
+	if _, err := tx.Exec("INSERT OR REPLACE INTO ContainerExitCode VALUES (?, ?, ?);", id, timeStamp.Unix(), exitCode); err != nil {
 		return fmt.Errorf("adding container %s exit code %d: %w", id, exitCode, err)
 	}
 
@@ -975,6 +1000,71 @@ func (s *SQLiteState) PruneContainerExitCodes() (defErr error) {
 	return nil
 }
 
+// AddAutoUpdateRecord adds a record of an update performed by auto-update to
+// the database.
+func (s *SQLiteState) AddAutoUpdateRecord(record *define.AutoUpdateRecord) (defErr error) {
+	if !s.valid {
+		return define.ErrDBClosed
+	}
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshalling auto-update record of container %s: %w", record.ContainerID, err)
+	}
+
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction to add auto-update record: %w", err)
+	}
+	defer func() {
+		if defErr != nil {
+			if err := tx.Rollback(); err != nil {
+				logrus.Errorf("Rolling back transaction to add auto-update record: %v", err)
+			}
+		}
+	}()
+
+	if _, err := tx.Exec("INSERT INTO AutoUpdateRecord (Timestamp, JSON) VALUES (?, ?);", record.Time.Unix(), string(recordJSON)); err != nil {
+		return fmt.Errorf("adding auto-update record of container %s: %w", record.ContainerID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction to add auto-update record: %w", err)
+	}
+
+	return nil
+}
+
+// AllAutoUpdateRecords returns all auto-update records in the order they were
+// added to the database.
+func (s *SQLiteState) AllAutoUpdateRecords() ([]*define.AutoUpdateRecord, error) {
+	if !s.valid {
+		return nil, define.ErrDBClosed
+	}
+
+	rows, err := s.conn.Query("SELECT JSON FROM AutoUpdateRecord ORDER BY ID;")
+	if err != nil {
+		return nil, fmt.Errorf("querying database for auto-update records: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*define.AutoUpdateRecord{}
+
+	for rows.Next() {
+		var recordJSON string
+		if err := rows.Scan(&recordJSON); err != nil {
+			return nil, fmt.Errorf("scanning auto-update record from database: %w", err)
+		}
+		record := new(define.AutoUpdateRecord)
+		if err := json.Unmarshal([]byte(recordJSON), record); err != nil {
+			return nil, fmt.Errorf("unmarshalling auto-update record: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
 // AddExecSession adds an exec session to the state.
 func (s *SQLiteState) AddExecSession(ctr *Container, session *ExecSession) (defErr error) {
 	if !s.valid {
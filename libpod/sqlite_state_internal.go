@@ -88,6 +88,11 @@ func migrateSchemaIfNecessary(tx *sql.Tx) (bool, error) {
 	}
 
 	// Perform schema migration here, one version at a time.
+	// Version 2 adds the AutoUpdateRecord table which is created along
+	// with all other missing tables by the caller.
+	if _, err := tx.Exec("UPDATE DBConfig SET SchemaVersion=?;", schemaVersion); err != nil {
+		return false, fmt.Errorf("updating database schema version to %d: %w", schemaVersion, err)
+	}
 
 	return false, nil
 }
@@ -204,6 +209,13 @@ func createSQLiteTables(tx *sql.Tx) error {
                 FOREIGN KEY (Name) REFERENCES VolumeConfig(Name) DEFERRABLE INITIALLY DEFERRED
         );`
 
+	const autoUpdateRecord = `
+        CREATE TABLE IF NOT EXISTS AutoUpdateRecord(
+                ID        INTEGER PRIMARY KEY AUTOINCREMENT,
+                Timestamp INTEGER NOT NULL,
+                JSON      TEXT    NOT NULL
+        );`
+
 	tables := map[string]string{
 		"DBConfig":             dbConfig,
 		"IDNamespace":          idNamespace,
@@ -217,6 +229,7 @@ func createSQLiteTables(tx *sql.Tx) error {
 		"PodState":             podState,
 		"VolumeConfig":         volumeConfig,
 		"VolumeState":          volumeState,
+		"AutoUpdateRecord":     autoUpdateRecord,
 	}
 
 	for tblName, cmd := range tables {
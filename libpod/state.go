@@ -1,6 +1,9 @@
 package libpod
 
-import "github.com/containers/common/libnetwork/types"
+import (
+	"github.com/containers/common/libnetwork/types"
+	"github.com/containers/podman/v4/libpod/define"
+)
 
 // State is a storage backend for libpod's current state.
 // A State is only initialized once per instance of libpod.
@@ -114,6 +117,11 @@ type State interface { //nolint:interfacebloat
 	// Remove exit codes older than 5 minutes.
 	PruneContainerExitCodes() error
 
+	// Add a record of an update performed by auto-update to the database.
+	AddAutoUpdateRecord(record *define.AutoUpdateRecord) error
+	// Return all auto-update records in the order they were added.
+	AllAutoUpdateRecords() ([]*define.AutoUpdateRecord, error)
+
 	// Add creates a reference to an exec session in the database.
 	// The container the exec session is attached to will be recorded.
 	// The container state will not be modified.
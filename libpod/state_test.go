@@ -2625,3 +2625,41 @@ func TestConvertPortMapping(t *testing.T) {
 		testContainersEqual(t, retrievedCtr, testCtr, true)
 	})
 }
+
+func TestAddAndGetAutoUpdateRecords(t *testing.T) {
+	runForAllStates(t, func(t *testing.T, state State, manager lock.Manager) {
+		records, err := state.AllAutoUpdateRecords()
+		assert.NoError(t, err)
+		assert.Empty(t, records)
+
+		now := time.Now().UTC().Truncate(time.Second)
+		first := &define.AutoUpdateRecord{
+			ContainerID:    strings.Repeat("1", 64),
+			ContainerName:  "web",
+			SystemdUnit:    "container-web.service",
+			ImageName:      "quay.io/example/web:latest",
+			OldImageID:     strings.Repeat("a", 64),
+			NewImageID:     strings.Repeat("b", 64),
+			Time:           now,
+			Result:         "true",
+			ProtectedUntil: now.Add(time.Hour),
+		}
+		second := &define.AutoUpdateRecord{
+			ContainerID:   strings.Repeat("2", 64),
+			ContainerName: "web",
+			ImageName:     "quay.io/example/web:latest",
+			OldImageID:    strings.Repeat("b", 64),
+			NewImageID:    strings.Repeat("a", 64),
+			Time:          now.Add(time.Minute),
+			Result:        "rolled back",
+		}
+		require.NoError(t, state.AddAutoUpdateRecord(first))
+		require.NoError(t, state.AddAutoUpdateRecord(second))
+
+		records, err = state.AllAutoUpdateRecords()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, first, records[0])
+		assert.Equal(t, second, records[1])
+	})
+}
@@ -443,6 +443,8 @@ func cliOpts(cc handlers.CreateContainerConfig, rtc *config.Config) (*entities.C
 		HealthRetries:     define.DefaultHealthCheckRetries,
 		HealthTimeout:     define.DefaultHealthCheckTimeout,
 		HealthStartPeriod: define.DefaultHealthCheckStartPeriod,
+		HealthMaxLogCount: define.DefaultHealthMaxLogCount,
+		HealthMaxLogSize:  define.DefaultHealthMaxLogSize,
 	}
 	if !rootless.IsRootless() {
 		var ulimits []string
@@ -2,9 +2,11 @@ package compat
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,6 +33,8 @@ func LogsFromContainer(w http.ResponseWriter, r *http.Request) {
 		Until      string `schema:"until"`
 		Timestamps bool   `schema:"timestamps"`
 		Tail       string `schema:"tail"`
+		Grep       string `schema:"grep"`
+		Format     string `schema:"format"`
 	}{
 		Tail: "all",
 	}
@@ -81,6 +85,29 @@ func LogsFromContainer(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var grep *regexp.Regexp
+	if query.Grep != "" {
+		grep, err = regexp.Compile(query.Grep)
+		if err != nil {
+			utils.BadRequest(w, "grep", query.Grep, err)
+			return
+		}
+	}
+
+	if query.Format != "" && query.Format != "json" {
+		utils.BadRequest(w, "format", query.Format, errors.New("only json is supported"))
+		return
+	}
+
+	// Filter the streams when reading the log.
+	var stream string
+	switch {
+	case !query.Stderr:
+		stream = logs.StdoutStream
+	case !query.Stdout:
+		stream = logs.StderrStream
+	}
+
 	options := &logs.LogOptions{
 		Details:    true,
 		Follow:     query.Follow,
@@ -88,6 +115,8 @@ func LogsFromContainer(w http.ResponseWriter, r *http.Request) {
 		Until:      until,
 		Tail:       tail,
 		Timestamps: query.Timestamps,
+		Stream:     stream,
+		Grep:       grep,
 	}
 
 	var wg sync.WaitGroup
@@ -146,14 +175,27 @@ func LogsFromContainer(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		if query.Timestamps {
-			frame.WriteString(line.Time.Format(time.RFC3339))
-			frame.WriteString(" ")
-		}
-
-		frame.WriteString(line.Msg)
-		if !line.Partial() {
+		switch {
+		case query.Format == "json":
+			// Structured lines are always sent on stdout.
+			header[0] = 1
+			jsonLine, err := line.ToJSONString()
+			if err != nil {
+				log.Errorf("unable to encode log line: %q", err)
+				continue
+			}
+			frame.WriteString(jsonLine)
 			frame.WriteString("\n")
+		default:
+			if query.Timestamps {
+				frame.WriteString(line.Time.Format(time.RFC3339))
+				frame.WriteString(" ")
+			}
+
+			frame.WriteString(line.Msg)
+			if !line.Partial() {
+				frame.WriteString("\n")
+			}
 		}
 
 		if writeHeader {
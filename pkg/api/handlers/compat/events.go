@@ -2,7 +2,9 @@ package compat
 
 import (
 	"fmt"
+	"mime"
 	"net/http"
+	"strings"
 
 	"github.com/containers/podman/v4/libpod"
 	"github.com/containers/podman/v4/libpod/events"
@@ -22,6 +24,7 @@ func GetEvents(w http.ResponseWriter, r *http.Request) {
 		decoder   = r.Context().Value(api.DecoderKey).(*schema.Decoder)
 		runtime   = r.Context().Value(api.RuntimeKey).(*libpod.Runtime)
 		json      = jsoniter.ConfigCompatibleWithStandardLibrary // FIXME: this should happen on the package level
+		source    = entities.CloudEventsSource()
 	)
 
 	// NOTE: the "filters" parameter is extracted separately for backwards
@@ -30,6 +33,7 @@ func GetEvents(w http.ResponseWriter, r *http.Request) {
 		Since  string `schema:"since"`
 		Until  string `schema:"until"`
 		Stream bool   `schema:"stream"`
+		Count  uint   `schema:"count"`
 	}{
 		Stream: true,
 	}
@@ -38,7 +42,7 @@ func GetEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(query.Since) > 0 || len(query.Until) > 0 {
+	if len(query.Since) > 0 || len(query.Until) > 0 || query.Count > 0 {
 		fromStart = true
 	}
 
@@ -59,6 +63,7 @@ func GetEvents(w http.ResponseWriter, r *http.Request) {
 			EventChannel: eventChannel,
 			Since:        query.Since,
 			Until:        query.Until,
+			Count:        query.Count,
 		}
 		errorChannel <- runtime.Events(r.Context(), readOpts)
 	}()
@@ -68,7 +73,12 @@ func GetEvents(w http.ResponseWriter, r *http.Request) {
 		flush = flusher.Flush
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	mediaType := eventsMediaType(r)
+	if mediaType == "" {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", mediaType)
+	}
 	w.WriteHeader(http.StatusOK)
 	flush()
 
@@ -88,17 +98,19 @@ func GetEvents(w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 
-			e := entities.ConvertToEntitiesEvent(*evt)
-			// Some events differ between Libpod and Docker endpoints.
-			// Handle these differences for Docker-compat.
-			if !utils.IsLibpodRequest(r) && e.Type == "image" && e.Status == "remove" {
-				e.Status = "delete"
-				e.Action = "delete"
-			}
-			if !utils.IsLibpodRequest(r) && e.Status == "died" {
-				e.Status = "die"
-				e.Action = "die"
-				e.Actor.Attributes["exitCode"] = e.Actor.Attributes["containerExitCode"]
+			var e interface{}
+			switch {
+			case mediaType == entities.MediaTypeCloudEvents:
+				e, err = entities.ConvertToCloudEvent(*evt, source)
+				if err != nil {
+					logrus.Errorf("Unable to convert event: %q", err)
+					continue
+				}
+			case mediaType == entities.MediaTypeDockerEvents || !utils.IsLibpodRequest(r):
+				// Some events differ between Libpod and Docker endpoints.
+				e = entities.ConvertToDockerEvent(*evt)
+			default:
+				e = entities.ConvertToEntitiesEvent(*evt)
 			}
 
 			if err := coder.Encode(e); err != nil {
@@ -110,3 +122,20 @@ func GetEvents(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// eventsMediaType returns the media type of the events requested in the Accept
+// header, or an empty string if the default format of the endpoint was
+// requested.
+func eventsMediaType(r *http.Request) string {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case entities.MediaTypeCloudEvents, entities.MediaTypeDockerEvents:
+			return mediaType
+		}
+	}
+	return ""
+}
@@ -1,18 +1,40 @@
 package libpod
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/containers/podman/v4/libpod"
 	"github.com/containers/podman/v4/libpod/define"
 	"github.com/containers/podman/v4/pkg/api/handlers/utils"
 	api "github.com/containers/podman/v4/pkg/api/types"
+	"github.com/containers/podman/v4/pkg/util"
+	"github.com/gorilla/schema"
 )
 
 func RunHealthCheck(w http.ResponseWriter, r *http.Request) {
 	runtime := r.Context().Value(api.RuntimeKey).(*libpod.Runtime)
+	decoder := r.Context().Value(api.DecoderKey).(*schema.Decoder)
+	query := struct {
+		Readiness bool `schema:"readiness"`
+	}{}
+	if err := decoder.Decode(&query, r.URL.Query()); err != nil {
+		utils.Error(w, http.StatusBadRequest, fmt.Errorf("failed to parse parameters for %s: %w", r.URL.String(), err))
+		return
+	}
 	name := utils.GetName(r)
-	status, err := runtime.HealthCheck(r.Context(), name)
+
+	var (
+		status define.HealthCheckStatus
+		err    error
+	)
+	if query.Readiness {
+		status, err = runtime.ReadinessCheck(r.Context(), name)
+	} else {
+		status, err = runtime.HealthCheck(r.Context(), name)
+	}
 	if err != nil {
 		if status == define.HealthCheckContainerNotFound {
 			utils.ContainerNotFound(w, name, err)
@@ -29,6 +51,16 @@ func RunHealthCheck(w http.ResponseWriter, r *http.Request) {
 		utils.InternalServerError(w, err)
 		return
 	}
+	if query.Readiness {
+		report := define.HealthCheckResults{
+			Status: define.ReadinessCheckNotReady,
+		}
+		if status == define.HealthCheckSuccess {
+			report.Status = define.ReadinessCheckReady
+		}
+		utils.WriteResponse(w, http.StatusOK, report)
+		return
+	}
 	hcStatus := define.HealthCheckUnhealthy
 	if status == define.HealthCheckSuccess {
 		hcStatus = define.HealthCheckHealthy
@@ -40,3 +72,41 @@ func RunHealthCheck(w http.ResponseWriter, r *http.Request) {
 	}
 	utils.WriteResponse(w, http.StatusOK, report)
 }
+
+func HealthCheckLog(w http.ResponseWriter, r *http.Request) {
+	runtime := r.Context().Value(api.RuntimeKey).(*libpod.Runtime)
+	decoder := r.Context().Value(api.DecoderKey).(*schema.Decoder)
+	query := struct {
+		Since string `schema:"since"`
+	}{}
+	if err := decoder.Decode(&query, r.URL.Query()); err != nil {
+		utils.Error(w, http.StatusBadRequest, fmt.Errorf("failed to parse parameters for %s: %w", r.URL.String(), err))
+		return
+	}
+	var since time.Time
+	if query.Since != "" {
+		var err error
+		since, err = util.ParseInputTime(query.Since, true)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	name := utils.GetName(r)
+	ctr, err := runtime.LookupContainer(name)
+	if err != nil {
+		utils.ContainerNotFound(w, name, err)
+		return
+	}
+	log, err := ctr.HealthCheckLog(r.Context(), since)
+	if err != nil {
+		if errors.Is(err, define.ErrInvalidArg) {
+			utils.Error(w, http.StatusConflict, err)
+			return
+		}
+		utils.InternalServerError(w, err)
+		return
+	}
+	utils.WriteResponse(w, http.StatusOK, log)
+}
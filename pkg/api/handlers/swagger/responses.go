@@ -180,6 +180,13 @@ type healthCheck struct {
 	Body define.HealthCheckResults
 }
 
+// Healthcheck Log
+// swagger:response
+type healthCheckLog struct {
+	// in:body
+	Body []define.HealthCheckLog
+}
+
 // Version
 // swagger:response
 type versionResponse struct {
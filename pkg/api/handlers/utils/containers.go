@@ -30,6 +30,7 @@ type waitQueryDocker struct {
 type waitQueryLibpod struct {
 	Interval  string                   `schema:"interval"`
 	Condition []define.ContainerStatus `schema:"condition"`
+	Ready     bool                     `schema:"ready"`
 }
 
 func WaitContainerDocker(w http.ResponseWriter, r *http.Request) {
@@ -120,7 +121,7 @@ func WaitContainerLibpod(w http.ResponseWriter, r *http.Request) {
 
 	name := GetName(r)
 
-	waitFn := createContainerWaitFn(r.Context(), name, interval)
+	waitFn := createContainerWaitFn(r.Context(), name, interval, query.Ready)
 	exitCode, err := waitFn(query.Condition...)
 	if err != nil {
 		if errors.Is(err, define.ErrNoSuchCtr) {
@@ -135,7 +136,7 @@ func WaitContainerLibpod(w http.ResponseWriter, r *http.Request) {
 
 type containerWaitFn func(conditions ...define.ContainerStatus) (int32, error)
 
-func createContainerWaitFn(ctx context.Context, containerName string, interval time.Duration) containerWaitFn {
+func createContainerWaitFn(ctx context.Context, containerName string, interval time.Duration, ready bool) containerWaitFn {
 	runtime := ctx.Value(api.RuntimeKey).(*libpod.Runtime)
 	var containerEngine entities.ContainerEngine = &abi.ContainerEngine{Libpod: runtime}
 
@@ -143,6 +144,7 @@ func createContainerWaitFn(ctx context.Context, containerName string, interval t
 		opts := entities.WaitOptions{
 			Condition: conditions,
 			Interval:  interval,
+			Ready:     ready,
 		}
 		ctrWaitReport, err := containerEngine.ContainerWait(ctx, []string{containerName}, opts)
 		if err != nil {
@@ -164,7 +166,7 @@ func isValidDockerCondition(cond string) bool {
 }
 
 func waitDockerCondition(ctx context.Context, containerName string, interval time.Duration, dockerCondition string) (int32, error) {
-	containerWait := createContainerWaitFn(ctx, containerName, interval)
+	containerWait := createContainerWaitFn(ctx, containerName, interval, false)
 
 	var err error
 	var code int32
@@ -1001,11 +1001,23 @@ func (s *APIServer) registerContainersHandlers(r *mux.Router) error {
 	//    type: string
 	//    description: Only return this number of log lines from the end of the logs
 	//    default: all
+	//  - in: query
+	//    name: grep
+	//    type: string
+	//    description: Only return log lines matching this regular expression. Partial lines are matched once the line is complete.
+	//  - in: query
+	//    name: format
+	//    type: string
+	//    description: |
+	//      Set to "json" to return one JSON object per log line with the time, stream, containerId, containerName, partial and message keys.
+	//      The objects are sent on the stdout stream.
 	// produces:
 	// - application/json
 	// responses:
 	//   200:
 	//     description:  logs returned as a stream in response body.
+	//   400:
+	//      $ref: "#/responses/badParamError"
 	//   404:
 	//      $ref: "#/responses/containerNotFound"
 	//   500:
@@ -1235,7 +1247,12 @@ func (s *APIServer) registerContainersHandlers(r *mux.Router) error {
 	//       - exited
 	//       - removing
 	//       - stopping
-	//    description: "Conditions to wait for. If no condition provided the 'exited' condition is assumed."
+	//    description: "Conditions to wait for. If no condition provided the 'exited' condition is assumed unless ready is set."
+	//  - in: query
+	//    name: ready
+	//    type: boolean
+	//    default: false
+	//    description: Wait for the container to be running and to pass its readiness check in addition to the conditions.
 	//  - in: query
 	//    name: interval
 	//    type: string
@@ -16,6 +16,8 @@ func (s *APIServer) registerEventsHandlers(r *mux.Router) error {
 	// description: Returns events filtered on query parameters
 	// produces:
 	// - application/json
+	// - application/cloudevents+json
+	// - application/vnd.podman.docker-events.v1+json
 	// parameters:
 	// - name: since
 	//   type: string
@@ -29,6 +31,13 @@ func (s *APIServer) registerEventsHandlers(r *mux.Router) error {
 	//   type: string
 	//   in: query
 	//   description: JSON encoded map[string][]string of constraints
+	// - name: Accept
+	//   type: string
+	//   in: header
+	//   description: |
+	//     Format of the events.  `application/cloudevents+json` returns CloudEvents 1.0 in the structured JSON format
+	//     and `application/vnd.podman.docker-events.v1+json` returns Docker-compatible events.  The Docker-compatible
+	//     events are also the data of the CloudEvents.
 	// responses:
 	//   200:
 	//     description: returns a string of json data describing an event
@@ -45,6 +54,8 @@ func (s *APIServer) registerEventsHandlers(r *mux.Router) error {
 	// description: Returns events filtered on query parameters
 	// produces:
 	// - application/json
+	// - application/cloudevents+json
+	// - application/vnd.podman.docker-events.v1+json
 	// parameters:
 	// - name: since
 	//   type: string
@@ -63,6 +74,17 @@ func (s *APIServer) registerEventsHandlers(r *mux.Router) error {
 	//   in: query
 	//   default: true
 	//   description: when false, do not follow events
+	// - name: count
+	//   type: integer
+	//   in: query
+	//   description: only return the specified number of most recent events before following new ones
+	// - name: Accept
+	//   type: string
+	//   in: header
+	//   description: |
+	//     Format of the events.  `application/cloudevents+json` returns CloudEvents 1.0 in the structured JSON format
+	//     and `application/vnd.podman.docker-events.v1+json` returns Docker-compatible events.  The Docker-compatible
+	//     events are also the data of the CloudEvents.
 	// responses:
 	//   200:
 	//     description: returns a string of json data describing an event
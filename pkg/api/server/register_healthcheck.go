@@ -20,6 +20,10 @@ func (s *APIServer) registerHealthCheckHandlers(r *mux.Router) error {
 	//    type: string
 	//    required: true
 	//    description: the name or ID of the container
+	//  - in: query
+	//    name: readiness
+	//    type: boolean
+	//    description: run the readiness check of the container instead of its healthcheck
 	// produces:
 	// - application/json
 	// responses:
@@ -32,5 +36,35 @@ func (s *APIServer) registerHealthCheckHandlers(r *mux.Router) error {
 	//   500:
 	//     $ref: '#/responses/internalError'
 	r.Handle(VersionedPath("/libpod/containers/{name:.*}/healthcheck"), s.APIHandler(libpod.RunHealthCheck)).Methods(http.MethodGet)
+	// swagger:operation GET /libpod/containers/{name}/healthcheck/log libpod ContainerHealthcheckLogLibpod
+	// ---
+	// tags:
+	//  - containers
+	// summary: Read a container's healthcheck log
+	// description: Return the results of the container's healthchecks recorded at the destination of its healthcheck log
+	// parameters:
+	//  - in: path
+	//    name: name
+	//    type: string
+	//    required: true
+	//    description: the name or ID of the container
+	//  - in: query
+	//    name: since
+	//    type: string
+	//    description: only return the results of healthchecks started at or after the given time (a timestamp or a duration relative to now)
+	// produces:
+	// - application/json
+	// responses:
+	//   200:
+	//     $ref: "#/responses/healthCheckLog"
+	//   400:
+	//     $ref: "#/responses/badParamError"
+	//   404:
+	//     $ref: "#/responses/containerNotFound"
+	//   409:
+	//     description: container has no healthcheck
+	//   500:
+	//     $ref: '#/responses/internalError'
+	r.Handle(VersionedPath("/libpod/containers/{name:.*}/healthcheck/log"), s.APIHandler(libpod.HealthCheckLog)).Methods(http.MethodGet)
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/containers/common/libimage"
 	"github.com/containers/common/pkg/config"
@@ -29,6 +30,9 @@ const (
 	PolicyRegistryImage = "registry"
 	// PolicyLocalImage is the policy to run auto-update based on a local image
 	PolicyLocalImage = "local"
+	// PolicySemver is the policy to update to the highest tag in the
+	// image's repository matching the semver constraint of the container.
+	PolicySemver = "semver"
 )
 
 // Map for easy lookups of supported policies.
@@ -38,11 +42,13 @@ var supportedPolicies = map[string]Policy{
 	"image":                     PolicyRegistryImage, // Deprecated in favor of PolicyRegistryImage
 	string(PolicyRegistryImage): PolicyRegistryImage,
 	string(PolicyLocalImage):    PolicyLocalImage,
+	string(PolicySemver):        PolicySemver,
 }
 
 // updater includes shared state for auto-updating one or more containers.
 type updater struct {
 	conn             *dbus.Conn                  // DBUS connection
+	kube             KubePlayer                  // Replays Kubernetes YAML
 	options          *entities.AutoUpdateOptions // User-specified options
 	unitToTasks      map[string][]*task          // Keeps track of tasks per unit
 	updatedRawImages map[string]bool             // Keeps track of updated images
@@ -55,18 +61,33 @@ const (
 	statusNotUpdated = "false"       // No update was needed
 	statusPending    = "pending"     // The update is pending (see options.DryRun)
 	statusRolledBack = "rolled back" // Rollback after a failed update
+
+	statusRolledBackUnhealthy = "rolled-back (unhealthy)" // Rollback after the container did not turn healthy
 )
 
+// healthCheckInterval is the interval in which the health status of updated
+// containers is checked when waiting for them to turn healthy.
+const healthCheckInterval = time.Second
+
 // task includes data and state for updating a container
 type task struct {
-	authfile     string            // Container-specific authfile
-	auto         *updater          // Reverse pointer to the updater
-	container    *libpod.Container // Container to update
-	policy       Policy            // Update policy
-	image        *libimage.Image   // Original image before the update
-	rawImageName string            // The container's raw image name
-	status       string            // Auto-update status
-	unit         string            // Name of the systemd unit
+	authfile         string            // Container-specific authfile
+	auto             *updater          // Reverse pointer to the updater
+	container        *libpod.Container // Container to update
+	healthTimeout    time.Duration     // Time to wait for the updated container to turn healthy
+	policy           Policy            // Update policy
+	image            *libimage.Image   // Original image before the update
+	rawImageName     string            // The container's raw image name
+	semverConstraint string            // Semver constraint (see PolicySemver)
+	kube             *kubePlay         // Kubernetes YAML of the container's pod if not running in systemd
+	spec             []byte            // Serialized spec to recreate the container if not running in systemd
+	oldTag           string            // Image tag before the update (see PolicySemver)
+	newTag           string            // Image tag of the update (see PolicySemver)
+	newImageName     string            // Image reference with the new tag to recreate the container on (see PolicySemver)
+	dropIn           *imageDropIn      // Drop-in of the container's systemd unit setting newImageName (see PolicySemver)
+	newImageID       string            // ID of the image the container is updated to
+	status           string            // Auto-update status
+	unit             string            // Name of the systemd unit
 }
 
 // LookupPolicy looks up the corresponding Policy for the specified
@@ -104,14 +125,19 @@ func LookupPolicy(s string) (Policy, error) {
 // of a running container is different than the local one. If the image digests
 // differ, it restarts the systemd unit with the new image.
 //
+// Containers not running in a systemd unit are recreated on the new image
+// directly.  Pods created via `podman-play-kube` are replayed from their
+// Kubernetes YAML via the specified KubePlayer.
+//
 // It returns a slice of successfully restarted systemd units and a slice of
 // errors encountered during auto update.
-func AutoUpdate(ctx context.Context, runtime *libpod.Runtime, options entities.AutoUpdateOptions) ([]*entities.AutoUpdateReport, []error) {
+func AutoUpdate(ctx context.Context, runtime *libpod.Runtime, kube KubePlayer, options entities.AutoUpdateOptions) ([]*entities.AutoUpdateReport, []error) {
 	// Note that (most) errors are non-fatal such that a single
 	// misconfigured container does not prevent others from being updated
 	// (which could be a security threat).
 
 	auto := updater{
+		kube:             kube,
 		options:          &options,
 		runtime:          runtime,
 		updatedRawImages: make(map[string]bool),
@@ -125,15 +151,17 @@ func AutoUpdate(ctx context.Context, runtime *libpod.Runtime, options entities.A
 		return nil, allErrors
 	}
 
-	// Connect to DBUS.
-	conn, err := systemd.ConnectToDBUS()
-	if err != nil {
-		logrus.Errorf(err.Error())
-		allErrors = append(allErrors, err)
-		return nil, allErrors
+	// Connect to DBUS if any container runs in a systemd unit.
+	if auto.hasSystemdUnits() {
+		conn, err := systemd.ConnectToDBUS()
+		if err != nil {
+			logrus.Errorf(err.Error())
+			allErrors = append(allErrors, err)
+			return nil, allErrors
+		}
+		defer conn.Close()
+		auto.conn = conn
 	}
-	defer conn.Close()
-	auto.conn = conn
 
 	runtime.NewSystemEvent(events.AutoUpdate)
 
@@ -144,13 +172,36 @@ func AutoUpdate(ctx context.Context, runtime *libpod.Runtime, options entities.A
 		allErrors = append(allErrors, unitErrors...)
 		for _, task := range tasks {
 			allReports = append(allReports, task.report())
+			if task.newImageID != "" {
+				if err := task.addRecord(task.image.ID(), task.newImageID); err != nil {
+					allErrors = append(allErrors, err)
+				}
+			}
+			if task.newTag != "" && task.status == statusUpdated {
+				runtime.NewAutoUpdateEvent(task.container, task.imageName(), map[string]string{
+					"old_tag": task.oldTag,
+					"new_tag": task.newTag,
+				})
+			}
 		}
 	}
 
 	return allReports, allErrors
 }
 
-// updateUnit auto updates the tasks in the specified systemd unit.
+// hasSystemdUnits returns whether any task runs in a systemd unit.
+func (u *updater) hasSystemdUnits() bool {
+	for _, tasks := range u.unitToTasks {
+		if tasks[0].unit != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// updateUnit auto updates the tasks in the specified unit.  Note that the
+// unit is either a systemd unit or, for containers not running in systemd,
+// the container or the Kubernetes YAML (see restartUnit).
 func (u *updater) updateUnit(ctx context.Context, unit string, tasks []*task) []error {
 	var errors []error
 	tasksUpdated := false
@@ -178,6 +229,13 @@ func (u *updater) updateUnit(ctx context.Context, unit string, tasks []*task) []
 				return fmt.Errorf("updating image for container %s: %w", task.container.ID(), err)
 			}
 
+			newImage, _, err := u.runtime.LibimageRuntime().LookupImage(task.imageName(), nil)
+			if err != nil {
+				task.status = statusFailed
+				return fmt.Errorf("looking up updated image of container %s: %w", task.container.ID(), err)
+			}
+			task.newImageID = newImage.ID()
+
 			tasksUpdated = true
 			return nil
 		}()
@@ -192,7 +250,13 @@ func (u *updater) updateUnit(ctx context.Context, unit string, tasks []*task) []
 		return errors
 	}
 
-	updateError := u.restartSystemdUnit(ctx, unit)
+	updateError := u.restartUnit(ctx, unit, tasks)
+	unhealthy := false
+	if updateError == nil {
+		if updateError = u.waitHealthy(ctx, tasks); updateError != nil {
+			unhealthy = true
+		}
+	}
 	for _, task := range tasks {
 		if updateError == nil {
 			task.status = statusUpdated
@@ -203,7 +267,10 @@ func (u *updater) updateUnit(ctx context.Context, unit string, tasks []*task) []
 
 	// Jump to the next unit on successful update or if rollbacks are disabled.
 	if updateError == nil || !u.options.Rollback {
-		if updateError != nil {
+		switch {
+		case unhealthy:
+			errors = append(errors, fmt.Errorf("updating unit %s: %w", unit, updateError))
+		case updateError != nil:
 			errors = append(errors, fmt.Errorf("restarting unit %s during update: %w", unit, updateError))
 		}
 		return errors
@@ -217,7 +284,7 @@ func (u *updater) updateUnit(ctx context.Context, unit string, tasks []*task) []
 		}
 	}
 
-	if err := u.restartSystemdUnit(ctx, unit); err != nil {
+	if err := u.restartUnit(ctx, unit, tasks); err != nil {
 		for _, task := range tasks {
 			task.status = statusFailed
 		}
@@ -228,11 +295,70 @@ func (u *updater) updateUnit(ctx context.Context, unit string, tasks []*task) []
 
 	for _, task := range tasks {
 		task.status = statusRolledBack
+		if unhealthy {
+			task.status = statusRolledBackUnhealthy
+		}
+	}
+
+	if unhealthy {
+		logrus.Warnf("Rolled back unit %s: %v", unit, updateError)
 	}
 
 	return errors
 }
 
+// waitHealthy waits for the updated containers of the tasks to turn healthy.
+// Tasks without a health timeout are skipped as are containers without a
+// healthcheck.  An error is returned if a container turns unhealthy or does
+// not turn healthy within the task's health timeout.
+func (u *updater) waitHealthy(ctx context.Context, tasks []*task) error {
+	for _, task := range tasks {
+		if task.healthTimeout <= 0 {
+			continue
+		}
+		if err := task.waitHealthy(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitHealthy waits for the task's container to turn healthy.  Note that the
+// container is looked up by name as restarting the systemd unit usually
+// creates a new container.
+func (t *task) waitHealthy(ctx context.Context) error {
+	name := t.container.Name()
+	deadline := time.Now().Add(t.healthTimeout)
+	for {
+		ctr, err := t.auto.runtime.LookupContainer(name)
+		if err == nil {
+			if !ctr.HasHealthCheck() {
+				logrus.Warnf("Container %s has no healthcheck: not waiting for it to turn healthy", name)
+				return nil
+			}
+			status, err := ctr.HealthCheckStatus()
+			switch {
+			case err != nil:
+				logrus.Debugf("Checking health status of container %s: %v", name, err)
+			case status == define.HealthCheckHealthy:
+				return nil
+			case status == define.HealthCheckUnhealthy:
+				return fmt.Errorf("container %s turned unhealthy after the update", name)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("container %s did not turn healthy within %s after the update", name, t.healthTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(healthCheckInterval):
+		}
+	}
+}
+
 // report creates an auto-update report for the task.
 func (t *task) report() *entities.AutoUpdateReport {
 	return &entities.AutoUpdateReport{
@@ -242,6 +368,8 @@ func (t *task) report() *entities.AutoUpdateReport {
 		Policy:        string(t.policy),
 		SystemdUnit:   t.unit,
 		Updated:       t.status,
+		OldTag:        t.oldTag,
+		NewTag:        t.newTag,
 	}
 }
 
@@ -252,6 +380,8 @@ func (t *task) updateAvailable(ctx context.Context) (bool, error) {
 		return t.registryUpdateAvailable(ctx)
 	case PolicyLocalImage:
 		return t.localUpdateAvailable()
+	case PolicySemver:
+		return t.semverUpdateAvailable(ctx)
 	default:
 		return false, fmt.Errorf("unexpected auto-update policy %s for container %s", t.policy, t.container.ID())
 	}
@@ -265,6 +395,8 @@ func (t *task) update(ctx context.Context) error {
 	case PolicyLocalImage:
 		// Nothing to do as the image is already available in the local storage.
 		return nil
+	case PolicySemver:
+		return t.semverUpdate(ctx)
 	default:
 		return fmt.Errorf("unexpected auto-update policy %s for container %s", t.policy, t.container.ID())
 	}
@@ -315,6 +447,13 @@ func (t *task) localUpdateAvailable() (bool, error) {
 
 // rollbackImage rolls back the task's image to the previous version before the update.
 func (t *task) rollbackImage() error {
+	// The container is recreated on its raw image name, which has not
+	// been retagged.
+	if t.newImageName != "" {
+		t.newImageName = ""
+		return nil
+	}
+
 	// To fallback, simply retag the old image and restart the service.
 	if err := t.image.Tag(t.rawImageName); err != nil {
 		return err
@@ -389,6 +528,7 @@ func (u *updater) assembleTasks(ctx context.Context) []error {
 	}
 
 	u.unitToTasks = make(map[string][]*task)
+	kubeKeys := make(map[string]string) // Kubernetes YAML -> unit key
 
 	errors := []error{}
 	for _, c := range allContainers {
@@ -419,16 +559,23 @@ func (u *updater) assembleTasks(ctx context.Context) []error {
 			continue
 		}
 
-		// Make sure the container runs in a systemd unit which is
-		// stored as a label at container creation.
+		// Check if the container runs in a systemd unit which is
+		// stored as a label at container creation.  If not, the
+		// container or its Kubernetes YAML is recreated directly.
 		unit, exists, err := u.systemdUnitForContainer(ctr, labels)
 		if err != nil {
 			errors = append(errors, err)
 			continue
 		}
+		key := unit
+		var kube *kubePlay
 		if !exists {
-			errors = append(errors, fmt.Errorf("auto-updating container %q: no %s label found", ctr.ID(), systemdDefine.EnvVariable))
-			continue
+			unit = ""
+			key, kube, err = u.fallbackUnitForContainer(ctr, kubeKeys)
+			if err != nil {
+				errors = append(errors, err)
+				continue
+			}
 		}
 
 		id, _ := ctr.Image()
@@ -445,19 +592,39 @@ func (u *updater) assembleTasks(ctx context.Context) []error {
 			continue
 		}
 
+		healthTimeout := u.options.HealthTimeout
+		if value, exists := labels[define.AutoUpdateHealthTimeoutLabel]; exists {
+			healthTimeout, err = time.ParseDuration(value)
+			if err != nil {
+				errors = append(errors, fmt.Errorf("auto-updating container %q: invalid %s label: %w", ctr.ID(), define.AutoUpdateHealthTimeoutLabel, err))
+				continue
+			}
+		}
+
+		semverConstraint := labels[define.AutoUpdateSemverLabel]
+		if policy == PolicySemver {
+			if _, err := parseSemverConstraint(semverConstraint); err != nil {
+				errors = append(errors, fmt.Errorf("auto-updating container %q: invalid %s label: %w", ctr.ID(), define.AutoUpdateSemverLabel, err))
+				continue
+			}
+		}
+
 		t := task{
-			authfile:     labels[define.AutoUpdateAuthfileLabel],
-			auto:         u,
-			container:    ctr,
-			policy:       policy,
-			image:        image,
-			unit:         unit,
-			rawImageName: rawImageName,
-			status:       statusFailed, // must be updated later on
+			authfile:         labels[define.AutoUpdateAuthfileLabel],
+			auto:             u,
+			container:        ctr,
+			healthTimeout:    healthTimeout,
+			policy:           policy,
+			image:            image,
+			unit:             unit,
+			rawImageName:     rawImageName,
+			semverConstraint: semverConstraint,
+			kube:             kube,
+			status:           statusFailed, // must be updated later on
 		}
 
 		// Add the task to the unit.
-		u.unitToTasks[unit] = append(u.unitToTasks[unit], &t)
+		u.unitToTasks[key] = append(u.unitToTasks[key], &t)
 	}
 
 	return errors
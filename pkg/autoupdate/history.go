@@ -0,0 +1,208 @@
+package autoupdate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/containers/podman/v4/libpod"
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/containers/podman/v4/pkg/domain/entities"
+	"github.com/containers/podman/v4/pkg/systemd"
+	"github.com/sirupsen/logrus"
+)
+
+// addRecord adds a record of the task's update from the old to the new image
+// to the database.  The old image is protected from being pruned for the
+// image retention of the updater's options.
+func (t *task) addRecord(oldImageID, newImageID string) error {
+	now := time.Now()
+	record := &define.AutoUpdateRecord{
+		ContainerID:    t.container.ID(),
+		ContainerName:  t.container.Name(),
+		SystemdUnit:    t.unit,
+		ImageName:      t.rawImageName,
+		OldImageID:     oldImageID,
+		NewImageID:     newImageID,
+		Time:           now,
+		Result:         t.status,
+		ProtectedUntil: now.Add(t.auto.options.ImageRetention),
+	}
+	if err := t.auto.runtime.AddAutoUpdateRecord(record); err != nil {
+		return fmt.Errorf("recording update of container %s: %w", t.container.ID(), err)
+	}
+	return nil
+}
+
+// History returns the records of all updates performed by auto-update in the
+// order they were performed.
+func History(runtime *libpod.Runtime) ([]*entities.AutoUpdateHistoryReport, error) {
+	records, err := runtime.AutoUpdateRecords()
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]*entities.AutoUpdateHistoryReport, len(records))
+	for i, record := range records {
+		reports[i] = &entities.AutoUpdateHistoryReport{AutoUpdateRecord: *record}
+	}
+	return reports, nil
+}
+
+// ProtectedImages returns the IDs of the images which are still needed to roll
+// back updates.  Such images must not be pruned until their retention
+// expires.
+func ProtectedImages(runtime *libpod.Runtime) (map[string]bool, error) {
+	records, err := runtime.AutoUpdateRecords()
+	if err != nil {
+		return nil, err
+	}
+	protected := make(map[string]bool)
+	now := time.Now()
+	for _, record := range records {
+		if record.ProtectedUntil.After(now) {
+			protected[record.OldImageID] = true
+		}
+	}
+	return protected, nil
+}
+
+// Rollback rolls back the latest update of the specified container or of all
+// containers updated in the specified systemd unit.  The container's previous
+// image is tagged with the container's raw image name another time and the
+// unit is restarted (see restartUnit).  Containers moved to a new tag by the
+// semver policy are recreated on their previous image name.  Only successful
+// updates can be rolled back, so the latest update of a container can be
+// rolled back at most once.
+func Rollback(ctx context.Context, runtime *libpod.Runtime, kube KubePlayer, nameOrUnit string) ([]*entities.AutoUpdateReport, []error) {
+	records, err := runtime.AutoUpdateRecords()
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	// Updates usually recreate the container, so records are matched by
+	// the container's name rather than its ID.
+	name := nameOrUnit
+	if ctr, err := runtime.LookupContainer(nameOrUnit); err == nil {
+		name = ctr.Name()
+	}
+
+	var names []string
+	latest := make(map[string]*define.AutoUpdateRecord) // container name -> latest record
+	for _, record := range records {
+		if record.ContainerName != name && record.SystemdUnit != nameOrUnit {
+			continue
+		}
+		if _, exists := latest[record.ContainerName]; !exists {
+			names = append(names, record.ContainerName)
+		}
+		latest[record.ContainerName] = record
+	}
+	if len(names) == 0 {
+		return nil, []error{fmt.Errorf("no auto-update found for container or unit %q", nameOrUnit)}
+	}
+
+	auto := updater{
+		kube:             kube,
+		options:          &entities.AutoUpdateOptions{},
+		runtime:          runtime,
+		unitToTasks:      make(map[string][]*task),
+		updatedRawImages: make(map[string]bool),
+	}
+	kubeKeys := make(map[string]string) // Kubernetes YAML -> unit key
+
+	var allErrors []error
+	for _, name := range names {
+		record := latest[name]
+		if record.Result != statusUpdated {
+			allErrors = append(allErrors, fmt.Errorf("rolling back container %s: latest update has status %q", name, record.Result))
+			continue
+		}
+
+		ctr, err := runtime.LookupContainer(name)
+		if err != nil {
+			allErrors = append(allErrors, fmt.Errorf("rolling back container %s: %w", name, err))
+			continue
+		}
+
+		image, _, err := runtime.LibimageRuntime().LookupImage(record.OldImageID, nil)
+		if err != nil {
+			allErrors = append(allErrors, fmt.Errorf("looking up previous image of container %s: %w", name, err))
+			continue
+		}
+
+		key := record.SystemdUnit
+		var kube *kubePlay
+		if key == "" {
+			key, kube, err = auto.fallbackUnitForContainer(ctr, kubeKeys)
+			if err != nil {
+				allErrors = append(allErrors, err)
+				continue
+			}
+		}
+
+		t := task{
+			auto:         &auto,
+			container:    ctr,
+			policy:       Policy(ctr.Labels()[define.AutoUpdateLabel]),
+			image:        image,
+			unit:         record.SystemdUnit,
+			rawImageName: record.ImageName,
+			kube:         kube,
+			newImageID:   record.NewImageID,
+			status:       statusFailed, // must be updated later on
+		}
+		auto.unitToTasks[key] = append(auto.unitToTasks[key], &t)
+	}
+
+	if len(auto.unitToTasks) == 0 {
+		return nil, allErrors
+	}
+
+	if auto.hasSystemdUnits() {
+		conn, err := systemd.ConnectToDBUS()
+		if err != nil {
+			allErrors = append(allErrors, err)
+			return nil, allErrors
+		}
+		defer conn.Close()
+		auto.conn = conn
+	}
+
+	var allReports []*entities.AutoUpdateReport
+	for unit, tasks := range auto.unitToTasks {
+		if err := auto.rollbackUnit(ctx, unit, tasks); err != nil {
+			allErrors = append(allErrors, err)
+		}
+		for _, task := range tasks {
+			allReports = append(allReports, task.report())
+			if task.status != statusRolledBack {
+				continue
+			}
+			if err := task.addRecord(task.newImageID, task.image.ID()); err != nil {
+				allErrors = append(allErrors, err)
+			}
+		}
+	}
+
+	return allReports, allErrors
+}
+
+// rollbackUnit retags the previous images of the tasks and restarts the
+// specified unit.
+func (u *updater) rollbackUnit(ctx context.Context, unit string, tasks []*task) error {
+	for _, task := range tasks {
+		if err := task.rollbackImage(); err != nil {
+			return fmt.Errorf("rolling back image for container %s in unit %s: %w", task.container.ID(), unit, err)
+		}
+	}
+
+	if err := u.restartUnit(ctx, unit, tasks); err != nil {
+		return fmt.Errorf("restarting unit %s during rollback: %w", unit, err)
+	}
+
+	for _, task := range tasks {
+		task.status = statusRolledBack
+	}
+	logrus.Infof("Successfully rolled back unit %s", unit)
+	return nil
+}
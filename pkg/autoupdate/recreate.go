@@ -0,0 +1,335 @@
+package autoupdate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/containers/podman/v4/libpod"
+	"github.com/containers/podman/v4/libpod/define"
+	"github.com/containers/podman/v4/pkg/domain/entities"
+	"github.com/containers/podman/v4/pkg/specgen"
+	"github.com/containers/podman/v4/pkg/specgen/generate"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// KubePlayer plays and tears down Kubernetes YAML.  It is used to replay the
+// YAML of pods created via `podman-play-kube` that are not running in a
+// systemd unit.
+type KubePlayer interface {
+	PlayKube(ctx context.Context, body io.Reader, opts entities.PlayKubeOptions) (*entities.PlayKubeReport, error)
+	PlayKubeDown(ctx context.Context, body io.Reader, opts entities.PlayKubeDownOptions) (*entities.PlayKubeReport, error)
+}
+
+// kubePlay is the Kubernetes YAML of pods created via `podman-play-kube` along
+// with the options the YAML has been played with.
+type kubePlay struct {
+	yaml    string // Kubernetes YAML of the pods
+	options string // JSON-encoded entities.PlayKubeOptions
+}
+
+// fallbackUnitForContainer returns the key to group the container's update
+// task by if the container does not run in a systemd unit.  Containers in pods
+// created via `podman-play-kube` are grouped by the pod's Kubernetes YAML
+// which is returned along with its options, such that a pod is replayed at
+// most once.  All other containers are recreated individually.
+func (u *updater) fallbackUnitForContainer(c *libpod.Container, kubeKeys map[string]string) (string, *kubePlay, error) {
+	podID := c.ConfigNoCopy().Pod
+	if podID != "" {
+		pod, err := u.runtime.LookupPod(podID)
+		if err != nil {
+			return "", nil, fmt.Errorf("looking up pod of container %s: %w", c.ID(), err)
+		}
+		if kubeYAML := pod.KubeYAML(); kubeYAML != "" {
+			key, exists := kubeKeys[kubeYAML]
+			if !exists {
+				key = "kube pod " + pod.Name()
+				kubeKeys[kubeYAML] = key
+			}
+			return key, &kubePlay{yaml: kubeYAML, options: pod.KubePlayOptions()}, nil
+		}
+	}
+	return "container " + c.Name(), nil, nil
+}
+
+// restartUnit restarts the specified unit of the tasks.  If the tasks run in
+// a systemd unit, the unit is restarted.  Otherwise, the Kubernetes YAML of the
+// tasks is replayed or the task's container is recreated.
+func (u *updater) restartUnit(ctx context.Context, unit string, tasks []*task) error {
+	switch {
+	case tasks[0].unit != "":
+		if err := u.updateImageDropIns(ctx, tasks); err != nil {
+			return err
+		}
+		return u.restartSystemdUnit(ctx, unit)
+	case tasks[0].kube != nil:
+		kubeYAML, err := kubeYAMLWithImages(tasks[0].kube.yaml, imageReplacements(tasks))
+		if err != nil {
+			return err
+		}
+		return u.replayKube(ctx, unit, kubeYAML, tasks[0].kube.options)
+	default:
+		return tasks[0].recreateContainer(ctx)
+	}
+}
+
+// imageReplacements returns a map from the raw image names of the tasks'
+// containers to the tasks' image names if they differ.
+func imageReplacements(tasks []*task) map[string]string {
+	images := make(map[string]string)
+	for _, t := range tasks {
+		if raw := t.container.RawImageName(); raw != t.imageName() {
+			images[raw] = t.imageName()
+		}
+	}
+	return images
+}
+
+// kubeYAMLWithImages returns the Kubernetes YAML with the images of its
+// containers replaced according to the specified map of old to new images.
+func kubeYAMLWithImages(kubeYAML string, images map[string]string) (string, error) {
+	if len(images) == 0 {
+		return kubeYAML, nil
+	}
+
+	var buf bytes.Buffer
+	decoder := yaml.NewDecoder(strings.NewReader(kubeYAML))
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for {
+		var document yaml.Node
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return "", fmt.Errorf("parsing Kubernetes YAML: %w", err)
+		}
+		replaceKubeImages(&document, images)
+		if err := encoder.Encode(&document); err != nil {
+			return "", err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// replaceKubeImages replaces the images of all containers and init
+// containers below the node according to the specified map of old to new
+// images.
+func replaceKubeImages(node *yaml.Node, images map[string]string) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if (key.Value != "containers" && key.Value != "initContainers") || value.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, container := range value.Content {
+				replaceContainerImage(container, images)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		replaceKubeImages(child, images)
+	}
+}
+
+// replaceContainerImage replaces the image of the container node according to
+// the specified map of old to new images.
+func replaceContainerImage(container *yaml.Node, images map[string]string) {
+	if container.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(container.Content); i += 2 {
+		key, value := container.Content[i], container.Content[i+1]
+		if key.Value != "image" || value.Kind != yaml.ScalarNode {
+			continue
+		}
+		if image, ok := images[value.Value]; ok {
+			value.Value = image
+		}
+	}
+}
+
+// replayKube tears down the pods of the specified Kubernetes YAML and plays
+// the YAML another time with the specified JSON-encoded options.
+func (u *updater) replayKube(ctx context.Context, unit, kubeYAML, kubeOptions string) error {
+	if u.kube == nil {
+		return errors.New("replaying Kubernetes YAML is not supported")
+	}
+	var options entities.PlayKubeOptions
+	if kubeOptions != "" {
+		if err := json.Unmarshal([]byte(kubeOptions), &options); err != nil {
+			return fmt.Errorf("decoding options of Kubernetes YAML: %w", err)
+		}
+	}
+	if _, err := u.kube.PlayKubeDown(ctx, strings.NewReader(kubeYAML), entities.PlayKubeDownOptions{}); err != nil {
+		return fmt.Errorf("tearing down Kubernetes YAML: %w", err)
+	}
+	if _, err := u.kube.PlayKube(ctx, strings.NewReader(kubeYAML), options); err != nil {
+		return fmt.Errorf("playing Kubernetes YAML: %w", err)
+	}
+	logrus.Infof("Successfully replayed Kubernetes YAML of %s", unit)
+	return nil
+}
+
+// recreateContainer replaces the task's container with a new one with the
+// same configuration, name, volumes and pod membership on the task's image
+// name.  The current container is stopped and renamed aside, and only removed
+// once the new container has started; otherwise it is restored.  The
+// configuration is taken from the original container, so the container can
+// be recreated multiple times (e.g., during a rollback).
+func (t *task) recreateContainer(ctx context.Context) error {
+	runtime := t.auto.runtime
+	name := t.container.Name()
+
+	if t.spec == nil {
+		spec := specgen.NewSpecGenerator(t.rawImageName, false)
+		if _, _, err := generate.ConfigToSpec(runtime, spec, t.container.ID()); err != nil {
+			return fmt.Errorf("getting configuration of container %s: %w", name, err)
+		}
+		spec.Name = name
+		if err := inheritPodNamespaces(runtime, spec); err != nil {
+			return err
+		}
+		serialized, err := json.Marshal(spec)
+		if err != nil {
+			return err
+		}
+		t.spec = serialized
+	}
+
+	spec := &specgen.SpecGenerator{}
+	if err := json.Unmarshal(t.spec, spec); err != nil {
+		return err
+	}
+	spec.Image = t.imageName()
+	spec.RawImageName = t.imageName()
+	if _, err := generate.CompleteSpec(ctx, runtime, spec); err != nil {
+		return err
+	}
+	// Without a terminal, running containers may exit.
+	spec.Terminal = t.container.Terminal()
+
+	// Move the current container, which is either the original one or
+	// the one of a previous attempt, aside.
+	old, err := runtime.LookupContainer(name)
+	switch {
+	case err == nil:
+	case errors.Is(err, define.ErrNoSuchCtr):
+		old = nil
+	default:
+		return err
+	}
+	wasRunning := false
+	if old != nil {
+		state, err := old.State()
+		if err != nil {
+			return err
+		}
+		wasRunning = state == define.ContainerStateRunning
+		if wasRunning {
+			if err := old.Stop(); err != nil {
+				return fmt.Errorf("stopping container %s: %w", name, err)
+			}
+		}
+		old, err = runtime.RenameContainer(ctx, old, fmt.Sprintf("%s-%s", name, old.ID()[:12]))
+		if err != nil {
+			return fmt.Errorf("renaming container %s: %w", name, err)
+		}
+	}
+
+	if err := createAndStartContainer(ctx, runtime, spec, t.container); err != nil {
+		if old != nil {
+			restoreContainer(ctx, runtime, old, name, wasRunning)
+		}
+		return err
+	}
+
+	// Remove the old container.  Volumes are preserved.
+	if old != nil {
+		if err := runtime.RemoveContainer(ctx, old, true, false, nil); err != nil {
+			logrus.Errorf("Removing old container %s of %s: %v", old.ID(), name, err)
+		}
+	}
+
+	logrus.Infof("Successfully recreated container %s", name)
+	return nil
+}
+
+// createAndStartContainer creates and starts a container from the completed
+// spec.  The container is removed if it cannot be started.
+func createAndStartContainer(ctx context.Context, runtime *libpod.Runtime, spec *specgen.SpecGenerator, original *libpod.Container) error {
+	rtSpec, spec, opts, err := generate.MakeContainer(ctx, runtime, spec, true, original)
+	if err != nil {
+		return fmt.Errorf("recreating container %s: %w", spec.Name, err)
+	}
+	ctr, err := generate.ExecuteCreate(ctx, runtime, rtSpec, spec, false, opts...)
+	if err != nil {
+		return fmt.Errorf("recreating container %s: %w", spec.Name, err)
+	}
+	if err := ctr.Start(ctx, true); err != nil {
+		if rmErr := runtime.RemoveContainer(ctx, ctr, true, false, nil); rmErr != nil {
+			logrus.Errorf("Removing recreated container %s: %v", spec.Name, rmErr)
+		}
+		return fmt.Errorf("starting recreated container %s: %w", spec.Name, err)
+	}
+	return nil
+}
+
+// restoreContainer renames the old container moved aside by
+// recreateContainer back to its name and starts it if it was running.
+func restoreContainer(ctx context.Context, runtime *libpod.Runtime, old *libpod.Container, name string, start bool) {
+	old, err := runtime.RenameContainer(ctx, old, name)
+	if err != nil {
+		logrus.Errorf("Restoring name of container %s: %v", name, err)
+		return
+	}
+	if !start {
+		return
+	}
+	if err := old.Start(ctx, true); err != nil {
+		logrus.Errorf("Restarting container %s: %v", name, err)
+	}
+}
+
+// inheritPodNamespaces resets the namespaces the container's pod shares to
+// the default, such that the recreated container joins the namespaces of the
+// pod rather than those of the original infra container.
+func inheritPodNamespaces(runtime *libpod.Runtime, spec *specgen.SpecGenerator) error {
+	if spec.Pod == "" {
+		return nil
+	}
+	pod, err := runtime.LookupPod(spec.Pod)
+	if err != nil {
+		return err
+	}
+
+	if pod.SharesNet() {
+		spec.Networks = nil
+		spec.NetworkOptions = nil
+	}
+
+	allNamespaces := []struct {
+		isShared bool
+		value    *specgen.Namespace
+	}{
+		{pod.SharesPID(), &spec.PidNS},
+		{pod.SharesNet(), &spec.NetNS},
+		{pod.SharesCgroup(), &spec.CgroupNS},
+		{pod.SharesIPC(), &spec.IpcNS},
+		{pod.SharesUTS(), &spec.UtsNS},
+	}
+	for _, n := range allNamespaces {
+		if n.isShared {
+			*n.value = specgen.Namespace{NSMode: specgen.Default}
+		}
+	}
+	return nil
+}
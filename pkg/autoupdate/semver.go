@@ -0,0 +1,382 @@
+package autoupdate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/containers/common/libimage"
+	"github.com/containers/common/pkg/config"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/types"
+	"github.com/containers/podman/v4/pkg/rootless"
+	systemdDefine "github.com/containers/podman/v4/pkg/systemd/define"
+	"github.com/containers/podman/v4/pkg/systemd/parser"
+	"github.com/containers/storage/pkg/homedir"
+	"github.com/containers/storage/pkg/ioutils"
+)
+
+// parseSemverConstraint parses the specified constraint into a semver range.
+// In addition to the range syntax of github.com/blang/semver, the following
+// shorthands are supported:
+//
+//   - "~1.4" and "~1.4.2" allow patch updates (>=1.4.0 <1.5.0 and >=1.4.2 <1.5.0).
+//   - "^1.4" and "^1.4.2" allow minor updates (>=1.4.0 <2.0.0 and >=1.4.2 <2.0.0).
+//     Major version zero is considered unstable, so "^0.4" allows patch
+//     updates only.
+func parseSemverConstraint(constraint string) (semver.Range, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return nil, fmt.Errorf("empty semver constraint")
+	}
+
+	prefix := constraint[0]
+	if prefix != '~' && prefix != '^' {
+		return semver.ParseRange(constraint)
+	}
+
+	lower, err := semver.ParseTolerant(constraint[1:])
+	if err != nil {
+		return nil, fmt.Errorf("parsing semver constraint %q: %w", constraint, err)
+	}
+	lower.Pre = nil
+	lower.Build = nil
+
+	// "~1" is equivalent to "^1".
+	allowMinor := !strings.Contains(constraint[1:], ".") || prefix == '^' && lower.Major > 0
+
+	upper := semver.Version{Major: lower.Major, Minor: lower.Minor + 1}
+	if allowMinor {
+		upper = semver.Version{Major: lower.Major + 1}
+	}
+	return semver.ParseRange(fmt.Sprintf(">=%s <%s", lower, upper))
+}
+
+// parseSemverTag parses the specified image tag as a semantic version.  Tags
+// may be prefixed with a "v" but must otherwise be complete versions.  Note
+// that pre-releases are not considered to be updates and are rejected.
+func parseSemverTag(tag string) (semver.Version, bool) {
+	version, err := semver.Parse(strings.TrimPrefix(tag, "v"))
+	if err != nil || len(version.Pre) > 0 {
+		return semver.Version{}, false
+	}
+	return version, true
+}
+
+// highestSemverTag returns the tag with the highest version in tags matching
+// the specified range.  If no tag matches, an empty string is returned.
+func highestSemverTag(tags []string, constraint semver.Range) string {
+	var (
+		highestTag     string
+		highestVersion semver.Version
+	)
+	for _, tag := range tags {
+		version, ok := parseSemverTag(tag)
+		if !ok || !constraint(version) {
+			continue
+		}
+		if highestTag == "" || version.GT(highestVersion) {
+			highestTag = tag
+			highestVersion = version
+		}
+	}
+	return highestTag
+}
+
+// semverCurrentTag returns the tag the task's image currently corresponds
+// to.  That is the highest semantic version among the image's tags in the
+// repository of the container's raw image name.  If none of the tags is a
+// semantic version, the tag of the raw image name is returned.
+func (t *task) semverCurrentTag(named reference.Named) string {
+	var tags []string
+	for _, name := range t.image.Names() {
+		imageNamed, err := reference.ParseNormalizedNamed(name)
+		if err != nil || imageNamed.Name() != named.Name() {
+			continue
+		}
+		if tagged, ok := imageNamed.(reference.Tagged); ok {
+			tags = append(tags, tagged.Tag())
+		}
+	}
+
+	all := func(semver.Version) bool { return true }
+	if tag := highestSemverTag(tags, all); tag != "" {
+		return tag
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		return tagged.Tag()
+	}
+	return ""
+}
+
+// semverUpdateAvailable returns whether the repository of the container's
+// raw image name has a tag with a higher version matching the task's semver
+// constraint.  If so, the task's oldTag and newTag are set accordingly.
+func (t *task) semverUpdateAvailable(ctx context.Context) (bool, error) {
+	constraint, err := parseSemverConstraint(t.semverConstraint)
+	if err != nil {
+		return false, err
+	}
+
+	named, err := reference.ParseNormalizedNamed(t.rawImageName)
+	if err != nil {
+		return false, err
+	}
+	repository := reference.TrimNamed(named)
+	remoteRef, err := docker.NewReference(repository)
+	if err != nil {
+		return false, err
+	}
+
+	sys := &types.SystemContext{AuthFilePath: t.authfile}
+	tags, err := docker.GetRepositoryTags(ctx, sys, remoteRef)
+	if err != nil {
+		return false, fmt.Errorf("listing tags of %s: %w", repository.Name(), err)
+	}
+
+	newTag := highestSemverTag(tags, constraint)
+	if newTag == "" {
+		return false, fmt.Errorf("no tag of %s matches semver constraint %q", repository.Name(), t.semverConstraint)
+	}
+
+	t.oldTag = t.semverCurrentTag(named)
+	if oldVersion, ok := parseSemverTag(t.oldTag); ok {
+		newVersion, _ := parseSemverTag(newTag)
+		// Never downgrade, for instance, when a tag has been removed
+		// from the repository.
+		if newVersion.LT(oldVersion) {
+			return false, nil
+		}
+	}
+
+	newNamed, err := reference.WithTag(repository, newTag)
+	if err != nil {
+		return false, err
+	}
+	newRef, err := docker.NewReference(newNamed)
+	if err != nil {
+		return false, err
+	}
+	options := &libimage.HasDifferentDigestOptions{AuthFilePath: t.authfile}
+	differs, err := t.image.HasDifferentDigest(ctx, newRef, options)
+	if err != nil {
+		return false, err
+	}
+	if differs {
+		t.newTag = newTag
+	}
+	return differs, nil
+}
+
+// semverUpdate pulls down the image with the task's new tag.  Note that the
+// image is not tagged with the container's raw image name as that would move
+// a pinned tag (e.g., app:1.4.0) to the content of another version.  Instead,
+// the task's new image name is set, on which restartUnit recreates the
+// container.
+func (t *task) semverUpdate(ctx context.Context) error {
+	named, err := reference.ParseNormalizedNamed(t.rawImageName)
+	if err != nil {
+		return err
+	}
+	newNamed, err := reference.WithTag(reference.TrimNamed(named), t.newTag)
+	if err != nil {
+		return err
+	}
+
+	pullOptions := &libimage.PullOptions{}
+	pullOptions.AuthFilePath = t.authfile
+	pullOptions.Writer = os.Stderr
+	pulledImages, err := t.auto.runtime.LibimageRuntime().Pull(ctx, newNamed.String(), config.PullPolicyAlways, pullOptions)
+	if err != nil {
+		return err
+	}
+	if len(pulledImages) == 0 {
+		return fmt.Errorf("internal error: no image pulled for %s", newNamed.String())
+	}
+	if newNamed.String() != named.String() {
+		t.newImageName = newNamed.String()
+	}
+	return nil
+}
+
+// imageName returns the image reference the task's container is recreated
+// on.  That is the raw image name unless a semver update has moved the
+// container to a new tag.
+func (t *task) imageName() string {
+	if t.newImageName != "" {
+		return t.newImageName
+	}
+	return t.rawImageName
+}
+
+// imageDropInName is the name of the drop-in overriding the ExecStart of
+// a container's systemd unit to run the container on a new tag.
+const imageDropInName = "99-podman-auto-update.conf"
+
+// imageDropIn is a drop-in written to a container's systemd unit.
+type imageDropIn struct {
+	path     string // Path of the drop-in
+	existed  bool   // Whether the drop-in existed before the update
+	previous []byte // Content of the drop-in before the update
+	written  bool   // Whether the drop-in has been written by the update
+}
+
+// updateImageDropIns writes the drop-ins of the tasks' systemd units such that
+// the units recreate the containers on the tasks' image names.  Drop-ins
+// written during an update which has been rolled back are restored.  systemd
+// is reloaded if any drop-in has changed.
+func (u *updater) updateImageDropIns(ctx context.Context, tasks []*task) error {
+	changed := false
+	for _, t := range tasks {
+		written := t.dropIn != nil && t.dropIn.written
+		differs := t.imageName() != t.container.RawImageName()
+		var err error
+		switch {
+		case differs && !written:
+			err = t.writeImageDropIn(ctx)
+		case !differs && written:
+			err = t.dropIn.restore()
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return u.conn.ReloadContext(ctx)
+}
+
+// writeImageDropIn writes a drop-in to the task's container unit which
+// overrides the unit's ExecStart with the task's image name in place of the
+// container's raw image name.  Note that the unit
+// of the container is used even if the container is part of a pod as the
+// pod's unit does not create the container.
+func (t *task) writeImageDropIn(ctx context.Context) error {
+	unit := t.container.Labels()[systemdDefine.EnvVariable]
+	if unit == "" {
+		return fmt.Errorf("container %s has no systemd unit to update", t.container.ID())
+	}
+	property, err := t.auto.conn.GetUnitTypePropertyContext(ctx, unit, "Service", "ExecStart")
+	if err != nil {
+		return fmt.Errorf("reading ExecStart of systemd unit %s: %w", unit, err)
+	}
+	content, err := imageDropInContent(property.Value.Value(), t.container.RawImageName(), t.imageName())
+	if err != nil {
+		return fmt.Errorf("updating image of systemd unit %s: %w", unit, err)
+	}
+
+	dir, err := systemdUnitConfigDir()
+	if err != nil {
+		return err
+	}
+	dir = filepath.Join(dir, unit+".d")
+	dropIn := &imageDropIn{path: filepath.Join(dir, imageDropInName)}
+	dropIn.previous, err = os.ReadFile(dropIn.path)
+	switch {
+	case err == nil:
+		dropIn.existed = true
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := ioutils.AtomicWriteFile(dropIn.path, content, 0644); err != nil {
+		return err
+	}
+	dropIn.written = true
+	t.dropIn = dropIn
+	return nil
+}
+
+// restore restores the content of the drop-in before the update.
+func (d *imageDropIn) restore() error {
+	var err error
+	if d.existed {
+		err = ioutils.AtomicWriteFile(d.path, d.previous, 0644)
+	} else {
+		err = os.Remove(d.path)
+	}
+	if err != nil {
+		return err
+	}
+	d.written = false
+	return nil
+}
+
+// systemdUnitConfigDir returns the directory of the systemd unit
+// configuration of the user.
+func systemdUnitConfigDir() (string, error) {
+	if !rootless.IsRootless() {
+		return "/etc/systemd/system", nil
+	}
+	configHome, err := homedir.GetConfigHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configHome, "systemd", "user"), nil
+}
+
+// imageDropInContent returns the content of a drop-in overriding the
+// specified ExecStart property of a unit with newImage in place of oldImage.
+// The property must hold exactly one command, which is the case for units
+// created by `podman generate systemd --new` and Quadlet.
+func imageDropInContent(execStart interface{}, oldImage, newImage string) ([]byte, error) {
+	commands, ok := execStart.([][]interface{})
+	if !ok || len(commands) != 1 || len(commands[0]) < 3 {
+		return nil, fmt.Errorf("unexpected ExecStart %v: expected a single command", execStart)
+	}
+	path, ok := commands[0][0].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected path %v of ExecStart", commands[0][0])
+	}
+	argv, ok := commands[0][1].([]string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected arguments %v of ExecStart", commands[0][1])
+	}
+	ignoreFailure, _ := commands[0][2].(bool)
+
+	replaced := false
+	args := make([]string, 0, len(argv)+1)
+	if len(argv) == 0 || argv[0] != path {
+		// The path differs from argv[0] (i.e., the "@" prefix).
+		args = append(args, "@"+path)
+	}
+	for _, arg := range argv {
+		if arg == oldImage {
+			arg = newImage
+			replaced = true
+		}
+		args = append(args, arg)
+	}
+	if !replaced {
+		return nil, fmt.Errorf("image %s is not an argument of ExecStart", oldImage)
+	}
+	for i := range args {
+		// Specifiers have already been resolved by systemd.
+		args[i] = strings.ReplaceAll(args[i], "%", "%%")
+	}
+	if ignoreFailure {
+		args[0] = "-" + args[0]
+	}
+
+	unitFile := parser.NewUnitFile()
+	unitFile.AddComment("", "Written by podman auto-update to run the container on "+newImage)
+	unitFile.Add("Service", "ExecStart", "")
+	unitFile.AddCmdline("Service", "ExecStart", args)
+	content, err := unitFile.ToString()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
@@ -0,0 +1,125 @@
+package autoupdate
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSemverConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matches    []string
+		rejects    []string
+	}{
+		{"~1.4", []string{"1.4.0", "1.4.9"}, []string{"1.3.9", "1.5.0", "2.0.0"}},
+		{"~1.4.2", []string{"1.4.2", "1.4.10"}, []string{"1.4.1", "1.5.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"0.9.0", "2.0.0"}},
+		{"^1.4", []string{"1.4.0", "1.9.3"}, []string{"1.3.0", "2.0.0"}},
+		{"^0.4", []string{"0.4.0", "0.4.7"}, []string{"0.5.0", "1.0.0"}},
+		{">=1.2.0 <1.3.0", []string{"1.2.0", "1.2.5"}, []string{"1.1.0", "1.3.0"}},
+	}
+
+	for _, test := range tests {
+		constraint, err := parseSemverConstraint(test.constraint)
+		require.NoError(t, err, test.constraint)
+		for _, version := range test.matches {
+			assert.True(t, constraint(semver.MustParse(version)), "%s should match %s", test.constraint, version)
+		}
+		for _, version := range test.rejects {
+			assert.False(t, constraint(semver.MustParse(version)), "%s should not match %s", test.constraint, version)
+		}
+	}
+
+	for _, constraint := range []string{"", "~", "^x.y", "~1.4 <2"} {
+		_, err := parseSemverConstraint(constraint)
+		assert.Error(t, err, constraint)
+	}
+}
+
+func TestHighestSemverTag(t *testing.T) {
+	constraint, err := parseSemverConstraint("~1.4")
+	require.NoError(t, err)
+
+	tags := []string{"latest", "1.4", "1.4.2", "v1.4.10", "1.4.11-rc1", "1.4.3", "1.5.0"}
+	assert.Equal(t, "v1.4.10", highestSemverTag(tags, constraint))
+	assert.Equal(t, "", highestSemverTag([]string{"latest", "1.3.9", "1.5.0"}, constraint))
+}
+
+func TestImageDropInContent(t *testing.T) {
+	execStart := [][]interface{}{{
+		"/usr/bin/podman",
+		[]string{"/usr/bin/podman", "run", "--name", "app", "--label", "description=my app", "--env", "PCT=100%", "quay.io/example/app:1.4.0"},
+		false, uint64(0), uint64(0), uint64(0), uint64(0), uint32(0), int32(0), int32(0),
+	}}
+	content, err := imageDropInContent(execStart, "quay.io/example/app:1.4.0", "quay.io/example/app:1.4.2")
+	require.NoError(t, err)
+	expected := `# Written by podman auto-update to run the container on quay.io/example/app:1.4.2
+
+[Service]
+ExecStart=
+ExecStart=/usr/bin/podman run --name app --label "description=my app" --env PCT=100%% quay.io/example/app:1.4.2
+`
+	assert.Equal(t, expected, string(content))
+
+	execStart[0][1] = []string{"podman", "run", "quay.io/example/app:1.4.0"}
+	execStart[0][2] = true
+	content, err = imageDropInContent(execStart, "quay.io/example/app:1.4.0", "quay.io/example/app:1.4.2")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "\nExecStart=-@/usr/bin/podman podman run quay.io/example/app:1.4.2\n")
+
+	_, err = imageDropInContent(execStart, "quay.io/example/other:1.0.0", "quay.io/example/other:1.0.1")
+	assert.Error(t, err)
+	_, err = imageDropInContent([][]interface{}{}, "quay.io/example/app:1.4.0", "quay.io/example/app:1.4.2")
+	assert.Error(t, err)
+}
+
+func TestKubeYAMLWithImages(t *testing.T) {
+	kubeYAML := `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+  - name: app
+    image: quay.io/example/app:1.4.0
+  - name: sidecar
+    image: quay.io/example/sidecar:2.0.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: image
+data:
+  image: quay.io/example/app:1.4.0
+`
+	images := map[string]string{"quay.io/example/app:1.4.0": "quay.io/example/app:1.4.2"}
+	replaced, err := kubeYAMLWithImages(kubeYAML, images)
+	require.NoError(t, err)
+	// Only the image of the container is replaced.
+	expected := `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+    - name: app
+      image: quay.io/example/app:1.4.2
+    - name: sidecar
+      image: quay.io/example/sidecar:2.0.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: image
+data:
+  image: quay.io/example/app:1.4.0
+`
+	assert.Equal(t, expected, replaced)
+
+	unchanged, err := kubeYAMLWithImages(kubeYAML, nil)
+	require.NoError(t, err)
+	assert.Equal(t, kubeYAML, unchanged)
+}
@@ -14,15 +14,18 @@ func RunHealthCheck(ctx context.Context, nameOrID string, options *HealthCheckOp
 	if options == nil {
 		options = new(HealthCheckOptions)
 	}
-	_ = options
 	conn, err := bindings.GetClient(ctx)
 	if err != nil {
 		return nil, err
 	}
+	params, err := options.ToParams()
+	if err != nil {
+		return nil, err
+	}
 	var (
 		status define.HealthCheckResults
 	)
-	response, err := conn.DoRequest(ctx, nil, http.MethodGet, "/containers/%s/healthcheck", nil, nil, nameOrID)
+	response, err := conn.DoRequest(ctx, nil, http.MethodGet, "/containers/%s/healthcheck", params, nil, nameOrID)
 	if err != nil {
 		return nil, err
 	}
@@ -30,3 +33,27 @@ func RunHealthCheck(ctx context.Context, nameOrID string, options *HealthCheckOp
 
 	return &status, response.Process(&status)
 }
+
+// HealthCheckLog returns the results of the container's healthchecks recorded in
+// its healthcheck log.
+func HealthCheckLog(ctx context.Context, nameOrID string, options *HealthCheckLogOptions) ([]define.HealthCheckLog, error) {
+	if options == nil {
+		options = new(HealthCheckLogOptions)
+	}
+	conn, err := bindings.GetClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params, err := options.ToParams()
+	if err != nil {
+		return nil, err
+	}
+	var log []define.HealthCheckLog
+	response, err := conn.DoRequest(ctx, nil, http.MethodGet, "/containers/%s/healthcheck/log", params, nil, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	return log, response.Process(&log)
+}
@@ -13,6 +13,8 @@ import (
 //go:generate go run ../generator/generator.go LogOptions
 type LogOptions struct {
 	Follow     *bool
+	Format     *string
+	Grep       *string
 	Since      *string
 	Stderr     *bool
 	Stdout     *bool
@@ -119,7 +121,17 @@ type ExecStartOptions struct {
 // the health of a container
 //
 //go:generate go run ../generator/generator.go HealthCheckOptions
-type HealthCheckOptions struct{}
+type HealthCheckOptions struct {
+	Readiness *bool
+}
+
+// HealthCheckLogOptions are optional options for reading
+// the healthcheck log of a container
+//
+//go:generate go run ../generator/generator.go HealthCheckLogOptions
+type HealthCheckLogOptions struct {
+	Since *string
+}
 
 // MountOptions are optional options for mounting
 // containers
@@ -231,6 +243,7 @@ type UnpauseOptions struct{}
 type WaitOptions struct {
 	Condition []define.ContainerStatus
 	Interval  *string
+	Ready     *bool
 }
 
 // StopOptions are optional options for stopping containers
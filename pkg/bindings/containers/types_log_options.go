@@ -32,6 +32,36 @@ func (o *LogOptions) GetFollow() bool {
 	return *o.Follow
 }
 
+// WithFormat set field Format to given value
+func (o *LogOptions) WithFormat(value string) *LogOptions {
+	o.Format = &value
+	return o
+}
+
+// GetFormat returns value of field Format
+func (o *LogOptions) GetFormat() string {
+	if o.Format == nil {
+		var z string
+		return z
+	}
+	return *o.Format
+}
+
+// WithGrep set field Grep to given value
+func (o *LogOptions) WithGrep(value string) *LogOptions {
+	o.Grep = &value
+	return o
+}
+
+// GetGrep returns value of field Grep
+func (o *LogOptions) GetGrep() string {
+	if o.Grep == nil {
+		var z string
+		return z
+	}
+	return *o.Grep
+}
+
 // WithSince set field Since to given value
 func (o *LogOptions) WithSince(value string) *LogOptions {
 	o.Since = &value
@@ -47,3 +47,18 @@ func (o *WaitOptions) GetInterval() string {
 	}
 	return *o.Interval
 }
+
+// WithReady set field Ready to given value
+func (o *WaitOptions) WithReady(value bool) *WaitOptions {
+	o.Ready = &value
+	return o
+}
+
+// GetReady returns value of field Ready
+func (o *WaitOptions) GetReady() bool {
+	if o.Ready == nil {
+		var z bool
+		return z
+	}
+	return *o.Ready
+}
@@ -4,6 +4,7 @@ package system
 //
 //go:generate go run ../generator/generator.go EventsOptions
 type EventsOptions struct {
+	Count   *uint
 	Filters map[string][]string
 	Since   *string
 	Stream  *bool
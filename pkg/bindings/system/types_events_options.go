@@ -17,6 +17,21 @@ func (o *EventsOptions) ToParams() (url.Values, error) {
 	return util.ToParams(o)
 }
 
+// WithCount set field Count to given value
+func (o *EventsOptions) WithCount(value uint) *EventsOptions {
+	o.Count = &value
+	return o
+}
+
+// GetCount returns value of field Count
+func (o *EventsOptions) GetCount() uint {
+	if o.Count == nil {
+		var z uint
+		return z
+	}
+	return *o.Count
+}
+
 // WithFilters set field Filters to given value
 func (o *EventsOptions) WithFilters(value map[string][]string) *EventsOptions {
 	o.Filters = value
@@ -1,5 +1,11 @@
 package entities
 
+import (
+	"time"
+
+	"github.com/containers/podman/v4/libpod/define"
+)
+
 // AutoUpdateOptions are the options for running auto-update.
 type AutoUpdateOptions struct {
 	// Authfile to use when contacting registries.
@@ -11,6 +17,15 @@ type AutoUpdateOptions struct {
 	// If restarting the service with the new image failed, restart it
 	// another time with the previous image.
 	Rollback bool
+	// If set, wait up to HealthTimeout for updated containers with a
+	// healthcheck to turn healthy after restarting the service.  If a
+	// container does not turn healthy in time, the update is treated as
+	// failed.  Can be overridden per container via the
+	// io.containers.autoupdate.health-timeout label.
+	HealthTimeout time.Duration
+	// Protect the images used before an update from being pruned for the
+	// specified duration, such that the update can be rolled back.
+	ImageRetention time.Duration
 }
 
 // AutoUpdateReport contains the results from running auto-update.
@@ -26,6 +41,15 @@ type AutoUpdateReport struct {
 	// SystemdUnit running a container configured for auto updates.
 	SystemdUnit string
 	// Indicates the update status: true, false, failed, pending (see
-	// DryRun).
+	// DryRun), rolled back or rolled-back (unhealthy) (see HealthTimeout).
 	Updated string
+	// The image tag before the update (semver policy only).
+	OldTag string
+	// The image tag the container is updated to (semver policy only).
+	NewTag string
+}
+
+// AutoUpdateHistoryReport describes an update performed by auto-update.
+type AutoUpdateHistoryReport struct {
+	define.AutoUpdateRecord
 }
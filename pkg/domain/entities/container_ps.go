@@ -55,6 +55,9 @@ type ListContainer struct {
 	PodName string
 	// Port mappings
 	Ports []types.PortMapping
+	// Readiness is the status of the container's readiness check: "ready"
+	// or "not ready".  It is empty if the container has no readiness check.
+	Readiness string `json:",omitempty"`
 	// Restarts is how many times the container was restarted by its
 	// restart policy. This is NOT incremented by normal container restarts
 	// (only by restart policy).
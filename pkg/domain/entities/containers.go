@@ -54,6 +54,9 @@ type WaitOptions struct {
 	Interval  time.Duration
 	Ignore    bool
 	Latest    bool
+	// Ready waits for the container to pass its readiness check in
+	// addition to the conditions.
+	Ready bool
 }
 
 type WaitReport struct {
@@ -272,6 +275,12 @@ type ContainerLogsOptions struct {
 	Timestamps bool
 	// Show different colors in the logs.
 	Colors bool
+	// Only show the lines of this stream, stdout or stderr.
+	Stream string
+	// Only show the lines matching this regular expression.
+	Grep string
+	// Format of the output, "json" for one JSON object per line.
+	Format string
 	// Write the stdout to this Writer.
 	StdoutWriter io.Writer
 	// Write the stderr to this Writer.
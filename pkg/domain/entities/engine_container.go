@@ -15,6 +15,8 @@ type ContainerCopyFunc func() error
 
 type ContainerEngine interface { //nolint:interfacebloat
 	AutoUpdate(ctx context.Context, options AutoUpdateOptions) ([]*AutoUpdateReport, []error)
+	AutoUpdateHistory(ctx context.Context) ([]*AutoUpdateHistoryReport, error)
+	AutoUpdateRollback(ctx context.Context, nameOrUnit string) ([]*AutoUpdateReport, []error)
 	Config(ctx context.Context) (*config.Config, error)
 	ContainerAttach(ctx context.Context, nameOrID string, options AttachOptions) error
 	ContainerCheckpoint(ctx context.Context, namesOrIds []string, options CheckpointOptions) ([]*CheckpointReport, error)
@@ -58,7 +60,9 @@ type ContainerEngine interface { //nolint:interfacebloat
 	GenerateSpec(ctx context.Context, opts *GenerateSpecOptions) (*GenerateSpecReport, error)
 	GenerateSystemd(ctx context.Context, nameOrID string, opts GenerateSystemdOptions) (*GenerateSystemdReport, error)
 	GenerateKube(ctx context.Context, nameOrIDs []string, opts GenerateKubeOptions) (*GenerateKubeReport, error)
+	GenerateQuadlet(ctx context.Context, nameOrIDs []string, opts GenerateQuadletOptions) (*GenerateQuadletReport, error)
 	SystemPrune(ctx context.Context, options SystemPruneOptions) (*SystemPruneReport, error)
+	HealthCheckLog(ctx context.Context, nameOrID string, options HealthCheckLogOptions) ([]define.HealthCheckLog, error)
 	HealthCheckRun(ctx context.Context, nameOrID string, options HealthCheckOptions) (*define.HealthCheckResults, error)
 	Info(ctx context.Context) (*define.Info, error)
 	KubeApply(ctx context.Context, body io.Reader, opts ApplyOptions) error
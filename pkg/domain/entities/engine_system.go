@@ -8,7 +8,7 @@ import (
 
 type SystemEngine interface {
 	Renumber(ctx context.Context, flags *pflag.FlagSet, config *PodmanConfig) error
-	Migrate(ctx context.Context, flags *pflag.FlagSet, config *PodmanConfig, options SystemMigrateOptions) error
+	Migrate(ctx context.Context, flags *pflag.FlagSet, config *PodmanConfig, options SystemMigrateOptions) (*SystemMigrateReport, error)
 	Reset(ctx context.Context) error
 	Shutdown(ctx context.Context)
 }
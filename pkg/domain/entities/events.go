@@ -1,11 +1,35 @@
 package entities
 
 import (
+	"os"
 	"strconv"
 	"time"
 
 	libpodEvents "github.com/containers/podman/v4/libpod/events"
 	dockerEvents "github.com/docker/docker/api/types/events"
+	"github.com/opencontainers/go-digest"
+)
+
+const (
+	// EventsSchemaVersion is the version of the schema of the
+	// Docker-compatible events, which are also the data of CloudEvents.  It
+	// must be increased whenever a field is removed or changes its meaning.
+	EventsSchemaVersion = "1"
+	// MediaTypeDockerEvents is the media type of Docker-compatible events.
+	MediaTypeDockerEvents = "application/vnd.podman.docker-events.v" + EventsSchemaVersion + "+json"
+	// MediaTypeCloudEvents is the media type of events in the structured
+	// JSON format of CloudEvents.
+	MediaTypeCloudEvents = "application/cloudevents+json"
+
+	// CloudEventsSpecVersion is the version of the CloudEvents specification
+	// the CloudEvents conform to.
+	CloudEventsSpecVersion = "1.0"
+	// CloudEventsDataSchema identifies the schema of the data of CloudEvents.
+	CloudEventsDataSchema = "urn:podman:events:docker:v" + EventsSchemaVersion
+	// CloudEventsTypePrefix is the prefix of the type of CloudEvents which
+	// is followed by the type and status of the event, e.g.,
+	// io.podman.event.container.start.
+	CloudEventsTypePrefix = "io.podman.event."
 )
 
 // Event combines various event-related data such as time, event type, status
@@ -17,6 +41,20 @@ type Event struct {
 	HealthStatus string `json:",omitempty"`
 }
 
+// CloudEvent is an event in the structured JSON format of CloudEvents 1.0.
+// Its data is the Docker-compatible event.
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	DataSchema      string    `json:"dataschema"`
+	Data            *Event    `json:"data"`
+}
+
 // ConvertToLibpodEvent converts an entities event to a libpod one.
 func ConvertToLibpodEvent(e Event) *libpodEvents.Event {
 	exitCode, err := strconv.Atoi(e.Actor.Attributes["containerExitCode"])
@@ -35,9 +73,11 @@ func ConvertToLibpodEvent(e Event) *libpodEvents.Event {
 	name := e.Actor.Attributes["name"]
 	details := e.Actor.Attributes
 	podID := e.Actor.Attributes["podId"]
+	memoryLimit, _ := strconv.ParseUint(e.Actor.Attributes["memoryLimit"], 10, 64)
 	delete(details, "image")
 	delete(details, "name")
 	delete(details, "containerExitCode")
+	delete(details, "memoryLimit")
 	return &libpodEvents.Event{
 		ContainerExitCode: exitCode,
 		ID:                e.Actor.ID,
@@ -47,6 +87,7 @@ func ConvertToLibpodEvent(e Event) *libpodEvents.Event {
 		Time:              time.Unix(0, e.TimeNano),
 		Type:              t,
 		HealthStatus:      e.HealthStatus,
+		MemoryLimit:       memoryLimit,
 		Details: libpodEvents.Details{
 			PodID:      podID,
 			Attributes: details,
@@ -64,6 +105,9 @@ func ConvertToEntitiesEvent(e libpodEvents.Event) *Event {
 	attributes["name"] = e.Name
 	attributes["containerExitCode"] = strconv.Itoa(e.ContainerExitCode)
 	attributes["podId"] = e.PodID
+	if e.Status == libpodEvents.OOM {
+		attributes["memoryLimit"] = strconv.FormatUint(e.MemoryLimit, 10)
+	}
 	message := dockerEvents.Message{
 		// Compatibility with clients that still look for deprecated API elements
 		Status: e.Status.String(),
@@ -84,3 +128,53 @@ func ConvertToEntitiesEvent(e libpodEvents.Event) *Event {
 		e.HealthStatus,
 	}
 }
+
+// ConvertToDockerEvent converts a libpod event to an entities one with the
+// statuses and attributes expected by Docker clients.
+func ConvertToDockerEvent(e libpodEvents.Event) *Event {
+	event := ConvertToEntitiesEvent(e)
+	if event.Type == "image" && event.Status == "remove" {
+		event.Status = "delete"
+		event.Action = "delete"
+	}
+	if event.Status == "died" {
+		event.Status = "die"
+		event.Action = "die"
+		event.Actor.Attributes["exitCode"] = event.Actor.Attributes["containerExitCode"]
+	}
+	return event
+}
+
+// ConvertToCloudEvent converts a libpod event to a CloudEvent originating from
+// the specified source.  The ID of the CloudEvent is derived from the libpod
+// event, so reading the same event twice yields the same ID.
+func ConvertToCloudEvent(e libpodEvents.Event, source string) (*CloudEvent, error) {
+	eventJSON, err := e.ToJSONString()
+	if err != nil {
+		return nil, err
+	}
+	subject := e.Name
+	if subject == "" {
+		subject = e.ID
+	}
+	return &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              digest.FromString(eventJSON).Encoded(),
+		Source:          source,
+		Type:            CloudEventsTypePrefix + e.Type.String() + "." + e.Status.String(),
+		Subject:         subject,
+		Time:            e.Time,
+		DataContentType: "application/json",
+		DataSchema:      CloudEventsDataSchema,
+		Data:            ConvertToDockerEvent(e),
+	}, nil
+}
+
+// CloudEventsSource returns the source of the CloudEvents of this host.
+func CloudEventsSource() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "localhost"
+	}
+	return "podman://" + hostname
+}
@@ -27,6 +27,20 @@ type GenerateSystemdReport struct {
 	Units map[string]string
 }
 
+// GenerateQuadletOptions control the generation of Quadlet units.
+type GenerateQuadletOptions struct {
+	// NoHeader - skip the header comment of the units.
+	NoHeader bool
+}
+
+// GenerateQuadletReport
+type GenerateQuadletReport struct {
+	// Units of the generate process. key = unit file name -> value = unit content
+	Units map[string]string
+	// Unsupported - settings of the objects which the units cannot express.
+	Unsupported []string
+}
+
 // GenerateKubeOptions control the generation of Kubernetes YAML files.
 type GenerateKubeOptions struct {
 	// Service - generate YAML for a Kubernetes _service_ object.
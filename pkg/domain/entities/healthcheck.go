@@ -1,3 +1,14 @@
 package entities
 
-type HealthCheckOptions struct{}
+type HealthCheckOptions struct {
+	// Readiness runs the readiness check instead of the healthcheck.
+	Readiness bool
+}
+
+// HealthCheckLogOptions are the options for reading the healthcheck log of a
+// container.
+type HealthCheckLogOptions struct {
+	// Since only returns the results of healthchecks which started at or
+	// after the specified time.
+	Since string
+}
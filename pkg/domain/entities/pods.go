@@ -217,6 +217,15 @@ type ContainerCreateOptions struct {
 	HealthStartPeriod  string
 	HealthTimeout      string
 	HealthOnFailure    string
+	HealthHTTP         string
+	HealthHTTPHeader   []string
+	HealthHTTPStatus   int
+	HealthTCP          string
+	HealthGRPC         string
+	HealthGRPCService  string
+	HealthLogDest      string
+	HealthMaxLogCount  uint
+	HealthMaxLogSize   uint
 	Hostname           string `json:"hostname,omitempty"`
 	HTTPProxy          bool
 	HostUsers          []string
@@ -254,6 +263,12 @@ type ContainerCreateOptions struct {
 	Quiet              bool
 	ReadOnly           bool
 	ReadWriteTmpFS     bool
+	ReadyCmd           string
+	ReadyInterval      string
+	ReadyRetries       uint
+	ReadyStartPeriod   string
+	ReadySuccesses     uint
+	ReadyTimeout       string
 	Restart            string
 	Replace            bool
 	Requires           []string
@@ -38,6 +38,16 @@ type SystemPruneReport struct {
 // cli to migrate runtimes of containers
 type SystemMigrateOptions struct {
 	NewRuntime string
+	MigrateDB  bool
+	RollbackDB bool
+	DryRun     bool
+}
+
+// SystemMigrateReport describes the response for migrating
+type SystemMigrateReport struct {
+	// DBMigration summarizes the migration of the database backend, if
+	// one was requested.
+	DBMigration string
 }
 
 // SystemDfOptions describes the options for getting df information
@@ -95,6 +95,7 @@ type EventsOptions struct {
 	Stream    bool
 	Since     string
 	Until     string
+	Count     uint
 }
 
 // ContainerCreateResponse is the response struct for creating a container
@@ -8,5 +8,13 @@ import (
 )
 
 func (ic *ContainerEngine) AutoUpdate(ctx context.Context, options entities.AutoUpdateOptions) ([]*entities.AutoUpdateReport, []error) {
-	return autoupdate.AutoUpdate(ctx, ic.Libpod, options)
+	return autoupdate.AutoUpdate(ctx, ic.Libpod, ic, options)
+}
+
+func (ic *ContainerEngine) AutoUpdateHistory(ctx context.Context) ([]*entities.AutoUpdateHistoryReport, error) {
+	return autoupdate.History(ic.Libpod)
+}
+
+func (ic *ContainerEngine) AutoUpdateRollback(ctx context.Context, nameOrUnit string) ([]*entities.AutoUpdateReport, []error) {
+	return autoupdate.Rollback(ctx, ic.Libpod, ic, nameOrUnit)
 }
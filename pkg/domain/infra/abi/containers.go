@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"sync"
 	"time"
@@ -185,10 +186,10 @@ func (ic *ContainerEngine) ContainerWait(ctx context.Context, namesOrIds []strin
 		}
 
 		response := entities.WaitReport{}
-		if options.Condition == nil {
+		if options.Condition == nil && !options.Ready {
 			options.Condition = []define.ContainerStatus{define.ContainerStateStopped, define.ContainerStateExited}
 		}
-		exitCode, err := c.WaitForConditionWithInterval(ctx, options.Interval, options.Condition...)
+		exitCode, err := c.WaitForReadyOrConditionWithInterval(ctx, options.Interval, options.Ready, options.Condition...)
 		if err != nil {
 			response.Error = err
 		} else {
@@ -1213,6 +1214,17 @@ func (ic *ContainerEngine) ContainerLogs(ctx context.Context, namesOrIds []strin
 		return err
 	}
 
+	if err := logs.ValidateStream(options.Stream); err != nil {
+		return err
+	}
+	var grep *regexp.Regexp
+	if options.Grep != "" {
+		grep, err = regexp.Compile(options.Grep)
+		if err != nil {
+			return fmt.Errorf("invalid grep pattern %q: %w", options.Grep, err)
+		}
+	}
+
 	logOpts := &logs.LogOptions{
 		Multi:      len(containers) > 1,
 		Details:    options.Details,
@@ -1224,6 +1236,8 @@ func (ic *ContainerEngine) ContainerLogs(ctx context.Context, namesOrIds []strin
 		Colors:     options.Colors,
 		UseName:    options.Names,
 		WaitGroup:  &wg,
+		Stream:     options.Stream,
+		Grep:       grep,
 	}
 
 	chSize := len(containers) * int(options.Tail)
@@ -1247,12 +1261,31 @@ func (ic *ContainerEngine) ContainerLogs(ctx context.Context, namesOrIds []strin
 	}()
 
 	for line := range logChannel {
+		if options.Format == "json" {
+			writeJSONLogLine(line, options)
+			continue
+		}
 		line.Write(options.StdoutWriter, options.StderrWriter, logOpts)
 	}
 
 	return nil
 }
 
+// writeJSONLogLine writes the log line as JSON object to stdout or, if no
+// stdout writer is set, to stderr.
+func writeJSONLogLine(line *logs.LogLine, options entities.ContainerLogsOptions) {
+	out := options.StdoutWriter
+	if out == nil {
+		out = options.StderrWriter
+	}
+	jsonLine, err := line.ToJSONString()
+	if err != nil {
+		logrus.Errorf("Encoding log line of container %s: %v", line.CID, err)
+		return
+	}
+	fmt.Fprintln(out, jsonLine)
+}
+
 func (ic *ContainerEngine) ContainerCleanup(ctx context.Context, namesOrIds []string, options entities.ContainerCleanupOptions) ([]*entities.ContainerCleanupReport, error) {
 	containers, err := getContainers(ic.Libpod, getContainersOptions{all: options.All, latest: options.Latest, names: namesOrIds})
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -267,6 +268,53 @@ func (ic *ContainerEngine) GenerateKube(ctx context.Context, nameOrIDs []string,
 	return &entities.GenerateKubeReport{Reader: bytes.NewReader(k)}, nil
 }
 
+// GenerateQuadlet generates Quadlet units for the specified containers, pods,
+// volumes or networks.
+func (ic *ContainerEngine) GenerateQuadlet(ctx context.Context, nameOrIDs []string, options entities.GenerateQuadletOptions) (*entities.GenerateQuadletReport, error) {
+	report := &entities.GenerateQuadletReport{Units: make(map[string]string)}
+
+	for _, nameOrID := range nameOrIDs {
+		var (
+			units       map[string]string
+			unsupported []string
+			err         error
+		)
+
+		// Let's assume it's a container, then a pod, a volume and
+		// finally a network.
+		if ctr, ctrErr := ic.Libpod.LookupContainer(nameOrID); ctrErr == nil {
+			units, unsupported, err = generate.ContainerQuadlet(ctx, ctr, options)
+		} else if !errors.Is(ctrErr, define.ErrNoSuchCtr) {
+			return nil, ctrErr
+		} else if pod, podErr := ic.Libpod.LookupPod(nameOrID); podErr == nil {
+			units, unsupported, err = generate.PodQuadlets(ctx, pod, options)
+		} else if !errors.Is(podErr, define.ErrNoSuchPod) {
+			return nil, podErr
+		} else if vol, volErr := ic.Libpod.LookupVolume(nameOrID); volErr == nil {
+			units, unsupported, err = generate.VolumeQuadlet(vol, options)
+		} else if !errors.Is(volErr, define.ErrNoSuchVolume) {
+			return nil, volErr
+		} else if network, netErr := ic.Libpod.Network().NetworkInspect(nameOrID); netErr == nil {
+			units, unsupported, err = generate.NetworkQuadlet(&network, options)
+		} else if !errors.Is(netErr, define.ErrNoSuchNetwork) {
+			return nil, netErr
+		} else {
+			// If it reaches here is because the name or id did not exist.
+			return nil, fmt.Errorf("name or ID %q not found", nameOrID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for name, content := range units {
+			report.Units[name] = content
+		}
+		report.Unsupported = append(report.Unsupported, unsupported...)
+	}
+
+	return report, nil
+}
+
 // getKubePods returns kube pod or deployment and service YAML files from podman pods.
 func getKubePods(ctx context.Context, pods []*libpod.Pod, options entities.GenerateKubeOptions) ([][]byte, [][]byte, error) {
 	out := [][]byte{}
@@ -2,12 +2,28 @@ package abi
 
 import (
 	"context"
+	"time"
 
 	"github.com/containers/podman/v4/libpod/define"
 	"github.com/containers/podman/v4/pkg/domain/entities"
+	"github.com/containers/podman/v4/pkg/util"
 )
 
 func (ic *ContainerEngine) HealthCheckRun(ctx context.Context, nameOrID string, options entities.HealthCheckOptions) (*define.HealthCheckResults, error) {
+	if options.Readiness {
+		status, err := ic.Libpod.ReadinessCheck(ctx, nameOrID)
+		if err != nil {
+			return nil, err
+		}
+		report := define.HealthCheckResults{
+			Status: define.ReadinessCheckNotReady,
+		}
+		if status == define.HealthCheckSuccess {
+			report.Status = define.ReadinessCheckReady
+		}
+		return &report, nil
+	}
+
 	status, err := ic.Libpod.HealthCheck(ctx, nameOrID)
 	if err != nil {
 		return nil, err
@@ -23,3 +39,19 @@ func (ic *ContainerEngine) HealthCheckRun(ctx context.Context, nameOrID string,
 	}
 	return &report, nil
 }
+
+func (ic *ContainerEngine) HealthCheckLog(ctx context.Context, nameOrID string, options entities.HealthCheckLogOptions) ([]define.HealthCheckLog, error) {
+	var since time.Time
+	if options.Since != "" {
+		var err error
+		since, err = util.ParseInputTime(options.Since, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ctr, err := ic.Libpod.LookupContainer(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	return ctr.HealthCheckLog(ctx, since)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/containers/image/v5/transports"
 	"github.com/containers/image/v5/transports/alltransports"
 	"github.com/containers/podman/v4/libpod/define"
+	"github.com/containers/podman/v4/pkg/autoupdate"
 	"github.com/containers/podman/v4/pkg/domain/entities"
 	"github.com/containers/podman/v4/pkg/domain/entities/reports"
 	domainUtils "github.com/containers/podman/v4/pkg/domain/utils"
@@ -63,12 +64,18 @@ func (ir *ImageEngine) Prune(ctx context.Context, opts entities.ImagePruneOption
 		pruneOptions.Filters = append(pruneOptions.Filters, "containers=false")
 	}
 
+	// Images needed to roll back auto updates are protected from pruning.
+	protectedImages, err := autoupdate.ProtectedImages(ir.Libpod)
+	if err != nil {
+		return nil, err
+	}
+
 	pruneReports := make([]*reports.PruneReport, 0)
 
 	// Now prune all images until we converge.
 	numPreviouslyRemovedImages := 1
 	for {
-		removedImages, rmErrors := ir.Libpod.LibimageRuntime().RemoveImages(ctx, nil, pruneOptions)
+		removedImages, rmErrors := ir.pruneImages(ctx, pruneOptions, protectedImages)
 		if rmErrors != nil {
 			return nil, errorhandling.JoinErrors(rmErrors)
 		}
@@ -91,6 +98,41 @@ func (ir *ImageEngine) Prune(ctx context.Context, opts entities.ImagePruneOption
 	return pruneReports, nil
 }
 
+// pruneImages removes the images matching the filters of the specified options
+// except for the protected ones.
+func (ir *ImageEngine) pruneImages(ctx context.Context, options *libimage.RemoveImagesOptions, protected map[string]bool) ([]*libimage.RemoveImageReport, []error) {
+	if len(protected) == 0 {
+		return ir.Libpod.LibimageRuntime().RemoveImages(ctx, nil, options)
+	}
+
+	listOptions := &libimage.ListImagesOptions{
+		Filters:                 options.Filters,
+		IsExternalContainerFunc: options.IsExternalContainerFunc,
+	}
+	images, err := ir.Libpod.LibimageRuntime().ListImages(ctx, nil, listOptions)
+	if err != nil {
+		return nil, []error{err}
+	}
+	// Select the images to remove via ID filters rather than removing
+	// them by ID, which fails for images with more than one tag.
+	var idFilters []string
+	for _, image := range images {
+		if !protected[image.ID()] {
+			idFilters = append(idFilters, "id="+image.ID())
+		}
+	}
+	if len(idFilters) == 0 {
+		return nil, nil
+	}
+
+	// Do not remove dangling parents which may be protected.  They are
+	// considered in the next iteration of pruning.
+	rmOptions := *options
+	rmOptions.Filters = append(append([]string{}, options.Filters...), idFilters...)
+	rmOptions.NoPrune = true
+	return ir.Libpod.LibimageRuntime().RemoveImages(ctx, nil, &rmOptions)
+}
+
 func toDomainHistoryLayer(layer *libimage.ImageHistory) entities.ImageHistoryLayer {
 	l := entities.ImageHistoryLayer{
 		Comment:   layer.Comment,
@@ -3,6 +3,7 @@ package abi
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -165,6 +166,7 @@ func (ic *ContainerEngine) PlayKube(ctx context.Context, body io.Reader, options
 	ipIndex := 0
 
 	var configMaps []v1.ConfigMap
+	var configMapDocuments [][]byte
 
 	ranContainers := false
 	// FIXME: both, the service container and the proxies, should ideally
@@ -240,7 +242,7 @@ func (ic *ContainerEngine) PlayKube(ctx context.Context, body io.Reader, options
 				podYAML.Annotations[name] = val
 			}
 
-			r, proxies, err := ic.playKubePod(ctx, podTemplateSpec.ObjectMeta.Name, &podTemplateSpec, options, &ipIndex, podYAML.Annotations, configMaps, serviceContainer)
+			r, proxies, err := ic.playKubePod(ctx, podTemplateSpec.ObjectMeta.Name, &podTemplateSpec, options, &ipIndex, podYAML.Annotations, configMaps, serviceContainer, replayYAML(document, configMapDocuments))
 			if err != nil {
 				return nil, err
 			}
@@ -256,7 +258,7 @@ func (ic *ContainerEngine) PlayKube(ctx context.Context, body io.Reader, options
 				return nil, fmt.Errorf("unable to read YAML as Kube Deployment: %w", err)
 			}
 
-			r, proxies, err := ic.playKubeDeployment(ctx, &deploymentYAML, options, &ipIndex, configMaps, serviceContainer)
+			r, proxies, err := ic.playKubeDeployment(ctx, &deploymentYAML, options, &ipIndex, configMaps, serviceContainer, replayYAML(document, configMapDocuments))
 			if err != nil {
 				return nil, err
 			}
@@ -299,6 +301,7 @@ func (ic *ContainerEngine) PlayKube(ctx context.Context, body io.Reader, options
 				return nil, fmt.Errorf("unable to read YAML as Kube ConfigMap: %w", err)
 			}
 			configMaps = append(configMaps, configMap)
+			configMapDocuments = append(configMapDocuments, document)
 		case "Secret":
 			var secret v1.Secret
 
@@ -366,7 +369,7 @@ func (ic *ContainerEngine) PlayKube(ctx context.Context, body io.Reader, options
 	return report, nil
 }
 
-func (ic *ContainerEngine) playKubeDeployment(ctx context.Context, deploymentYAML *v1apps.Deployment, options entities.PlayKubeOptions, ipIndex *int, configMaps []v1.ConfigMap, serviceContainer *libpod.Container) (*entities.PlayKubeReport, []*notifyproxy.NotifyProxy, error) {
+func (ic *ContainerEngine) playKubeDeployment(ctx context.Context, deploymentYAML *v1apps.Deployment, options entities.PlayKubeOptions, ipIndex *int, configMaps []v1.ConfigMap, serviceContainer *libpod.Container, kubeYAML []byte) (*entities.PlayKubeReport, []*notifyproxy.NotifyProxy, error) {
 	var (
 		deploymentName string
 		podSpec        v1.PodTemplateSpec
@@ -388,7 +391,7 @@ func (ic *ContainerEngine) playKubeDeployment(ctx context.Context, deploymentYAM
 	podSpec = deploymentYAML.Spec.Template
 
 	podName := fmt.Sprintf("%s-pod", deploymentName)
-	podReport, proxies, err := ic.playKubePod(ctx, podName, &podSpec, options, ipIndex, deploymentYAML.Annotations, configMaps, serviceContainer)
+	podReport, proxies, err := ic.playKubePod(ctx, podName, &podSpec, options, ipIndex, deploymentYAML.Annotations, configMaps, serviceContainer, kubeYAML)
 	if err != nil {
 		return nil, nil, fmt.Errorf("encountered while bringing up pod %s: %w", podName, err)
 	}
@@ -397,7 +400,7 @@ func (ic *ContainerEngine) playKubeDeployment(ctx context.Context, deploymentYAM
 	return &report, proxies, nil
 }
 
-func (ic *ContainerEngine) playKubePod(ctx context.Context, podName string, podYAML *v1.PodTemplateSpec, options entities.PlayKubeOptions, ipIndex *int, annotations map[string]string, configMaps []v1.ConfigMap, serviceContainer *libpod.Container) (*entities.PlayKubeReport, []*notifyproxy.NotifyProxy, error) {
+func (ic *ContainerEngine) playKubePod(ctx context.Context, podName string, podYAML *v1.PodTemplateSpec, options entities.PlayKubeOptions, ipIndex *int, annotations map[string]string, configMaps []v1.ConfigMap, serviceContainer *libpod.Container, kubeYAML []byte) (*entities.PlayKubeReport, []*notifyproxy.NotifyProxy, error) {
 	var (
 		writer      io.Writer
 		playKubePod entities.PlayKubePod
@@ -649,6 +652,16 @@ func (ic *ContainerEngine) playKubePod(ctx context.Context, podName string, podY
 		podSpec.PodSpecGen.ServiceContainerID = serviceContainer.ID()
 	}
 
+	// Store the YAML and options to allow for replaying them on auto
+	// updates
+	if hasAutoUpdateAnnotation(annotations) {
+		podSpec.PodSpecGen.KubeYAML = string(kubeYAML)
+		podSpec.PodSpecGen.KubePlayOptions, err = replayOptions(options)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Create the Pod
 	pod, err := generate.MakePod(&podSpec, ic.Libpod)
 	if err != nil {
@@ -1304,6 +1317,42 @@ func getBuildFile(imageName string, cwd string) (string, error) {
 	return "", err
 }
 
+// replayYAML returns the Kubernetes YAML to store in a pod created from the
+// specified document of a Pod or Deployment to replay it on auto updates: the
+// document along with the ConfigMaps it may refer to.  Secrets are not
+// stored, as they are created as Podman secrets which outlive a replay.
+func replayYAML(document []byte, configMaps [][]byte) []byte {
+	return bytes.Join(append(append([][]byte{}, configMaps...), document), []byte("---\n"))
+}
+
+// hasAutoUpdateAnnotation returns whether the annotations of a pod enable auto
+// updates for any of its containers.
+func hasAutoUpdateAnnotation(annotations map[string]string) bool {
+	for k, v := range annotations {
+		if v != "" && (k == define.AutoUpdateLabel || strings.HasPrefix(k, define.AutoUpdateLabel+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// replayOptions returns the JSON-encoded options to store along with the YAML
+// of a pod, such that the YAML can be replayed with the same options.  The
+// credentials are not stored and the options controlling the current play
+// (i.e., --down, --replace and --wait) are reset.
+func replayOptions(options entities.PlayKubeOptions) (string, error) {
+	options.Username = ""
+	options.Password = ""
+	options.Down = false
+	options.Replace = false
+	options.Wait = false
+	b, err := json.Marshal(options)
+	if err != nil {
+		return "", fmt.Errorf("encoding options of the Kubernetes YAML: %w", err)
+	}
+	return string(b), nil
+}
+
 func (ic *ContainerEngine) PlayKubeDown(ctx context.Context, body io.Reader, options entities.PlayKubeDownOptions) (*entities.PlayKubeReport, error) {
 	var (
 		podNames    []string
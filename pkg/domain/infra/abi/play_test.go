@@ -262,3 +262,37 @@ kind: Pod
 		})
 	}
 }
+
+func TestReplayYAML(t *testing.T) {
+	pod := []byte("apiVersion: v1\nkind: Pod\n")
+	configMap := []byte("apiVersion: v1\nkind: ConfigMap\n")
+
+	assert.Equal(t, string(pod), string(replayYAML(pod, nil)))
+
+	replay := replayYAML(pod, [][]byte{configMap})
+	docs, err := splitMultiDocYAML(replay)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{configMap, pod}, docs)
+}
+
+func TestHasAutoUpdateAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{"NoAnnotations", nil, false},
+		{"OtherAnnotation", map[string]string{"foo": "bar"}, false},
+		{"PodAnnotation", map[string]string{"io.containers.autoupdate": "registry"}, true},
+		{"ContainerAnnotation", map[string]string{"io.containers.autoupdate/ctr": "local"}, true},
+		{"EmptyAnnotation", map[string]string{"io.containers.autoupdate": ""}, false},
+		{"AuthfileAnnotation", map[string]string{"io.containers.autoupdate.authfile": "/auth.json"}, false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, hasAutoUpdateAnnotation(test.annotations))
+		})
+	}
+}
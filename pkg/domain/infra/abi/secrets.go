@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/containers/common/pkg/secrets"
+	"github.com/containers/podman/v4/libpod/events"
 	"github.com/containers/podman/v4/pkg/domain/entities"
 	"github.com/containers/podman/v4/pkg/domain/utils"
 )
@@ -52,6 +53,7 @@ func (ic *ContainerEngine) SecretCreate(ctx context.Context, name string, reader
 	if err != nil {
 		return nil, err
 	}
+	ic.Libpod.NewSecretEvent(events.Create, secretID, name, nil)
 
 	return &entities.SecretCreateReport{
 		ID: secretID,
@@ -152,7 +154,14 @@ func (ic *ContainerEngine) SecretRm(ctx context.Context, nameOrIDs []string, opt
 		}
 	}
 	for _, nameOrID := range toRemove {
+		var name string
+		if secret, err := manager.Lookup(nameOrID); err == nil {
+			name = secret.Name
+		}
 		deletedID, err := manager.Delete(nameOrID)
+		if err == nil {
+			ic.Libpod.NewSecretEvent(events.Remove, deletedID, name, nil)
+		}
 		if err == nil || strings.Contains(err.Error(), "no such secret") {
 			reports = append(reports, &entities.SecretRmReport{
 				Err: err,
@@ -378,8 +378,13 @@ func (se *SystemEngine) Renumber(ctx context.Context, flags *pflag.FlagSet, conf
 	return nil
 }
 
-func (se SystemEngine) Migrate(ctx context.Context, flags *pflag.FlagSet, config *entities.PodmanConfig, options entities.SystemMigrateOptions) error {
-	return nil
+func (se SystemEngine) Migrate(ctx context.Context, flags *pflag.FlagSet, config *entities.PodmanConfig, options entities.SystemMigrateOptions) (*entities.SystemMigrateReport, error) {
+	report := new(entities.SystemMigrateReport)
+	// The migration is done while creating the runtime.
+	if dbReport := se.Libpod.DBMigrationReport(); dbReport != nil {
+		report.DBMigration = dbReport.String()
+	}
+	return report, nil
 }
 
 func (se SystemEngine) Shutdown(ctx context.Context) {
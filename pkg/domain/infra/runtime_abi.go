@@ -55,11 +55,21 @@ func NewSystemEngine(setup entities.EngineSetup, facts *entities.PodmanConfig) (
 		case entities.ResetMode:
 			r, err = GetRuntimeReset(context.Background(), facts.FlagSet, facts)
 		case entities.MigrateMode:
-			name, flagErr := facts.FlagSet.GetString("new-runtime")
-			if flagErr != nil {
+			options := entities.SystemMigrateOptions{}
+			var flagErr error
+			if options.NewRuntime, flagErr = facts.FlagSet.GetString("new-runtime"); flagErr != nil {
 				return nil, flagErr
 			}
-			r, err = GetRuntimeMigrate(context.Background(), facts.FlagSet, facts, name)
+			if options.MigrateDB, flagErr = facts.FlagSet.GetBool("migrate-db"); flagErr != nil {
+				return nil, flagErr
+			}
+			if options.RollbackDB, flagErr = facts.FlagSet.GetBool("rollback-db"); flagErr != nil {
+				return nil, flagErr
+			}
+			if options.DryRun, flagErr = facts.FlagSet.GetBool("dry-run"); flagErr != nil {
+				return nil, flagErr
+			}
+			r, err = GetRuntimeMigrate(context.Background(), facts.FlagSet, facts, options)
 		case entities.NoFDsMode:
 			r, err = GetRuntimeDisableFDs(context.Background(), facts.FlagSet, facts)
 		}
@@ -32,25 +32,31 @@ var (
 )
 
 type engineOpts struct {
-	name     string
-	renumber bool
-	migrate  bool
-	noStore  bool
-	withFDS  bool
-	reset    bool
-	config   *entities.PodmanConfig
+	name          string
+	renumber      bool
+	migrate       bool
+	migrateDB     bool
+	rollbackDB    bool
+	migrateDryRun bool
+	noStore       bool
+	withFDS       bool
+	reset         bool
+	config        *entities.PodmanConfig
 }
 
 // GetRuntimeMigrate gets a libpod runtime that will perform a migration of existing containers
-func GetRuntimeMigrate(ctx context.Context, fs *flag.FlagSet, cfg *entities.PodmanConfig, newRuntime string) (*libpod.Runtime, error) {
+func GetRuntimeMigrate(ctx context.Context, fs *flag.FlagSet, cfg *entities.PodmanConfig, options entities.SystemMigrateOptions) (*libpod.Runtime, error) {
 	return getRuntime(ctx, fs, &engineOpts{
-		name:     newRuntime,
-		renumber: false,
-		migrate:  true,
-		noStore:  false,
-		withFDS:  true,
-		reset:    false,
-		config:   cfg,
+		name:          options.NewRuntime,
+		renumber:      false,
+		migrate:       true,
+		migrateDB:     options.MigrateDB,
+		rollbackDB:    options.RollbackDB,
+		migrateDryRun: options.DryRun,
+		noStore:       false,
+		withFDS:       true,
+		reset:         false,
+		config:        cfg,
 	})
 }
 
@@ -179,6 +185,15 @@ func getRuntime(ctx context.Context, fs *flag.FlagSet, opts *engineOpts) (*libpo
 		if opts.name != "" {
 			options = append(options, libpod.WithMigrateRuntime(opts.name))
 		}
+		if opts.migrateDB {
+			options = append(options, libpod.WithMigrateDB())
+		}
+		if opts.rollbackDB {
+			options = append(options, libpod.WithRollbackDB())
+		}
+		if opts.migrateDryRun {
+			options = append(options, libpod.WithMigrateDryRun())
+		}
 	}
 
 	if opts.reset {
@@ -10,3 +10,11 @@ import (
 func (ic *ContainerEngine) AutoUpdate(ctx context.Context, options entities.AutoUpdateOptions) ([]*entities.AutoUpdateReport, []error) {
 	return nil, []error{errors.New("not implemented")}
 }
+
+func (ic *ContainerEngine) AutoUpdateHistory(ctx context.Context) ([]*entities.AutoUpdateHistoryReport, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (ic *ContainerEngine) AutoUpdateRollback(ctx context.Context, nameOrUnit string) ([]*entities.AutoUpdateReport, []error) {
+	return nil, []error{errors.New("not implemented")}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/containers/image/v5/docker/reference"
 	"github.com/containers/podman/v4/libpod/define"
 	"github.com/containers/podman/v4/libpod/events"
+	"github.com/containers/podman/v4/libpod/logs"
 	"github.com/containers/podman/v4/pkg/api/handlers"
 	"github.com/containers/podman/v4/pkg/bindings/containers"
 	"github.com/containers/podman/v4/pkg/bindings/images"
@@ -40,6 +41,9 @@ func (ic *ContainerEngine) ContainerExists(ctx context.Context, nameOrID string,
 func (ic *ContainerEngine) ContainerWait(ctx context.Context, namesOrIds []string, opts entities.WaitOptions) ([]entities.WaitReport, error) {
 	responses := make([]entities.WaitReport, 0, len(namesOrIds))
 	options := new(containers.WaitOptions).WithCondition(opts.Condition).WithInterval(opts.Interval.String())
+	if opts.Ready {
+		options.WithReady(true)
+	}
 	for _, n := range namesOrIds {
 		response := entities.WaitReport{}
 		exitCode, err := containers.Wait(ic.ClientCtx, n, options)
@@ -506,10 +510,16 @@ func (ic *ContainerEngine) ContainerLogs(_ context.Context, nameOrIDs []string,
 	since := opts.Since.Format(time.RFC3339)
 	until := opts.Until.Format(time.RFC3339)
 	tail := strconv.FormatInt(opts.Tail, 10)
-	stdout := opts.StdoutWriter != nil
-	stderr := opts.StderrWriter != nil
+	stdout := opts.StdoutWriter != nil && opts.Stream != logs.StderrStream
+	stderr := opts.StderrWriter != nil && opts.Stream != logs.StdoutStream
 	options := new(containers.LogOptions).WithFollow(opts.Follow).WithSince(since).WithUntil(until).WithStderr(stderr)
 	options.WithStdout(stdout).WithTail(tail).WithTimestamps(opts.Timestamps)
+	if opts.Grep != "" {
+		options.WithGrep(opts.Grep)
+	}
+	if opts.Format != "" {
+		options.WithFormat(opts.Format)
+	}
 
 	var err error
 	stdoutCh := make(chan string)
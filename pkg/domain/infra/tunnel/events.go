@@ -29,6 +29,9 @@ func (ic *ContainerEngine) Events(ctx context.Context, opts entities.EventsOptio
 		close(opts.EventChan)
 	}()
 	options := new(system.EventsOptions).WithFilters(filters).WithSince(opts.Since).WithStream(opts.Stream).WithUntil(opts.Until)
+	if opts.Count > 0 {
+		options.WithCount(opts.Count)
+	}
 	return system.Events(ic.ClientCtx, binChan, nil, options)
 }
 
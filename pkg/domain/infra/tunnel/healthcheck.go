@@ -9,5 +9,13 @@ import (
 )
 
 func (ic *ContainerEngine) HealthCheckRun(ctx context.Context, nameOrID string, options entities.HealthCheckOptions) (*define.HealthCheckResults, error) {
-	return containers.RunHealthCheck(ic.ClientCtx, nameOrID, nil)
+	return containers.RunHealthCheck(ic.ClientCtx, nameOrID, new(containers.HealthCheckOptions).WithReadiness(options.Readiness))
+}
+
+func (ic *ContainerEngine) HealthCheckLog(ctx context.Context, nameOrID string, options entities.HealthCheckLogOptions) ([]define.HealthCheckLog, error) {
+	logOptions := new(containers.HealthCheckLogOptions)
+	if options.Since != "" {
+		logOptions.WithSince(options.Since)
+	}
+	return containers.HealthCheckLog(ic.ClientCtx, nameOrID, logOptions)
 }
@@ -54,6 +54,10 @@ func (ic *ContainerEngine) GenerateSpec(ctx context.Context, opts *entities.Gene
 	return nil, fmt.Errorf("GenerateSpec is not supported on the remote API")
 }
 
+func (ic *ContainerEngine) GenerateQuadlet(ctx context.Context, nameOrIDs []string, opts entities.GenerateQuadletOptions) (*entities.GenerateQuadletReport, error) {
+	return nil, fmt.Errorf("GenerateQuadlet is not supported on the remote API")
+}
+
 func (ic *ContainerEngine) PlayKube(ctx context.Context, body io.Reader, opts entities.PlayKubeOptions) (*entities.PlayKubeReport, error) {
 	options := new(kube.PlayOptions).WithAuthfile(opts.Authfile).WithUsername(opts.Username).WithPassword(opts.Password)
 	options.WithCertDir(opts.CertDir).WithQuiet(opts.Quiet).WithSignaturePolicy(opts.SignaturePolicy).WithConfigMaps(opts.ConfigMaps)
@@ -980,6 +980,18 @@ type TCPSocketAction struct {
 	Host string `json:"host,omitempty"`
 }
 
+// GRPCAction describes an action involving a gRPC port.
+type GRPCAction struct {
+	// Port number of the gRPC service. Number must be in the range 1 to 65535.
+	Port int32 `json:"port"`
+	// Service is the name of the service to place in the gRPC HealthCheckRequest
+	// (see https://github.com/grpc/grpc/blob/master/doc/health-checking.md).
+	//
+	// If this is not specified, the default behavior is defined by gRPC.
+	// +optional
+	Service *string `json:"service"`
+}
+
 // ExecAction describes a "run in container" action.
 type ExecAction struct {
 	// Command is the command line to execute inside the container, the working directory for the
@@ -1274,6 +1286,9 @@ type Handler struct {
 	// TODO: implement a realistic TCP lifecycle hook
 	// +optional
 	TCPSocket *TCPSocketAction `json:"tcpSocket,omitempty"`
+	// GRPC specifies an action involving a gRPC port.
+	// +optional
+	GRPC *GRPCAction `json:"grpc,omitempty"`
 }
 
 // Lifecycle describes actions that the management system should take in response to container lifecycle
@@ -506,6 +506,9 @@ func createContainerOptions(rt *libpod.Runtime, s *specgen.SpecGenerator, pod *l
 		if len(s.LogConfiguration.Options) > 0 && s.LogConfiguration.Options["tag"] != "" {
 			options = append(options, libpod.WithLogTag(s.LogConfiguration.Options["tag"]))
 		}
+		if len(s.LogConfiguration.Options) > 0 {
+			options = append(options, libpod.WithLogOptions(s.LogConfiguration.Options))
+		}
 
 		if len(s.LogConfiguration.Driver) > 0 {
 			options = append(options, libpod.WithLogDriver(s.LogConfiguration.Driver))
@@ -581,6 +584,18 @@ func createContainerOptions(rt *libpod.Runtime, s *specgen.SpecGenerator, pod *l
 	if s.ContainerHealthCheckConfig.StartupHealthConfig != nil {
 		options = append(options, libpod.WithStartupHealthcheck(s.ContainerHealthCheckConfig.StartupHealthConfig))
 	}
+	if s.ContainerHealthCheckConfig.ReadinessConfig != nil {
+		options = append(options, libpod.WithReadinessCheck(s.ContainerHealthCheckConfig.ReadinessConfig))
+	}
+	if s.ContainerHealthCheckConfig.HealthLogDestination != "" {
+		options = append(options, libpod.WithHealthLogDestination(s.ContainerHealthCheckConfig.HealthLogDestination))
+	}
+	if s.ContainerHealthCheckConfig.HealthMaxLogCount != nil {
+		options = append(options, libpod.WithHealthMaxLogCount(*s.ContainerHealthCheckConfig.HealthMaxLogCount))
+	}
+	if s.ContainerHealthCheckConfig.HealthMaxLogSize != nil {
+		options = append(options, libpod.WithHealthMaxLogSize(*s.ContainerHealthCheckConfig.HealthMaxLogSize))
+	}
 
 	if s.ContainerHealthCheckConfig.HealthCheckOnFailureAction != define.HealthCheckOnFailureActionNone {
 		options = append(options, libpod.WithHealthCheckOnFailureAction(s.ContainerHealthCheckConfig.HealthCheckOnFailureAction))
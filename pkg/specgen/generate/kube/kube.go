@@ -219,16 +219,23 @@ func ToSpecGen(ctx context.Context, opts *CtrSpecGenOptions) (*specgen.SpecGener
 				return nil, err
 			}
 			s.LogConfiguration.Size = logSize
+		case "max-file", "compress":
+			if s.LogConfiguration.Driver != define.JSONLogging {
+				logrus.Warnf("Can only set %s with json-file log driver but driver is %q", split[0], s.LogConfiguration.Driver)
+				continue
+			}
+			s.LogConfiguration.Options[split[0]] = split[1]
 		default:
 			switch len(split[1]) {
 			case 0:
 				return nil, fmt.Errorf("invalid log option: %w", define.ErrInvalidArg)
 			default:
-				// tags for journald only
-				if s.LogConfiguration.Driver == "" || s.LogConfiguration.Driver == define.JournaldLogging {
+				// tags for journald, options of the syslog and fluentd drivers
+				switch s.LogConfiguration.Driver {
+				case "", define.JournaldLogging, define.SyslogLogging, define.FluentdLogging:
 					s.LogConfiguration.Options[split[0]] = split[1]
-				} else {
-					logrus.Warnf("Can only set tags with journald log driver but driver is %q", s.LogConfiguration.Driver)
+				default:
+					logrus.Warnf("Can only set %s with journald, syslog or fluentd log driver but driver is %q", split[0], s.LogConfiguration.Driver)
 				}
 			}
 		}
@@ -245,6 +252,10 @@ func ToSpecGen(ctx context.Context, opts *CtrSpecGenOptions) (*specgen.SpecGener
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure startupProbe: %w", err)
 	}
+	err = setupReadinessProbe(s, opts.Container)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure readinessProbe: %w", err)
+	}
 
 	// Since we prefix the container name with pod name to work-around the uniqueness requirement,
 	// the seccomp profile should reference the actual container name from the YAML
@@ -565,7 +576,7 @@ func parseMountPath(mountPath string, readOnly bool, propagationMode *v1.MountPr
 
 func probeToHealthConfig(probe *v1.Probe) (*manifest.Schema2HealthConfig, error) {
 	var commandString string
-	failureCmd := "exit 1"
+	var healthProbe *define.HealthProbe
 	probeHandler := probe.Handler
 
 	// configure healthcheck on the basis of Handler Actions.
@@ -590,10 +601,42 @@ func probeToHealthConfig(probe *v1.Probe) (*manifest.Schema2HealthConfig, error)
 		path := "/"
 		if probeHandler.HTTPGet.Path != "" {
 			path = probeHandler.HTTPGet.Path
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+		}
+		// The probe is performed by Podman, so neither curl nor any
+		// other tool is required in the container's image.
+		healthProbe = &define.HealthProbe{
+			Type:    define.HealthConfigTestHTTPGet,
+			Address: fmt.Sprintf("%s://%s%s", strings.ToLower(string(uriScheme)), net.JoinHostPort(host, strconv.Itoa(probeHandler.HTTPGet.Port.IntValue())), path),
+		}
+		for _, header := range probeHandler.HTTPGet.HTTPHeaders {
+			healthProbe.Headers = append(healthProbe.Headers, header.Name+": "+header.Value)
 		}
-		commandString = fmt.Sprintf("curl -f %s://%s:%d%s || %s", uriScheme, host, probeHandler.HTTPGet.Port.IntValue(), path, failureCmd)
 	case probeHandler.TCPSocket != nil:
-		commandString = fmt.Sprintf("nc -z -v %s %d || %s", probeHandler.TCPSocket.Host, probeHandler.TCPSocket.Port.IntValue(), failureCmd)
+		host := "localhost"
+		if probeHandler.TCPSocket.Host != "" {
+			host = probeHandler.TCPSocket.Host
+		}
+		healthProbe = &define.HealthProbe{
+			Type:    define.HealthConfigTestTCP,
+			Address: net.JoinHostPort(host, strconv.Itoa(probeHandler.TCPSocket.Port.IntValue())),
+		}
+	case probeHandler.GRPC != nil:
+		healthProbe = &define.HealthProbe{
+			Type:    define.HealthConfigTestGRPC,
+			Address: net.JoinHostPort("localhost", strconv.Itoa(int(probeHandler.GRPC.Port))),
+		}
+		if probeHandler.GRPC.Service != nil {
+			healthProbe.Service = *probeHandler.GRPC.Service
+		}
+	}
+	if healthProbe != nil {
+		if err := healthProbe.Validate(); err != nil {
+			return nil, err
+		}
+		return makeHealthCheckFromTest(healthProbe.Test(), probe.PeriodSeconds, probe.FailureThreshold, probe.TimeoutSeconds, probe.InitialDelaySeconds)
 	}
 	return makeHealthCheck(commandString, probe.PeriodSeconds, probe.FailureThreshold, probe.TimeoutSeconds, probe.InitialDelaySeconds)
 }
@@ -650,6 +693,25 @@ func setupStartupProbe(s *specgen.SpecGenerator, containerYAML v1.Container, res
 	return nil
 }
 
+func setupReadinessProbe(s *specgen.SpecGenerator, containerYAML v1.Container) error {
+	if containerYAML.ReadinessProbe == nil {
+		return nil
+	}
+	emptyHandler := v1.Handler{}
+	if containerYAML.ReadinessProbe.Handler == emptyHandler {
+		return nil
+	}
+	healthConfig, err := probeToHealthConfig(containerYAML.ReadinessProbe)
+	if err != nil {
+		return err
+	}
+	s.ReadinessConfig = &define.ReadinessCheck{
+		Schema2HealthConfig: *healthConfig,
+		Successes:           int(containerYAML.ReadinessProbe.SuccessThreshold),
+	}
+	return nil
+}
+
 func makeHealthCheck(inCmd string, interval int32, retries int32, timeout int32, startPeriod int32) (*manifest.Schema2HealthConfig, error) {
 	// Every healthcheck requires a command
 	if len(inCmd) == 0 {
@@ -671,8 +733,14 @@ func makeHealthCheck(inCmd string, interval int32, retries int32, timeout int32,
 			cmd = append([]string{define.HealthConfigTestCmd}, cmd...)
 		}
 	}
+	return makeHealthCheckFromTest(cmd, interval, retries, timeout, startPeriod)
+}
+
+// makeHealthCheckFromTest returns a healthcheck config running the specified
+// test with the timing settings of a Kubernetes probe.
+func makeHealthCheckFromTest(test []string, interval int32, retries int32, timeout int32, startPeriod int32) (*manifest.Schema2HealthConfig, error) {
 	hc := manifest.Schema2HealthConfig{
-		Test: cmd,
+		Test: test,
 	}
 
 	if interval < 1 {
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/containers/common/pkg/secrets"
 	v1 "github.com/containers/podman/v4/pkg/k8s.io/api/core/v1"
@@ -1258,3 +1259,84 @@ func TestHttpLivenessProbe(t *testing.T) {
 		})
 	}
 }
+
+func TestNativeLivenessProbes(t *testing.T) {
+	service := "my.service"
+	tests := []struct {
+		name         string
+		probe        v1.Probe
+		expectedTest []string
+	}{
+		{
+			"HttpProbeWithHeaders",
+			v1.Probe{
+				Handler: v1.Handler{
+					HTTPGet: &v1.HTTPGetAction{
+						Scheme:      v1.URISchemeHTTPS,
+						Port:        intstr.FromInt(8443),
+						Path:        "healthz",
+						HTTPHeaders: []v1.HTTPHeader{{Name: "X-Token", Value: "secret"}},
+					},
+				},
+			},
+			[]string{"HTTP-GET", "https://localhost:8443/healthz", "header=X-Token: secret"},
+		},
+		{
+			"TcpProbe",
+			v1.Probe{
+				Handler: v1.Handler{
+					TCPSocket: &v1.TCPSocketAction{
+						Port: intstr.FromInt(5432),
+					},
+				},
+			},
+			[]string{"TCP", "localhost:5432"},
+		},
+		{
+			"GrpcProbe",
+			v1.Probe{
+				Handler: v1.Handler{
+					GRPC: &v1.GRPCAction{
+						Port:    50051,
+						Service: &service,
+					},
+				},
+			},
+			[]string{"GRPC", "localhost:50051", "service=my.service"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			s := specgen.SpecGenerator{}
+			err := setupLivenessProbe(&s, v1.Container{LivenessProbe: &test.probe}, "")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedTest, s.ContainerHealthCheckConfig.HealthConfig.Test)
+		})
+	}
+}
+
+func TestReadinessProbe(t *testing.T) {
+	s := specgen.SpecGenerator{}
+	container := v1.Container{
+		ReadinessProbe: &v1.Probe{
+			Handler: v1.Handler{
+				TCPSocket: &v1.TCPSocketAction{
+					Port: intstr.FromInt(5432),
+				},
+			},
+			PeriodSeconds:    5,
+			FailureThreshold: 2,
+			SuccessThreshold: 3,
+		},
+	}
+	err := setupReadinessProbe(&s, container)
+	assert.NoError(t, err)
+	assert.Nil(t, s.ContainerHealthCheckConfig.HealthConfig)
+	assert.NotNil(t, s.ContainerHealthCheckConfig.ReadinessConfig)
+	assert.Equal(t, []string{"TCP", "localhost:5432"}, s.ContainerHealthCheckConfig.ReadinessConfig.Test)
+	assert.Equal(t, 5*time.Second, s.ContainerHealthCheckConfig.ReadinessConfig.Interval)
+	assert.Equal(t, 2, s.ContainerHealthCheckConfig.ReadinessConfig.Retries)
+	assert.Equal(t, 3, s.ContainerHealthCheckConfig.ReadinessConfig.Successes)
+}
@@ -143,6 +143,14 @@ func createPodOptions(p *specgen.PodSpecGenerator) ([]libpod.PodCreateOption, er
 		options = append(options, libpod.WithServiceContainer(p.ServiceContainerID))
 	}
 
+	if len(p.KubeYAML) > 0 {
+		options = append(options, libpod.WithPodKubeYAML(p.KubeYAML))
+	}
+
+	if len(p.KubePlayOptions) > 0 {
+		options = append(options, libpod.WithPodKubePlayOptions(p.KubePlayOptions))
+	}
+
 	if len(p.CgroupParent) > 0 {
 		options = append(options, libpod.WithPodCgroupParent(p.CgroupParent))
 	}
@@ -232,6 +232,12 @@ type PodSpecGenerator struct {
 
 	// The ID of the pod's service container.
 	ServiceContainerID string `json:"serviceContainerID,omitempty"`
+
+	// The Kubernetes YAML the pod is created from.
+	KubeYAML string `json:"-"`
+
+	// The JSON-encoded options the Kubernetes YAML is played with.
+	KubePlayOptions string `json:"-"`
 }
 
 type PodResourceConfig struct {
@@ -556,6 +556,22 @@ type ContainerHealthCheckConfig struct {
 	// Requires that HealthConfig be set.
 	// Optional.
 	StartupHealthConfig *define.StartupHealthCheck `json:"startupHealthConfig,omitempty"`
+	// Readiness check for a container.  Dependent containers are only
+	// started once the container is ready.
+	// Optional.
+	ReadinessConfig *define.ReadinessCheck `json:"readinessConfig,omitempty"`
+	// HealthLogDestination is the destination of the healthcheck log: a
+	// file (default), journald or events.
+	// Optional.
+	HealthLogDestination string `json:"healthLogDestination,omitempty"`
+	// HealthMaxLogCount is the maximum number of entries of the
+	// healthcheck log file.  0 means unlimited.
+	// Optional.
+	HealthMaxLogCount *uint `json:"healthMaxLogCount,omitempty"`
+	// HealthMaxLogSize is the maximum number of bytes of the output of a
+	// healthcheck kept in the log.  0 means unlimited.
+	// Optional.
+	HealthMaxLogSize *uint `json:"healthMaxLogSize,omitempty"`
 }
 
 // SpecGenerator creates an OCI spec and Libpod configuration options to create
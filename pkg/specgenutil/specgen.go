@@ -281,14 +281,29 @@ func FillOutSpecGen(s *specgen.SpecGenerator, c *entities.ContainerCreateOptions
 		}
 	}
 
+	probe, err := makeHealthProbeFromCli(c)
+	if err != nil {
+		return err
+	}
 	if len(c.HealthCmd) > 0 {
 		if c.NoHealthCheck {
 			return errors.New("cannot specify both --no-healthcheck and --health-cmd")
 		}
+		if probe != nil {
+			return errors.New("cannot specify both --health-cmd and a health probe")
+		}
 		s.HealthConfig, err = makeHealthCheckFromCli(c.HealthCmd, c.HealthInterval, c.HealthRetries, c.HealthTimeout, c.HealthStartPeriod, false)
 		if err != nil {
 			return err
 		}
+	} else if probe != nil {
+		if c.NoHealthCheck {
+			return errors.New("cannot specify both --no-healthcheck and a health probe")
+		}
+		s.HealthConfig, err = makeHealthCheckFromTest(probe.Test(), c.HealthInterval, c.HealthRetries, c.HealthTimeout, c.HealthStartPeriod, false)
+		if err != nil {
+			return err
+		}
 	} else if c.NoHealthCheck {
 		s.HealthConfig = &manifest.Schema2HealthConfig{
 			Test: []string{"NONE"},
@@ -320,6 +335,34 @@ func FillOutSpecGen(s *specgen.SpecGenerator, c *entities.ContainerCreateOptions
 		s.StartupHealthConfig.Successes = int(c.StartupHCSuccesses)
 	}
 
+	if c.ReadyCmd != "" {
+		tmpHcConfig, err := makeHealthCheckFromCli(c.ReadyCmd, c.ReadyInterval, c.ReadyRetries, c.ReadyTimeout, c.ReadyStartPeriod, false)
+		if err != nil {
+			return err
+		}
+		if tmpHcConfig.Test[0] == define.HealthConfigTestNone {
+			return errors.New("--readiness-cmd cannot be none")
+		}
+		s.ReadinessConfig = new(define.ReadinessCheck)
+		s.ReadinessConfig.Schema2HealthConfig = *tmpHcConfig
+		s.ReadinessConfig.Successes = int(c.ReadySuccesses)
+	}
+
+	if c.HealthLogDest != "" && c.HealthLogDest != define.HealthCheckLogDestinationFile {
+		if err := define.ValidateHealthCheckLogDestination(c.HealthLogDest); err != nil {
+			return err
+		}
+		s.HealthLogDestination = c.HealthLogDest
+	}
+	if c.HealthMaxLogCount != define.DefaultHealthMaxLogCount {
+		maxLogCount := c.HealthMaxLogCount
+		s.HealthMaxLogCount = &maxLogCount
+	}
+	if c.HealthMaxLogSize != define.DefaultHealthMaxLogSize {
+		maxLogSize := c.HealthMaxLogSize
+		s.HealthMaxLogSize = &maxLogSize
+	}
+
 	if err := setNamespaces(s, c); err != nil {
 		return err
 	}
@@ -916,9 +959,61 @@ func makeHealthCheckFromCli(inCmd, interval string, retries uint, timeout, start
 		cmdArr = []string{define.HealthConfigTestNone}
 	}
 
+	return makeHealthCheckFromTest(cmdArr, interval, retries, timeout, startPeriod, isStartup)
+}
+
+// makeHealthProbeFromCli returns the health probe specified by the
+// --health-http, --health-tcp and --health-grpc options or nil if none is
+// specified.
+func makeHealthProbeFromCli(c *entities.ContainerCreateOptions) (*define.HealthProbe, error) {
+	var probe *define.HealthProbe
+	var err error
+	probes := 0
+	if c.HealthHTTP != "" {
+		probes++
+		probe, err = define.NewHealthProbe(define.HealthConfigTestHTTPGet, c.HealthHTTP)
+		if err != nil {
+			return nil, err
+		}
+		probe.Status = c.HealthHTTPStatus
+		probe.Headers = c.HealthHTTPHeader
+	} else if c.HealthHTTPStatus != 0 || len(c.HealthHTTPHeader) > 0 {
+		return nil, errors.New("--health-http-status and --health-http-header require --health-http")
+	}
+	if c.HealthTCP != "" {
+		probes++
+		probe, err = define.NewHealthProbe(define.HealthConfigTestTCP, c.HealthTCP)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c.HealthGRPC != "" {
+		probes++
+		probe, err = define.NewHealthProbe(define.HealthConfigTestGRPC, c.HealthGRPC)
+		if err != nil {
+			return nil, err
+		}
+		probe.Service = c.HealthGRPCService
+	} else if c.HealthGRPCService != "" {
+		return nil, errors.New("--health-grpc-service requires --health-grpc")
+	}
+	if probes > 1 {
+		return nil, errors.New("--health-http, --health-tcp and --health-grpc are mutually exclusive")
+	}
+	if probe != nil {
+		if err := probe.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return probe, nil
+}
+
+// makeHealthCheckFromTest returns a healthcheck config running the specified
+// test.
+func makeHealthCheckFromTest(test []string, interval string, retries uint, timeout, startPeriod string, isStartup bool) (*manifest.Schema2HealthConfig, error) {
 	// healthcheck is by default an array, so we simply pass the user input
 	hc := manifest.Schema2HealthConfig{
-		Test: cmdArr,
+		Test: test,
 	}
 
 	if interval == "disable" {
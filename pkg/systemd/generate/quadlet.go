@@ -0,0 +1,1124 @@
+package generate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/containers/common/libnetwork/types"
+	"github.com/containers/common/pkg/config"
+	"github.com/containers/common/pkg/signal"
+	"github.com/containers/podman/v4/libpod"
+	libpodDefine "github.com/containers/podman/v4/libpod/define"
+	"github.com/containers/podman/v4/pkg/annotations"
+	"github.com/containers/podman/v4/pkg/domain/entities"
+	"github.com/containers/podman/v4/pkg/env"
+	"github.com/containers/podman/v4/pkg/rootless"
+	"github.com/containers/podman/v4/pkg/systemd/parser"
+	"github.com/containers/podman/v4/pkg/systemd/quadlet"
+	"github.com/containers/podman/v4/version"
+	units "github.com/docker/go-units"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+)
+
+// defaultInterfaceName matches the interface names podman assigns to the
+// networks of a container when none is requested.
+var defaultInterfaceName = regexp.MustCompile(`^eth[0-9]+$`)
+
+// quadletContainerInfo contains the data required for generating a
+// container's Quadlet unit.
+type quadletContainerInfo struct {
+	// Config of the container.
+	Config *libpod.ContainerConfig
+	// Image is the configuration of the image the container was created
+	// from. Settings inherited from the image are not repeated in the unit.
+	Image *ociv1.ImageConfig
+	// NamedVolumes and Mounts are the volumes added by the user.
+	NamedVolumes []*libpod.ContainerNamedVolume
+	Mounts       []spec.Mount
+	// PodName is the name of the pod the container is part of.
+	PodName string
+	// PodMounts are the destinations of the volumes the container
+	// inherited from the infra container of its pod.
+	PodMounts map[string]bool
+	// DependencyNames maps the IDs of the containers this container joins
+	// the namespaces of to their names.
+	DependencyNames map[string]string
+	// Defaults is the engine configuration the container was created with.
+	Defaults *config.Config
+	// Rootless is set when the container is run by a rootless user.
+	Rootless bool
+	// Labeling is set when SELinux labeling is enabled, otherwise the
+	// label options of the container are set implicitly.
+	Labeling bool
+}
+
+// quadletPodInfo contains the data required for generating a pod's Quadlet
+// unit.
+type quadletPodInfo struct {
+	// Config of the pod.
+	Config *libpod.PodConfig
+	// Infra is the infra container of the pod, nil if it has none.
+	Infra *quadletContainerInfo
+}
+
+// quadletReport collects the settings which cannot be expressed in the
+// generated units.
+type quadletReport struct {
+	unsupported []string
+}
+
+func (r *quadletReport) add(unitName string, format string, a ...interface{}) {
+	r.unsupported = append(r.unsupported, fmt.Sprintf("%s: cannot express %s", unitName, fmt.Sprintf(format, a...)))
+}
+
+// ContainerQuadlet generates a Quadlet .container unit for the specified
+// container. It returns the units keyed by their file name along with the
+// settings of the container which could not be expressed.
+func ContainerQuadlet(ctx context.Context, ctr *libpod.Container, options entities.GenerateQuadletOptions) (map[string]string, []string, error) {
+	if ctr.IsInfra() {
+		return nil, nil, fmt.Errorf("%s is an infra container, generate the Quadlet unit of its pod instead", ctr.ID())
+	}
+	info, err := generateQuadletContainerInfo(ctx, ctr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(info.PodName) > 0 {
+		// The unit refers to the .pod unit, so make sure that the user
+		// knows about it
+		return nil, nil, fmt.Errorf("container %s is associated with pod %s: use generate on the pod itself", ctr.ID(), info.PodName)
+	}
+
+	report := &quadletReport{}
+	name, unit := containerQuadlet(info, report)
+	content, err := quadletToString(unit, name, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	return map[string]string{name: content}, report.unsupported, nil
+}
+
+// PodQuadlets generates a Quadlet .pod unit for the specified pod and a
+// .container unit for each of its containers.
+func PodQuadlets(ctx context.Context, pod *libpod.Pod, options entities.GenerateQuadletOptions) (map[string]string, []string, error) {
+	podConfig, err := pod.Config()
+	if err != nil {
+		return nil, nil, err
+	}
+	info := &quadletPodInfo{Config: podConfig}
+	var podMounts map[string]bool
+	if pod.HasInfraContainer() {
+		infra, err := pod.InfraContainer()
+		if err != nil {
+			return nil, nil, err
+		}
+		if info.Infra, err = generateQuadletContainerInfo(ctx, infra); err != nil {
+			return nil, nil, err
+		}
+		podMounts = make(map[string]bool)
+		for _, vol := range info.Infra.NamedVolumes {
+			podMounts[vol.Dest] = true
+		}
+		for _, mount := range info.Infra.Mounts {
+			podMounts[mount.Destination] = true
+		}
+	}
+
+	ctrs, err := pod.AllContainers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &quadletReport{}
+	name, unit := podQuadlet(info, report)
+	content, err := quadletToString(unit, name, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	quadlets := map[string]string{name: content}
+
+	for _, ctr := range ctrs {
+		if ctr.IsInfra() {
+			continue
+		}
+		ctrInfo, err := generateQuadletContainerInfo(ctx, ctr)
+		if err != nil {
+			return nil, nil, err
+		}
+		ctrInfo.PodMounts = podMounts
+		name, unit := containerQuadlet(ctrInfo, report)
+		content, err := quadletToString(unit, name, options)
+		if err != nil {
+			return nil, nil, err
+		}
+		quadlets[name] = content
+	}
+	return quadlets, report.unsupported, nil
+}
+
+// VolumeQuadlet generates a Quadlet .volume unit for the specified volume.
+func VolumeQuadlet(vol *libpod.Volume, options entities.GenerateQuadletOptions) (map[string]string, []string, error) {
+	name, unit := volumeQuadlet(vol.Name(), vol.Driver(), vol.Options(), vol.Labels())
+	content, err := quadletToString(unit, name, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	return map[string]string{name: content}, nil, nil
+}
+
+// NetworkQuadlet generates a Quadlet .network unit for the specified
+// network.
+func NetworkQuadlet(network *types.Network, options entities.GenerateQuadletOptions) (map[string]string, []string, error) {
+	report := &quadletReport{}
+	name, unit := networkQuadlet(network, report)
+	content, err := quadletToString(unit, name, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	return map[string]string{name: content}, report.unsupported, nil
+}
+
+func quadletToString(unit *parser.UnitFile, name string, options entities.GenerateQuadletOptions) (string, error) {
+	if !options.NoHeader {
+		unit.PrependComment("", name, "autogenerated by Podman "+version.Version.String())
+	}
+	return unit.ToString()
+}
+
+func generateQuadletContainerInfo(ctx context.Context, ctr *libpod.Container) (*quadletContainerInfo, error) {
+	defaults, err := ctr.Runtime().GetConfigNoCopy()
+	if err != nil {
+		return nil, err
+	}
+
+	ctrConfig := ctr.Config()
+	info := &quadletContainerInfo{
+		Config:          ctrConfig,
+		DependencyNames: make(map[string]string),
+		Defaults:        defaults,
+		Rootless:        rootless.IsRootless(),
+		Labeling:        ctr.Runtime().EnableLabeling(),
+	}
+	info.NamedVolumes, info.Mounts = ctr.SortUserVolumes(ctrConfig.Spec)
+
+	if len(ctrConfig.RootfsImageID) > 0 {
+		img, _, err := ctr.Runtime().LibimageRuntime().LookupImage(ctrConfig.RootfsImageID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("looking up image %q of container %q: %w", ctrConfig.RootfsImageName, ctr.ID(), err)
+		}
+		imgData, err := img.Inspect(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		info.Image = imgData.Config
+	}
+
+	if podID := ctr.PodID(); len(podID) > 0 {
+		pod, err := ctr.Runtime().LookupPod(podID)
+		if err != nil {
+			return nil, err
+		}
+		info.PodName = pod.Name()
+	}
+
+	for _, id := range []string{ctrConfig.IPCNsCtr, ctrConfig.NetNsCtr, ctrConfig.PIDNsCtr, ctrConfig.UTSNsCtr} {
+		if len(id) == 0 || len(info.DependencyNames[id]) > 0 {
+			continue
+		}
+		dep, err := ctr.Runtime().LookupContainer(id)
+		if err != nil {
+			return nil, err
+		}
+		info.DependencyNames[id] = dep.Name()
+	}
+	return info, nil
+}
+
+// containerQuadlet returns the file name and content of the Quadlet unit of
+// a container.
+func containerQuadlet(info *quadletContainerInfo, report *quadletReport) (string, *parser.UnitFile) {
+	ctrConfig := info.Config
+	ctrSpec := ctrConfig.Spec
+	if ctrSpec.Process == nil {
+		ctrSpec.Process = &spec.Process{}
+	}
+	img := info.Image
+	if img == nil {
+		img = &ociv1.ImageConfig{}
+	}
+
+	name := ctrConfig.Name + ".container"
+	group := quadlet.ContainerGroup
+	unit := parser.NewUnitFile()
+	podmanArgs := []string{}
+
+	if len(ctrConfig.Rootfs) > 0 {
+		rootfs := ctrConfig.Rootfs
+		if ctrConfig.RootfsOverlay {
+			rootfs += ":O"
+		}
+		if ctrConfig.RootfsMapping != nil {
+			report.add(name, "the ID mapping of the root filesystem")
+		}
+		unit.Add(group, quadlet.KeyRootfs, rootfs)
+	} else {
+		image := ctrConfig.RawImageName
+		if len(image) == 0 {
+			image = ctrConfig.RootfsImageName
+		}
+		unit.Add(group, quadlet.KeyImage, image)
+	}
+	unit.Add(group, quadlet.KeyContainerName, ctrConfig.Name)
+	if len(info.PodName) > 0 {
+		unit.Add(group, quadlet.KeyPod, info.PodName+".pod")
+	}
+
+	// If the entrypoint is overridden the command of the image is not used
+	// either, so both have to be set
+	entrypointChanged := len(ctrConfig.Entrypoint) > 0 && !reflect.DeepEqual(ctrConfig.Entrypoint, img.Entrypoint)
+	if entrypointChanged {
+		if len(ctrConfig.Entrypoint) == 1 {
+			unit.Add(group, quadlet.KeyEntrypoint, ctrConfig.Entrypoint[0])
+		} else {
+			entrypoint, _ := json.Marshal(ctrConfig.Entrypoint)
+			unit.Add(group, quadlet.KeyEntrypoint, string(entrypoint))
+		}
+	}
+	if len(ctrConfig.Command) > 0 && (entrypointChanged || !reflect.DeepEqual(ctrConfig.Command, img.Cmd)) {
+		unit.AddCmdline(group, quadlet.KeyExec, ctrConfig.Command)
+	}
+
+	imageEnv := make(map[string]string, len(img.Env))
+	for _, e := range img.Env {
+		key, val, _ := strings.Cut(e, "=")
+		imageEnv[key] = val
+	}
+	defaultEnv := env.DefaultEnvVariables()
+	for _, e := range ctrSpec.Process.Env {
+		key, val, _ := strings.Cut(e, "=")
+		if defaultEnv[key] == val || (imageEnv[key] == val && len(imageEnv[key]) > 0) {
+			continue
+		}
+		unit.AddCmdline(group, quadlet.KeyEnvironment, []string{e})
+	}
+
+	labels := make(map[string]string)
+	for key, val := range ctrConfig.Labels {
+		switch {
+		case key == "io.containers.autoupdate":
+			unit.Add(group, quadlet.KeyAutoUpdate, val)
+		case key == "PODMAN_SYSTEMD_UNIT":
+			// Set by the generated service
+		case img.Labels[key] == val:
+			// Inherited from the image
+		default:
+			labels[key] = val
+		}
+	}
+	addQuadletKeyVals(unit, group, quadlet.KeyLabel, labels)
+
+	ctrAnnotations := make(map[string]string)
+	for key, val := range ctrSpec.Annotations {
+		if libpodDefine.IsReservedAnnotation(key) || annotations.IsReservedAnnotation(key) {
+			continue
+		}
+		ctrAnnotations[key] = val
+	}
+	addQuadletKeyVals(unit, group, quadlet.KeyAnnotation, ctrAnnotations)
+
+	if len(info.PodName) == 0 {
+		addQuadletNetworks(unit, group, ctrConfig, info, report, name)
+		addQuadletPorts(unit, group, ctrConfig.PortMappings)
+
+		for _, ns := range []struct {
+			flag   string
+			nsType spec.LinuxNamespaceType
+			ctrID  string
+		}{
+			{"--ipc", spec.IPCNamespace, ctrConfig.IPCNsCtr},
+			{"--pid", spec.PIDNamespace, ctrConfig.PIDNsCtr},
+			{"--uts", spec.UTSNamespace, ctrConfig.UTSNsCtr},
+		} {
+			if mode := quadletNamespaceMode(ctrSpec, ns.nsType, ns.ctrID, info.DependencyNames); len(mode) > 0 {
+				podmanArgs = append(podmanArgs, ns.flag+"="+mode)
+			}
+		}
+
+		if len(ctrSpec.Hostname) > 0 && !strings.HasPrefix(ctrConfig.ID, ctrSpec.Hostname) {
+			unit.Add(group, quadlet.KeyHostName, ctrSpec.Hostname)
+		}
+	}
+	if ctrSpec.Linux != nil {
+		for _, ns := range ctrSpec.Linux.Namespaces {
+			if ns.Type == spec.UserNamespace && len(info.PodName) == 0 {
+				report.add(name, "the user namespace")
+			}
+		}
+	}
+
+	for _, ip := range ctrConfig.DNSServer {
+		unit.Add(group, quadlet.KeyDNS, ip.String())
+	}
+	for _, search := range ctrConfig.DNSSearch {
+		unit.Add(group, quadlet.KeyDNSSearch, search)
+	}
+	for _, option := range ctrConfig.DNSOption {
+		unit.Add(group, quadlet.KeyDNSOption, option)
+	}
+	for _, host := range ctrConfig.HostAdd {
+		unit.Add(group, quadlet.KeyAddHost, host)
+	}
+
+	if len(ctrConfig.User) > 0 && ctrConfig.User != img.User {
+		uid, gid, hasGroup := strings.Cut(ctrConfig.User, ":")
+		_, uidErr := strconv.ParseUint(uid, 10, 32)
+		_, gidErr := strconv.ParseUint(gid, 10, 32)
+		switch {
+		case uidErr == nil && !hasGroup:
+			unit.Add(group, quadlet.KeyUser, uid)
+		case uidErr == nil && gidErr == nil:
+			unit.Add(group, quadlet.KeyUser, uid)
+			unit.Add(group, quadlet.KeyGroup, gid)
+		default:
+			// User and Group only take numeric IDs
+			podmanArgs = append(podmanArgs, "--user="+ctrConfig.User)
+		}
+	}
+	for _, g := range ctrConfig.Groups {
+		unit.Add(group, quadlet.KeyGroupAdd, g)
+	}
+	if cwd := ctrSpec.Process.Cwd; len(cwd) > 0 && cwd != "/" && cwd != img.WorkingDir {
+		unit.Add(group, quadlet.KeyWorkingDir, cwd)
+	}
+
+	addQuadletContainerVolumes(unit, group, info, report, name)
+
+	for _, device := range ctrConfig.DeviceHostSrc {
+		unit.Add(group, quadlet.KeyAddDevice, device.Path)
+	}
+	for _, device := range ctrConfig.CDIDevices {
+		unit.Add(group, quadlet.KeyAddDevice, device)
+	}
+
+	for _, secret := range ctrConfig.Secrets {
+		opts := []string{secret.Name}
+		if len(secret.Target) > 0 && secret.Target != secret.Name {
+			opts = append(opts, "target="+secret.Target)
+		}
+		if secret.UID != 0 {
+			opts = append(opts, fmt.Sprintf("uid=%d", secret.UID))
+		}
+		if secret.GID != 0 {
+			opts = append(opts, fmt.Sprintf("gid=%d", secret.GID))
+		}
+		if secret.Mode != 0 && secret.Mode != 0o444 {
+			opts = append(opts, fmt.Sprintf("mode=%o", secret.Mode))
+		}
+		unit.Add(group, quadlet.KeySecret, strings.Join(opts, ","))
+	}
+	for _, target := range sortedKeys(ctrConfig.EnvSecrets) {
+		unit.Add(group, quadlet.KeySecret, fmt.Sprintf("%s,type=env,target=%s", ctrConfig.EnvSecrets[target].Name, target))
+	}
+
+	addQuadletSecurity(unit, group, info, &podmanArgs)
+	addQuadletResources(unit, group, info, &podmanArgs)
+	addQuadletHealthChecks(unit, group, ctrConfig)
+
+	stopSignal := uint(syscall.SIGTERM)
+	if len(img.StopSignal) > 0 {
+		if sig, err := signal.ParseSignalNameOrNumber(img.StopSignal); err == nil {
+			stopSignal = uint(sig)
+		}
+	}
+	if ctrConfig.StopSignal != 0 && ctrConfig.StopSignal != stopSignal {
+		sigName := unix.SignalName(syscall.Signal(ctrConfig.StopSignal))
+		if len(sigName) == 0 {
+			sigName = strconv.FormatUint(uint64(ctrConfig.StopSignal), 10)
+		}
+		unit.Add(group, quadlet.KeyStopSignal, sigName)
+	}
+	if info.Defaults != nil && ctrConfig.StopTimeout != info.Defaults.Engine.StopTimeout {
+		unit.Add(group, quadlet.KeyStopTimeout, strconv.FormatUint(uint64(ctrConfig.StopTimeout), 10))
+	}
+
+	if len(ctrConfig.Timezone) > 0 {
+		unit.Add(group, quadlet.KeyTimezone, ctrConfig.Timezone)
+	}
+	if info.Defaults != nil && len(ctrConfig.LogDriver) > 0 && ctrConfig.LogDriver != info.Defaults.Containers.LogDriver {
+		unit.Add(group, quadlet.KeyLogDriver, ctrConfig.LogDriver)
+	}
+	if len(ctrConfig.LogTag) > 0 {
+		podmanArgs = append(podmanArgs, "--log-opt=tag="+ctrConfig.LogTag)
+	}
+	if ctrConfig.LogSize > 0 {
+		podmanArgs = append(podmanArgs, fmt.Sprintf("--log-opt=max-size=%d", ctrConfig.LogSize))
+	}
+
+	if ctrSpec.Process.Terminal {
+		report.add(name, "--tty")
+	}
+	if ctrConfig.Stdin {
+		report.add(name, "--interactive")
+	}
+	if ctrConfig.PreserveFDs > 0 {
+		report.add(name, "--preserve-fds")
+	}
+
+	if len(podmanArgs) > 0 {
+		unit.AddCmdline(group, quadlet.KeyPodmanArgs, podmanArgs)
+	}
+
+	switch ctrConfig.RestartPolicy {
+	case libpodDefine.RestartPolicyAlways, libpodDefine.RestartPolicyUnlessStopped:
+		unit.Add(quadlet.ServiceGroup, "Restart", "always")
+	case libpodDefine.RestartPolicyOnFailure:
+		unit.Add(quadlet.ServiceGroup, "Restart", "on-failure")
+		if ctrConfig.RestartRetries > 0 {
+			report.add(name, "the number of restart retries")
+		}
+	}
+
+	// Containers of a pod are started by the pod service
+	if len(info.PodName) == 0 {
+		unit.Add(quadlet.InstallGroup, "WantedBy", "default.target")
+	}
+
+	return name, unit
+}
+
+// addQuadletNetworks adds the networks of a container unless it uses the
+// default network.
+func addQuadletNetworks(unit *parser.UnitFile, group string, ctrConfig *libpod.ContainerConfig, info *quadletContainerInfo, report *quadletReport, name string) {
+	netMode := ctrConfig.NetMode
+	var networks []string
+	switch {
+	case len(ctrConfig.NetNsCtr) > 0:
+		networks = append(networks, "container:"+info.DependencyNames[ctrConfig.NetNsCtr])
+	case netMode.IsBridge():
+		for _, netName := range sortedKeys(ctrConfig.Networks) {
+			netOpts := ctrConfig.Networks[netName]
+			opts := []string{}
+			for _, ip := range netOpts.StaticIPs {
+				opts = append(opts, "ip="+ip.String())
+			}
+			if len(netOpts.StaticMAC) > 0 {
+				opts = append(opts, "mac="+netOpts.StaticMAC.String())
+			}
+			for _, alias := range netOpts.Aliases {
+				if alias == ctrConfig.Name || strings.HasPrefix(ctrConfig.ID, alias) {
+					continue
+				}
+				opts = append(opts, "alias="+alias)
+			}
+			if len(netOpts.InterfaceName) > 0 && !defaultInterfaceName.MatchString(netOpts.InterfaceName) {
+				opts = append(opts, "interface_name="+netOpts.InterfaceName)
+			}
+			if len(opts) > 0 {
+				networks = append(networks, netName+":"+strings.Join(opts, ","))
+			} else {
+				networks = append(networks, netName)
+			}
+		}
+		if info.Defaults != nil && !info.Rootless && len(networks) == 1 && networks[0] == info.Defaults.Network.DefaultNetwork {
+			networks = nil
+		}
+	case netMode.IsSlirp4netns(), netMode.IsPasta():
+		mode := string(netMode)
+		opts := ctrConfig.NetworkOptions[mode]
+		if len(opts) > 0 {
+			networks = append(networks, mode+":"+strings.Join(opts, ","))
+		} else if info.Defaults == nil || !info.Rootless || mode != info.Defaults.Network.DefaultRootlessNetworkCmd {
+			networks = append(networks, mode)
+		}
+	case netMode.IsHost(), netMode.IsNone(), netMode.IsNS():
+		networks = append(networks, string(netMode))
+	case len(netMode) > 0 && !netMode.IsDefault() && !netMode.IsPrivate():
+		report.add(name, "the network mode %q", netMode)
+	}
+	for _, network := range networks {
+		unit.Add(group, quadlet.KeyNetwork, network)
+	}
+}
+
+// addQuadletPorts adds the published ports of a container or pod.
+func addQuadletPorts(unit *parser.UnitFile, group string, ports []types.PortMapping) {
+	for _, port := range ports {
+		hostPort := strconv.Itoa(int(port.HostPort))
+		containerPort := strconv.Itoa(int(port.ContainerPort))
+		if port.Range > 1 {
+			hostPort += "-" + strconv.Itoa(int(port.HostPort+port.Range-1))
+			containerPort += "-" + strconv.Itoa(int(port.ContainerPort+port.Range-1))
+		}
+		publish := hostPort + ":" + containerPort
+		if len(port.HostIP) > 0 {
+			hostIP := port.HostIP
+			if ip := net.ParseIP(hostIP); ip != nil && ip.To4() == nil {
+				hostIP = "[" + hostIP + "]"
+			}
+			publish = hostIP + ":" + publish
+		}
+		for _, protocol := range strings.Split(port.Protocol, ",") {
+			if len(protocol) > 0 && protocol != "tcp" {
+				unit.Add(group, quadlet.KeyPublishPort, publish+"/"+protocol)
+			} else {
+				unit.Add(group, quadlet.KeyPublishPort, publish)
+			}
+		}
+	}
+}
+
+// addQuadletContainerVolumes adds the volumes, bind mounts and tmpfs mounts
+// the user added to a container.
+func addQuadletContainerVolumes(unit *parser.UnitFile, group string, info *quadletContainerInfo, report *quadletReport, name string) {
+	for _, vol := range info.NamedVolumes {
+		if info.PodMounts[vol.Dest] {
+			continue
+		}
+		switch {
+		case len(vol.SubPath) > 0:
+			report.add(name, "the subpath of volume %s", vol.Name)
+		case vol.IsAnonymous:
+			unit.Add(group, quadlet.KeyVolume, vol.Dest)
+		default:
+			unit.Add(group, quadlet.KeyVolume, joinQuadletVolume(vol.Name, vol.Dest, filterMountOptions(vol.Options)))
+		}
+	}
+
+	for _, mount := range info.Mounts {
+		if info.PodMounts[mount.Destination] {
+			continue
+		}
+		opts := filterMountOptions(mount.Options)
+		switch mount.Type {
+		case "bind":
+			unit.Add(group, quadlet.KeyVolume, joinQuadletVolume(mount.Source, mount.Destination, opts))
+		case "tmpfs":
+			if len(opts) > 0 {
+				unit.Add(group, quadlet.KeyTmpfs, mount.Destination+":"+strings.Join(opts, ","))
+			} else {
+				unit.Add(group, quadlet.KeyTmpfs, mount.Destination)
+			}
+		default:
+			report.add(name, "the %s mount at %s", mount.Type, mount.Destination)
+		}
+	}
+
+	for _, vol := range info.Config.ImageVolumes {
+		mount := fmt.Sprintf("type=image,source=%s,destination=%s", vol.Source, vol.Dest)
+		if vol.ReadWrite {
+			mount += ",rw=true"
+		}
+		unit.Add(group, quadlet.KeyMount, mount)
+	}
+	for _, vol := range info.Config.OverlayVolumes {
+		report.add(name, "the overlay volume at %s", vol.Dest)
+	}
+}
+
+// filterMountOptions removes the mount options podman adds by default.
+func filterMountOptions(options []string) []string {
+	opts := make([]string, 0, len(options))
+	for _, opt := range options {
+		switch opt {
+		case "rw", "rprivate", "rbind", "tmpcopyup", "nosuid", "nodev":
+		default:
+			opts = append(opts, opt)
+		}
+	}
+	return opts
+}
+
+func joinQuadletVolume(source string, dest string, opts []string) string {
+	vol := source + ":" + dest
+	if len(opts) > 0 {
+		vol += ":" + strings.Join(opts, ",")
+	}
+	return vol
+}
+
+// addQuadletSecurity adds the security settings of a container which differ
+// from the defaults.
+func addQuadletSecurity(unit *parser.UnitFile, group string, info *quadletContainerInfo, podmanArgs *[]string) {
+	ctrConfig := info.Config
+	ctrSpec := ctrConfig.Spec
+
+	if ctrConfig.Privileged {
+		*podmanArgs = append(*podmanArgs, "--privileged")
+	} else if ctrSpec.Process.Capabilities != nil && info.Defaults != nil {
+		normalize := func(c string) string {
+			c = strings.ToUpper(c)
+			if !strings.HasPrefix(c, "CAP_") {
+				c = "CAP_" + c
+			}
+			return c
+		}
+		defaultCaps := make(map[string]bool)
+		for _, c := range info.Defaults.Containers.DefaultCapabilities {
+			defaultCaps[normalize(c)] = true
+		}
+		currentCaps := make(map[string]bool)
+		for _, c := range ctrSpec.Process.Capabilities.Bounding {
+			currentCaps[normalize(c)] = true
+			if !defaultCaps[normalize(c)] {
+				unit.Add(group, quadlet.KeyAddCapability, normalize(c))
+			}
+		}
+		for _, c := range sortedKeys(defaultCaps) {
+			if !currentCaps[c] {
+				unit.Add(group, quadlet.KeyDropCapability, c)
+			}
+		}
+	}
+
+	if ctrSpec.Process.NoNewPrivileges {
+		unit.Add(group, quadlet.KeyNoNewPrivileges, "true")
+	}
+	if ctrSpec.Root != nil && ctrSpec.Root.Readonly {
+		unit.Add(group, quadlet.KeyReadOnly, "true")
+		// Podman mounts tmpfs on /tmp and friends of read only
+		// containers unless told otherwise
+		unit.Add(group, quadlet.KeyVolatileTmp, "true")
+	}
+	if ctrSpec.Annotations[libpodDefine.InspectAnnotationInit] == libpodDefine.InspectResponseTrue {
+		unit.Add(group, quadlet.KeyRunInit, "true")
+	}
+	if seccomp := ctrSpec.Annotations[libpodDefine.InspectAnnotationSeccomp]; len(seccomp) > 0 {
+		unit.Add(group, quadlet.KeySeccompProfile, seccomp)
+	}
+	if apparmor := ctrSpec.Annotations[libpodDefine.InspectAnnotationApparmor]; len(apparmor) > 0 {
+		*podmanArgs = append(*podmanArgs, "--security-opt=apparmor="+apparmor)
+	}
+
+	// The label options are copied from the containers whose PID or IPC
+	// namespace is joined
+	labelOpts := ctrConfig.LabelOpts
+	if !info.Labeling || ctrConfig.Privileged || len(ctrConfig.PIDNsCtr) > 0 || len(ctrConfig.IPCNsCtr) > 0 {
+		labelOpts = nil
+	}
+	for _, opt := range labelOpts {
+		key, val, _ := strings.Cut(opt, ":")
+		switch key {
+		case "disable":
+			// Implied by sharing the PID or IPC namespace of the host
+			if quadletNamespaceMode(ctrSpec, spec.PIDNamespace, ctrConfig.PIDNsCtr, nil) == "host" ||
+				quadletNamespaceMode(ctrSpec, spec.IPCNamespace, ctrConfig.IPCNsCtr, nil) == "host" {
+				continue
+			}
+			unit.Add(group, quadlet.KeySecurityLabelDisable, "true")
+		case "type":
+			unit.Add(group, quadlet.KeySecurityLabelType, val)
+		case "filetype":
+			unit.Add(group, quadlet.KeySecurityLabelFileType, val)
+		case "level":
+			unit.Add(group, quadlet.KeySecurityLabelLevel, val)
+		default:
+			*podmanArgs = append(*podmanArgs, "--security-opt=label="+opt)
+		}
+	}
+	if ctrConfig.LabelNested {
+		unit.Add(group, quadlet.KeySecurityLabelNested, "true")
+	}
+
+	if ctrSpec.Linux != nil {
+		defaultSysctls := make(map[string]bool)
+		if info.Defaults != nil {
+			for _, sysctl := range info.Defaults.Containers.DefaultSysctls {
+				defaultSysctls[sysctl] = true
+			}
+		}
+		for _, key := range sortedKeys(ctrSpec.Linux.Sysctl) {
+			sysctl := key + "=" + ctrSpec.Linux.Sysctl[key]
+			if !defaultSysctls[sysctl] {
+				unit.AddCmdline(group, quadlet.KeySysctl, []string{sysctl})
+			}
+		}
+	}
+}
+
+// addQuadletResources adds the resource limits of a container which differ
+// from the defaults.
+func addQuadletResources(unit *parser.UnitFile, group string, info *quadletContainerInfo, podmanArgs *[]string) {
+	ctrConfig := info.Config
+	if info.Defaults != nil {
+		defaultShmSize, err := units.FromHumanSize(info.Defaults.Containers.ShmSize)
+		if err == nil && ctrConfig.ShmSize > 0 && ctrConfig.ShmSize != defaultShmSize {
+			unit.Add(group, quadlet.KeyShmSize, strconv.FormatInt(ctrConfig.ShmSize, 10))
+		}
+	}
+
+	if ctrConfig.Spec.Linux == nil || ctrConfig.Spec.Linux.Resources == nil {
+		return
+	}
+	resources := ctrConfig.Spec.Linux.Resources
+	if resources.Memory != nil && resources.Memory.Limit != nil && *resources.Memory.Limit > 0 {
+		unit.Add(group, quadlet.KeyMemory, strconv.FormatInt(*resources.Memory.Limit, 10))
+	}
+	if cpu := resources.CPU; cpu != nil {
+		if cpu.Quota != nil && *cpu.Quota > 0 && cpu.Period != nil && *cpu.Period > 0 {
+			// CPUQuota only takes whole percents
+			if (*cpu.Quota*100)%int64(*cpu.Period) == 0 {
+				unit.Add(group, quadlet.KeyCPUQuota, fmt.Sprintf("%d%%", *cpu.Quota*100/int64(*cpu.Period)))
+			} else {
+				*podmanArgs = append(*podmanArgs, fmt.Sprintf("--cpu-quota=%d", *cpu.Quota), fmt.Sprintf("--cpu-period=%d", *cpu.Period))
+			}
+		}
+		if cpu.Shares != nil && *cpu.Shares > 0 {
+			// CPUWeight is converted to shares, use it when it is exact
+			if weight := *cpu.Shares * 100 / 1024; weight >= 1 && weight <= 10000 && weight*1024/100 == *cpu.Shares {
+				unit.Add(group, quadlet.KeyCPUWeight, strconv.FormatUint(weight, 10))
+			} else {
+				*podmanArgs = append(*podmanArgs, fmt.Sprintf("--cpu-shares=%d", *cpu.Shares))
+			}
+		}
+	}
+	if resources.Pids != nil && info.Defaults != nil && resources.Pids.Limit != info.Defaults.Containers.PidsLimit {
+		unit.Add(group, quadlet.KeyPidsLimit, strconv.FormatInt(resources.Pids.Limit, 10))
+	}
+}
+
+// addQuadletHealthChecks adds the health checks of a container, leaving out
+// the settings which have their default value.
+func addQuadletHealthChecks(unit *parser.UnitFile, group string, ctrConfig *libpod.ContainerConfig) {
+	if hc := ctrConfig.HealthCheckConfig; hc != nil && len(hc.Test) > 0 {
+		if probe, err := libpodDefine.ParseHealthProbe(hc.Test); err == nil {
+			addQuadletHealthProbe(unit, group, probe)
+		} else {
+			unit.Add(group, quadlet.KeyHealthCmd, quadletHealthCmd(hc.Test))
+		}
+		if hc.Interval > 0 && hc.Interval.String() != libpodDefine.DefaultHealthCheckInterval {
+			unit.Add(group, quadlet.KeyHealthInterval, hc.Interval.String())
+		}
+		if hc.Timeout > 0 && hc.Timeout.String() != libpodDefine.DefaultHealthCheckTimeout {
+			unit.Add(group, quadlet.KeyHealthTimeout, hc.Timeout.String())
+		}
+		if hc.StartPeriod > 0 {
+			unit.Add(group, quadlet.KeyHealthStartPeriod, hc.StartPeriod.String())
+		}
+		if hc.Retries > 0 && uint(hc.Retries) != libpodDefine.DefaultHealthCheckRetries {
+			unit.Add(group, quadlet.KeyHealthRetries, strconv.Itoa(hc.Retries))
+		}
+	}
+	if ctrConfig.HealthCheckOnFailureAction != libpodDefine.HealthCheckOnFailureActionNone {
+		unit.Add(group, quadlet.KeyHealthOnFailure, ctrConfig.HealthCheckOnFailureAction.String())
+	}
+	if hc := ctrConfig.StartupHealthCheckConfig; hc != nil && len(hc.Test) > 0 {
+		unit.Add(group, quadlet.KeyHealthStartupCmd, quadletHealthCmd(hc.Test))
+		if hc.Interval > 0 && hc.Interval.String() != libpodDefine.DefaultHealthCheckInterval {
+			unit.Add(group, quadlet.KeyHealthStartupInterval, hc.Interval.String())
+		}
+		if hc.Timeout > 0 && hc.Timeout.String() != libpodDefine.DefaultHealthCheckTimeout {
+			unit.Add(group, quadlet.KeyHealthStartupTimeout, hc.Timeout.String())
+		}
+		if hc.Retries > 0 {
+			unit.Add(group, quadlet.KeyHealthStartupRetries, strconv.Itoa(hc.Retries))
+		}
+		if hc.Successes > 0 {
+			unit.Add(group, quadlet.KeyHealthStartupSuccess, strconv.Itoa(hc.Successes))
+		}
+	}
+}
+
+// addQuadletHealthProbe adds the keys of a health probe.
+func addQuadletHealthProbe(unit *parser.UnitFile, group string, probe *libpodDefine.HealthProbe) {
+	switch probe.Type {
+	case libpodDefine.HealthConfigTestHTTPGet:
+		unit.Add(group, quadlet.KeyHealthHTTP, probe.Address)
+		if probe.Status != 0 {
+			unit.Add(group, quadlet.KeyHealthHTTPStatus, strconv.Itoa(probe.Status))
+		}
+		for _, header := range probe.Headers {
+			unit.Add(group, quadlet.KeyHealthHTTPHeader, header)
+		}
+	case libpodDefine.HealthConfigTestTCP:
+		unit.Add(group, quadlet.KeyHealthTCP, probe.Address)
+	case libpodDefine.HealthConfigTestGRPC:
+		unit.Add(group, quadlet.KeyHealthGRPC, probe.Address)
+		if probe.Service != "" {
+			unit.Add(group, quadlet.KeyHealthGRPCService, probe.Service)
+		}
+	}
+}
+
+// quadletHealthCmd converts the test of a health check back to the format
+// of --health-cmd.
+func quadletHealthCmd(test []string) string {
+	switch test[0] {
+	case "NONE":
+		return "none"
+	case "CMD-SHELL":
+		return strings.Join(test[1:], " ")
+	case "CMD":
+		test = test[1:]
+	}
+	cmd, _ := json.Marshal(test)
+	return string(cmd)
+}
+
+// quadletNamespaceMode returns the mode of a namespace of the container in
+// the format of the podman flags, or an empty string for the default.
+func quadletNamespaceMode(ctrSpec *spec.Spec, nsType spec.LinuxNamespaceType, ctrID string, names map[string]string) string {
+	if len(ctrID) > 0 {
+		return "container:" + names[ctrID]
+	}
+	if ctrSpec.Linux == nil {
+		return ""
+	}
+	for _, ns := range ctrSpec.Linux.Namespaces {
+		if ns.Type != nsType {
+			continue
+		}
+		if len(ns.Path) > 0 {
+			return "ns:" + ns.Path
+		}
+		return ""
+	}
+	return "host"
+}
+
+// podQuadlet returns the file name and content of the Quadlet unit of a pod.
+func podQuadlet(info *quadletPodInfo, report *quadletReport) (string, *parser.UnitFile) {
+	podConfig := info.Config
+	name := podConfig.Name + ".pod"
+	group := quadlet.PodGroup
+	unit := parser.NewUnitFile()
+	podmanArgs := []string{}
+
+	unit.Add(group, quadlet.KeyPodName, podConfig.Name)
+
+	if infra := info.Infra; infra != nil {
+		infraImage := infra.Config.RawImageName
+		if len(infraImage) > 0 && !strings.HasPrefix(infraImage, "localhost/podman-pause:") &&
+			(infra.Defaults == nil || infraImage != infra.Defaults.Engine.InfraImage) {
+			unit.Add(group, quadlet.KeyInfraImage, infraImage)
+		}
+		if podConfig.UsePodNet {
+			addQuadletNetworks(unit, group, infra.Config, infra, report, name)
+			addQuadletPorts(unit, group, infra.Config.PortMappings)
+		}
+		for _, vol := range infra.NamedVolumes {
+			unit.Add(group, quadlet.KeyVolume, joinQuadletVolume(vol.Name, vol.Dest, filterMountOptions(vol.Options)))
+		}
+		for _, mount := range infra.Mounts {
+			if mount.Type == "bind" {
+				unit.Add(group, quadlet.KeyVolume, joinQuadletVolume(mount.Source, mount.Destination, filterMountOptions(mount.Options)))
+			} else {
+				report.add(name, "the %s mount at %s", mount.Type, mount.Destination)
+			}
+		}
+	} else {
+		// The pod service relies on the infra container
+		report.add(name, "a pod without infra container")
+	}
+
+	shares := []string{}
+	for _, ns := range []struct {
+		name  string
+		share bool
+	}{
+		{"cgroup", podConfig.UsePodCgroupNS},
+		{"ipc", podConfig.UsePodIPC},
+		{"net", podConfig.UsePodNet},
+		{"pid", podConfig.UsePodPID},
+		{"uts", podConfig.UsePodUTS},
+	} {
+		if ns.share {
+			shares = append(shares, ns.name)
+		}
+	}
+	if info.Infra != nil && strings.Join(shares, ",") != "ipc,net,uts" {
+		podmanArgs = append(podmanArgs, "--share="+strings.Join(shares, ","))
+	}
+	if podConfig.UsePodUser {
+		report.add(name, "the user namespace")
+	}
+	if len(podConfig.Hostname) > 0 && podConfig.Hostname != podConfig.Name {
+		podmanArgs = append(podmanArgs, "--hostname="+podConfig.Hostname)
+	}
+	for _, key := range sortedKeys(podConfig.Labels) {
+		podmanArgs = append(podmanArgs, "--label="+key+"="+podConfig.Labels[key])
+	}
+
+	if len(podmanArgs) > 0 {
+		unit.AddCmdline(group, quadlet.KeyPodmanArgs, podmanArgs)
+	}
+
+	switch podConfig.RestartPolicy {
+	case libpodDefine.RestartPolicyAlways, libpodDefine.RestartPolicyUnlessStopped:
+		unit.Add(quadlet.ServiceGroup, "Restart", "always")
+	case libpodDefine.RestartPolicyOnFailure:
+		unit.Add(quadlet.ServiceGroup, "Restart", "on-failure")
+	}
+
+	unit.Add(quadlet.InstallGroup, "WantedBy", "default.target")
+
+	return name, unit
+}
+
+// volumeQuadlet returns the file name and content of the Quadlet unit of a
+// volume.
+func volumeQuadlet(volName string, driver string, options map[string]string, labels map[string]string) (string, *parser.UnitFile) {
+	name := volName + ".volume"
+	group := quadlet.VolumeGroup
+	unit := parser.NewUnitFile()
+	podmanArgs := []string{}
+
+	// Quadlet would otherwise create systemd-$name, which the containers
+	// do not refer to
+	unit.Add(group, quadlet.KeyVolumeName, volName)
+
+	if len(driver) > 0 && driver != libpodDefine.VolumeDriverLocal {
+		podmanArgs = append(podmanArgs, "--driver="+driver)
+	}
+
+	device, hasDevice := options["device"]
+	if hasDevice {
+		unit.Add(group, quadlet.KeyDevice, device)
+		if devType, ok := options["type"]; ok {
+			unit.Add(group, quadlet.KeyType, devType)
+		}
+	}
+
+	// Mount options are only supported along with Device, except for the
+	// owner of the volume
+	mountOpts := []string{}
+	for _, opt := range strings.Split(options["o"], ",") {
+		key, val, _ := strings.Cut(opt, "=")
+		switch {
+		case len(opt) == 0:
+		case key == "uid" && hasDevice:
+			unit.Add(group, quadlet.KeyUser, val)
+		case key == "gid" && hasDevice:
+			unit.Add(group, quadlet.KeyGroup, val)
+		default:
+			mountOpts = append(mountOpts, opt)
+		}
+	}
+	if options["NOQUOTA"] == "true" {
+		mountOpts = append(mountOpts, "noquota")
+	}
+	if len(mountOpts) > 0 {
+		if hasDevice {
+			unit.Add(group, quadlet.KeyOptions, strings.Join(mountOpts, ","))
+		} else {
+			podmanArgs = append(podmanArgs, "--opt=o="+strings.Join(mountOpts, ","))
+		}
+	}
+
+	for _, key := range sortedKeys(options) {
+		switch key {
+		case "device", "o":
+			// Handled above
+		case "type":
+			if !hasDevice {
+				podmanArgs = append(podmanArgs, "--opt=type="+options[key])
+			}
+		case "copy":
+			unit.Add(group, quadlet.KeyCopy, "true")
+		case "nocopy":
+			unit.Add(group, quadlet.KeyCopy, "false")
+		case "UID", "GID", "SIZE", "INODES", "NOQUOTA":
+			// Parsed from the mount options
+		default:
+			if len(options[key]) > 0 {
+				podmanArgs = append(podmanArgs, "--opt="+key+"="+options[key])
+			} else {
+				podmanArgs = append(podmanArgs, "--opt="+key)
+			}
+		}
+	}
+
+	addQuadletKeyVals(unit, group, quadlet.KeyLabel, labels)
+
+	if len(podmanArgs) > 0 {
+		unit.AddCmdline(group, quadlet.KeyPodmanArgs, podmanArgs)
+	}
+	return name, unit
+}
+
+// networkQuadlet returns the file name and content of the Quadlet unit of a
+// network.
+func networkQuadlet(network *types.Network, report *quadletReport) (string, *parser.UnitFile) {
+	name := network.Name + ".network"
+	group := quadlet.NetworkGroup
+	unit := parser.NewUnitFile()
+	podmanArgs := []string{}
+
+	// Quadlet would otherwise create systemd-$name, which the containers
+	// do not refer to
+	unit.Add(group, quadlet.KeyNetworkName, network.Name)
+
+	if len(network.Driver) > 0 && network.Driver != types.BridgeNetworkDriver {
+		unit.Add(group, quadlet.KeyNetworkDriver, network.Driver)
+		if len(network.NetworkInterface) > 0 {
+			podmanArgs = append(podmanArgs, "--interface-name="+network.NetworkInterface)
+		}
+	}
+
+	// Subnets are matched with gateways by their position, so the gateway
+	// of every subnet has to be set if any is
+	hasGateway := false
+	for _, subnet := range network.Subnets {
+		if subnet.Gateway != nil {
+			hasGateway = true
+		}
+	}
+	for _, subnet := range network.Subnets {
+		unit.Add(group, quadlet.KeyNetworkSubnet, subnet.Subnet.String())
+		if hasGateway {
+			if subnet.Gateway == nil {
+				report.add(name, "the subnet %s without a gateway", subnet.Subnet.String())
+				continue
+			}
+			unit.Add(group, quadlet.KeyNetworkGateway, subnet.Gateway.String())
+		}
+		if subnet.LeaseRange != nil {
+			report.add(name, "the IP range of the subnet %s", subnet.Subnet.String())
+		}
+	}
+
+	if network.Driver == types.BridgeNetworkDriver && !network.DNSEnabled {
+		unit.Add(group, quadlet.KeyNetworkDisableDNS, "true")
+	}
+	if network.Internal {
+		unit.Add(group, quadlet.KeyNetworkInternal, "true")
+	}
+	if network.IPv6Enabled {
+		unit.Add(group, quadlet.KeyNetworkIPv6, "true")
+	}
+	if ipamDriver := network.IPAMOptions[types.Driver]; len(ipamDriver) > 0 && ipamDriver != types.HostLocalIPAMDriver {
+		unit.Add(group, quadlet.KeyNetworkIPAMDriver, ipamDriver)
+	}
+	addQuadletKeyVals(unit, group, quadlet.KeyNetworkOptions, network.Options)
+	addQuadletKeyVals(unit, group, quadlet.KeyLabel, network.Labels)
+
+	if len(podmanArgs) > 0 {
+		unit.AddCmdline(group, quadlet.KeyPodmanArgs, podmanArgs)
+	}
+	return name, unit
+}
+
+// addQuadletKeyVals adds a key=value entry for each element of vals, sorted
+// by key.
+func addQuadletKeyVals(unit *parser.UnitFile, group string, key string, vals map[string]string) {
+	for _, k := range sortedKeys(vals) {
+		unit.AddCmdline(group, key, []string{k + "=" + vals[k]})
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,384 @@
+package generate
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containers/common/libnetwork/types"
+	"github.com/containers/common/pkg/config"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/podman/v4/libpod"
+	"github.com/containers/podman/v4/pkg/namespaces"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerQuadlet(t *testing.T) {
+	defaults := &config.Config{}
+	defaults.Containers.DefaultCapabilities = []string{"CHOWN", "KILL"}
+	defaults.Containers.LogDriver = "journald"
+	defaults.Containers.ShmSize = "65536k"
+	defaults.Engine.StopTimeout = 10
+	defaults.Network.DefaultNetwork = "podman"
+
+	memory := int64(104857600)
+	quota := int64(50000)
+	period := uint64(100000)
+	newConfig := func() *libpod.ContainerConfig {
+		ctrConfig := &libpod.ContainerConfig{
+			Spec: &spec.Spec{
+				Process: &spec.Process{
+					Env: []string{
+						"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+						"container=podman",
+						"IMAGE=env",
+						"FOO=a b",
+					},
+					Cwd: "/srv",
+					Capabilities: &spec.LinuxCapabilities{
+						Bounding: []string{"CAP_CHOWN", "CAP_NET_ADMIN"},
+					},
+				},
+				Linux: &spec.Linux{
+					Namespaces: []spec.LinuxNamespace{
+						{Type: spec.IPCNamespace},
+						{Type: spec.NetworkNamespace},
+						{Type: spec.PIDNamespace},
+						{Type: spec.UTSNamespace},
+					},
+					Resources: &spec.LinuxResources{
+						Memory: &spec.LinuxMemory{Limit: &memory},
+						CPU:    &spec.LinuxCPU{Quota: &quota, Period: &period},
+					},
+				},
+			},
+		}
+		ctrConfig.ID = "0123456789abcdef"
+		ctrConfig.Name = "web"
+		ctrConfig.RawImageName = "quay.io/example/web:latest"
+		ctrConfig.Command = []string{"httpd", "-f"}
+		ctrConfig.Labels = map[string]string{
+			"io.containers.autoupdate": "registry",
+			"from-image":               "yes",
+			"app":                      "web",
+		}
+		ctrConfig.NetMode = namespaces.NetworkMode("bridge")
+		ctrConfig.Networks = map[string]types.PerNetworkOptions{
+			"podman": {InterfaceName: "eth0"},
+		}
+		ctrConfig.PortMappings = []types.PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+			{HostIP: "127.0.0.1", HostPort: 5353, ContainerPort: 53, Protocol: "tcp,udp"},
+		}
+		ctrConfig.StopSignal = 15
+		ctrConfig.StopTimeout = 10
+		ctrConfig.LogDriver = "journald"
+		ctrConfig.RestartPolicy = "always"
+		return ctrConfig
+	}
+	image := &ociv1.ImageConfig{
+		Cmd:    []string{"httpd", "-f"},
+		Env:    []string{"IMAGE=env"},
+		Labels: map[string]string{"from-image": "yes"},
+	}
+
+	tests := []struct {
+		name        string
+		update      func(info *quadletContainerInfo)
+		expected    string
+		unsupported []string
+	}{
+		{
+			"defaults",
+			func(info *quadletContainerInfo) {},
+			`[Container]
+Image=quay.io/example/web:latest
+ContainerName=web
+Environment="FOO=a b"
+AutoUpdate=registry
+Label=app=web
+PublishPort=8080:80
+PublishPort=127.0.0.1:5353:53
+PublishPort=127.0.0.1:5353:53/udp
+WorkingDir=/srv
+AddCapability=CAP_NET_ADMIN
+DropCapability=CAP_KILL
+Memory=104857600
+CPUQuota=50%
+
+[Service]
+Restart=always
+
+[Install]
+WantedBy=default.target
+`,
+			nil,
+		},
+		{
+			"overridden",
+			func(info *quadletContainerInfo) {
+				info.Config.Entrypoint = []string{"/bin/sh", "-c"}
+				info.Config.Command = []string{"echo hello"}
+				info.Config.Networks = map[string]types.PerNetworkOptions{
+					"backend": {StaticIPs: []net.IP{net.ParseIP("10.89.0.5")}, Aliases: []string{"web", "api"}},
+				}
+				info.Config.PortMappings = nil
+				info.Config.User = "nginx"
+				info.Config.StopSignal = 2
+				info.Config.RestartPolicy = ""
+				info.Config.Spec.Process.Terminal = true
+				info.Config.Spec.Linux.Namespaces = info.Config.Spec.Linux.Namespaces[:2]
+				info.Config.Spec.Linux.Resources = nil
+				info.Config.Spec.Process.Capabilities = nil
+				info.NamedVolumes = []*libpod.ContainerNamedVolume{
+					{Name: "data", Dest: "/data", Options: []string{"ro", "nosuid", "nodev", "rbind"}},
+				}
+				info.Mounts = []spec.Mount{
+					{Type: "bind", Source: "/etc/web", Destination: "/etc/web", Options: []string{"z", "rw", "rbind", "rprivate"}},
+					{Type: "devpts", Source: "devpts", Destination: "/dev/pts2"},
+				}
+			},
+			`[Container]
+Image=quay.io/example/web:latest
+ContainerName=web
+Entrypoint=["/bin/sh","-c"]
+Exec="echo hello"
+Environment="FOO=a b"
+AutoUpdate=registry
+Label=app=web
+Network=backend:ip=10.89.0.5,alias=api
+WorkingDir=/srv
+Volume=data:/data:ro
+Volume=/etc/web:/etc/web:z
+StopSignal=SIGINT
+PodmanArgs=--pid=host --uts=host --user=nginx
+
+[Install]
+WantedBy=default.target
+`,
+			[]string{
+				"web.container: cannot express the devpts mount at /dev/pts2",
+				"web.container: cannot express --tty",
+			},
+		},
+		{
+			"health probe",
+			func(info *quadletContainerInfo) {
+				info.Config.PortMappings = nil
+				info.Config.RestartPolicy = ""
+				info.Config.HealthCheckConfig = &manifest.Schema2HealthConfig{
+					Test:     []string{"HTTP-GET", "http://localhost:80/healthz", "status=204", "header=X-Token: secret"},
+					Interval: 10 * time.Second,
+					Timeout:  30 * time.Second,
+					Retries:  3,
+				}
+			},
+			`[Container]
+Image=quay.io/example/web:latest
+ContainerName=web
+Environment="FOO=a b"
+AutoUpdate=registry
+Label=app=web
+WorkingDir=/srv
+AddCapability=CAP_NET_ADMIN
+DropCapability=CAP_KILL
+Memory=104857600
+CPUQuota=50%
+HealthHTTP=http://localhost:80/healthz
+HealthHTTPStatus=204
+HealthHTTPHeader=X-Token: secret
+HealthInterval=10s
+
+[Install]
+WantedBy=default.target
+`,
+			nil,
+		},
+		{
+			"pod member",
+			func(info *quadletContainerInfo) {
+				info.PodName = "app"
+				info.Config.PortMappings = nil
+				info.Config.RestartPolicy = ""
+				info.PodMounts = map[string]bool{"/shared": true}
+				info.NamedVolumes = []*libpod.ContainerNamedVolume{
+					{Name: "shared", Dest: "/shared"},
+				}
+			},
+			`[Container]
+Image=quay.io/example/web:latest
+ContainerName=web
+Pod=app.pod
+Environment="FOO=a b"
+AutoUpdate=registry
+Label=app=web
+WorkingDir=/srv
+AddCapability=CAP_NET_ADMIN
+DropCapability=CAP_KILL
+Memory=104857600
+CPUQuota=50%
+`,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			info := &quadletContainerInfo{
+				Config:   newConfig(),
+				Image:    image,
+				Defaults: defaults,
+			}
+			test.update(info)
+			report := &quadletReport{}
+			name, unit := containerQuadlet(info, report)
+			assert.Equal(t, "web.container", name)
+			content, err := unit.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, content)
+			assert.Equal(t, test.unsupported, report.unsupported)
+		})
+	}
+}
+
+func TestVolumeQuadlet(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		options  map[string]string
+		labels   map[string]string
+		expected string
+	}{
+		{
+			"default",
+			"local",
+			nil,
+			nil,
+			`[Volume]
+VolumeName=data
+`,
+		},
+		{
+			"device",
+			"local",
+			map[string]string{"device": "/dev/sdb1", "type": "ext4", "o": "uid=1000,noatime", "UID": "1000", "nocopy": ""},
+			map[string]string{"app": "db"},
+			`[Volume]
+VolumeName=data
+Device=/dev/sdb1
+Type=ext4
+User=1000
+Options=noatime
+Copy=false
+Label=app=db
+`,
+		},
+		{
+			"plugin",
+			"myplugin",
+			map[string]string{"o": "size=10G", "SIZE": "10G", "custom": "value"},
+			nil,
+			`[Volume]
+VolumeName=data
+PodmanArgs=--driver=myplugin --opt=o=size=10G --opt=custom=value
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		test := tt
+		t.Run(test.name, func(t *testing.T) {
+			name, unit := volumeQuadlet("data", test.driver, test.options, test.labels)
+			assert.Equal(t, "data.volume", name)
+			content, err := unit.ToString()
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, content)
+		})
+	}
+}
+
+func TestNetworkQuadlet(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("10.89.1.0/24")
+	require.NoError(t, err)
+	network := &types.Network{
+		Name:   "backend",
+		Driver: types.BridgeNetworkDriver,
+		Subnets: []types.Subnet{
+			{
+				Subnet:     types.IPNet{IPNet: *subnet},
+				Gateway:    net.ParseIP("10.89.1.1"),
+				LeaseRange: &types.LeaseRange{StartIP: net.ParseIP("10.89.1.100")},
+			},
+		},
+		Internal:   true,
+		DNSEnabled: false,
+		Labels:     map[string]string{"app": "web"},
+		Options:    map[string]string{"mtu": "1400"},
+		IPAMOptions: map[string]string{
+			types.Driver: types.HostLocalIPAMDriver,
+		},
+	}
+
+	report := &quadletReport{}
+	name, unit := networkQuadlet(network, report)
+	assert.Equal(t, "backend.network", name)
+	content, err := unit.ToString()
+	require.NoError(t, err)
+	assert.Equal(t, `[Network]
+NetworkName=backend
+Subnet=10.89.1.0/24
+Gateway=10.89.1.1
+DisableDNS=true
+Internal=true
+Options=mtu=1400
+Label=app=web
+`, content)
+	assert.Equal(t, []string{"backend.network: cannot express the IP range of the subnet 10.89.1.0/24"}, report.unsupported)
+}
+
+func TestPodQuadlet(t *testing.T) {
+	infraConfig := &libpod.ContainerConfig{Spec: &spec.Spec{}}
+	infraConfig.NetMode = namespaces.NetworkMode("bridge")
+	infraConfig.Networks = map[string]types.PerNetworkOptions{"podman": {}}
+	infraConfig.PortMappings = []types.PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}}
+	defaults := &config.Config{}
+	defaults.Network.DefaultNetwork = "podman"
+
+	info := &quadletPodInfo{
+		Config: &libpod.PodConfig{
+			Name:      "app",
+			Labels:    map[string]string{"tier": "frontend"},
+			UsePodIPC: true,
+			UsePodNet: true,
+			UsePodPID: true,
+			UsePodUTS: true,
+		},
+		Infra: &quadletContainerInfo{
+			Config:   infraConfig,
+			Defaults: defaults,
+			NamedVolumes: []*libpod.ContainerNamedVolume{
+				{Name: "shared", Dest: "/shared"},
+			},
+		},
+	}
+
+	report := &quadletReport{}
+	name, unit := podQuadlet(info, report)
+	assert.Equal(t, "app.pod", name)
+	content, err := unit.ToString()
+	require.NoError(t, err)
+	assert.Equal(t, `[Pod]
+PodName=app
+PublishPort=8080:80
+Volume=shared:/shared
+PodmanArgs=--share=ipc,net,pid,uts --label=tier=frontend
+
+[Install]
+WantedBy=default.target
+`, content)
+	assert.Empty(t, report.unsupported)
+}
@@ -26,6 +26,10 @@ type unitLine struct {
 	key       string
 	value     string
 	isComment bool
+
+	// Where the line was parsed from, if it was parsed
+	path   string
+	lineNr int
 }
 
 type unitGroup struct {
@@ -64,7 +68,10 @@ func (l *unitLine) set(value string) {
 }
 
 func (l *unitLine) dup() *unitLine {
-	return newUnitLine(l.key, l.value, l.isComment)
+	d := newUnitLine(l.key, l.value, l.isComment)
+	d.path = l.path
+	d.lineNr = l.lineNr
+	return d
 }
 
 func (l *unitLine) isKey(key string) bool {
@@ -182,7 +189,10 @@ func (f *UnitFile) ensureGroup(groupName string) *unitGroup {
 	return g
 }
 
-func (f *UnitFile) merge(source *UnitFile) {
+// Merge the groups of another unit file into this one. Lines of groups
+// existing in both files are appended, so later keys override or extend
+// (or, with an empty value, reset) earlier ones as with systemd drop-ins.
+func (f *UnitFile) Merge(source *UnitFile) {
 	for _, srcGroup := range source.groups {
 		group := f.ensureGroup(srcGroup.name)
 		group.merge(srcGroup)
@@ -193,7 +203,7 @@ func (f *UnitFile) merge(source *UnitFile) {
 func (f *UnitFile) Dup() *UnitFile {
 	copy := NewUnitFile()
 
-	copy.merge(f)
+	copy.Merge(f)
 	copy.Filename = f.Filename
 	return copy
 }
@@ -336,7 +346,10 @@ func (p *UnitFileParser) parseKeyValuePair(line string) error {
 
 	p.flushPendingComments(false)
 
-	p.currentGroup.add(key, value)
+	l := newUnitLine(key, value, false)
+	l.path = p.file.Path
+	l.lineNr = p.lineNr
+	p.currentGroup.addLine(l)
 
 	return nil
 }
@@ -587,6 +600,23 @@ func (f *UnitFile) LookupLastRaw(groupName string, key string) (string, bool) {
 	return line.value, true
 }
 
+// Look up where the last instance of the named key in the group was
+// parsed from. The path is empty if the unit file was not loaded from
+// disk, and ok is false if the key was not parsed from a file at all.
+func (f *UnitFile) LookupPosition(groupName string, key string) (string, int, bool) {
+	g, ok := f.groupByName[groupName]
+	if !ok {
+		return "", 0, false
+	}
+
+	line := g.findLast(key)
+	if line == nil || line.lineNr == 0 {
+		return "", 0, false
+	}
+
+	return line.path, line.lineNr, true
+}
+
 func (f *UnitFile) HasKey(groupName string, key string) bool {
 	_, ok := f.LookupLastRaw(groupName, key)
 	return ok
@@ -243,3 +243,82 @@ func TestRanges_Roundtrip(t *testing.T) {
 		assert.Equal(t, sample, asStr)
 	}
 }
+
+func TestUnitFile_Merge(t *testing.T) {
+	base := NewUnitFile()
+	err := base.Parse(`[Container]
+Image=localhost/imagename
+Volume=/a:/a
+`)
+	assert.Nil(t, err)
+
+	dropin := NewUnitFile()
+	err = dropin.Parse(`[Container]
+Image=localhost/other
+Volume=/b:/b
+
+[Service]
+Restart=always
+`)
+	assert.Nil(t, err)
+
+	base.Merge(dropin)
+
+	image, _ := base.Lookup("Container", "Image")
+	assert.Equal(t, "localhost/other", image)
+	assert.Equal(t, []string{"/a:/a", "/b:/b"}, base.LookupAll("Container", "Volume"))
+	restart, _ := base.Lookup("Service", "Restart")
+	assert.Equal(t, "always", restart)
+
+	reset := NewUnitFile()
+	err = reset.Parse(`[Container]
+Volume=
+Volume=/c:/c
+`)
+	assert.Nil(t, err)
+
+	base.Merge(reset)
+	assert.Equal(t, []string{"/c:/c"}, base.LookupAll("Container", "Volume"))
+}
+
+func TestUnitFile_LookupPosition(t *testing.T) {
+	f := NewUnitFile()
+	f.Path = "/etc/containers/systemd/foo.container"
+	err := f.Parse(`# comment
+[Container]
+Image=localhost/imagename
+Exec=/bin/sh \
+  -c true
+
+[Service]
+Restart=always
+Restart=no
+`)
+	assert.Nil(t, err)
+
+	path, line, ok := f.LookupPosition("Container", "Image")
+	assert.True(t, ok)
+	assert.Equal(t, f.Path, path)
+	assert.Equal(t, 3, line)
+
+	_, line, _ = f.LookupPosition("Service", "Restart")
+	assert.Equal(t, 9, line)
+
+	dropin := NewUnitFile()
+	dropin.Path = "/etc/containers/systemd/foo.container.d/10-image.conf"
+	err = dropin.Parse(`[Container]
+Image=localhost/other
+`)
+	assert.Nil(t, err)
+	f.Merge(dropin)
+
+	path, line, _ = f.LookupPosition("Container", "Image")
+	assert.Equal(t, dropin.Path, path)
+	assert.Equal(t, 2, line)
+
+	f.Set("Container", "Label", "foo=bar")
+	_, _, ok = f.LookupPosition("Container", "Label")
+	assert.False(t, ok)
+	_, _, ok = f.LookupPosition("Missing", "Image")
+	assert.False(t, ok)
+}
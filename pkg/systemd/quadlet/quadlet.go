@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/containers/podman/v4/pkg/systemd/parser"
@@ -18,37 +19,66 @@ const (
 	UnitDirDistro = "/usr/share/containers/systemd"
 
 	// Names of commonly used systemd/quadlet group names
+	BuildGroup      = "Build"
 	ContainerGroup  = "Container"
+	ImageGroup      = "Image"
 	InstallGroup    = "Install"
 	KubeGroup       = "Kube"
 	NetworkGroup    = "Network"
+	PodGroup        = "Pod"
 	ServiceGroup    = "Service"
 	UnitGroup       = "Unit"
 	VolumeGroup     = "Volume"
+	XBuildGroup     = "X-Build"
 	XContainerGroup = "X-Container"
+	XImageGroup     = "X-Image"
 	XKubeGroup      = "X-Kube"
 	XNetworkGroup   = "X-Network"
+	XPodGroup       = "X-Pod"
 	XVolumeGroup    = "X-Volume"
+
+	// Names of commonly used systemd service keys
+	ServiceKeyWorkingDirectory = "WorkingDirectory"
 )
 
 // All the supported quadlet keys
 const (
 	KeyAddCapability         = "AddCapability"
 	KeyAddDevice             = "AddDevice"
+	KeyAddHost               = "AddHost"
+	KeyAllTags               = "AllTags"
 	KeyAnnotation            = "Annotation"
+	KeyArch                  = "Arch"
+	KeyAuthFile              = "AuthFile"
 	KeyAutoUpdate            = "AutoUpdate"
+	KeyCPUQuota              = "CPUQuota"
+	KeyCPUWeight             = "CPUWeight"
+	KeyCertDir               = "CertDir"
 	KeyConfigMap             = "ConfigMap"
 	KeyContainerName         = "ContainerName"
 	KeyCopy                  = "Copy"
+	KeyDNS                   = "DNS"
+	KeyDNSOption             = "DNSOption"
+	KeyDNSSearch             = "DNSSearch"
+	KeyDecryptionKey         = "DecryptionKey"
 	KeyDevice                = "Device"
 	KeyDropCapability        = "DropCapability"
+	KeyEntrypoint            = "Entrypoint"
 	KeyEnvironment           = "Environment"
 	KeyEnvironmentFile       = "EnvironmentFile"
 	KeyEnvironmentHost       = "EnvironmentHost"
 	KeyExec                  = "Exec"
 	KeyExposeHostPort        = "ExposeHostPort"
+	KeyFile                  = "File"
+	KeyGlobalArgs            = "GlobalArgs"
 	KeyGroup                 = "Group"
+	KeyGroupAdd              = "GroupAdd"
 	KeyHealthCmd             = "HealthCmd"
+	KeyHealthGRPC            = "HealthGRPC"
+	KeyHealthGRPCService     = "HealthGRPCService"
+	KeyHealthHTTP            = "HealthHTTP"
+	KeyHealthHTTPHeader      = "HealthHTTPHeader"
+	KeyHealthHTTPStatus      = "HealthHTTPStatus"
 	KeyHealthInterval        = "HealthInterval"
 	KeyHealthOnFailure       = "HealthOnFailure"
 	KeyHealthRetries         = "HealthRetries"
@@ -58,15 +88,19 @@ const (
 	KeyHealthStartupRetries  = "HealthStartupRetries"
 	KeyHealthStartupSuccess  = "HealthStartupSuccess"
 	KeyHealthStartupTimeout  = "HealthStartupTimeout"
+	KeyHealthTCP             = "HealthTCP"
 	KeyHealthTimeout         = "HealthTimeout"
 	KeyHostName              = "HostName"
 	KeyImage                 = "Image"
+	KeyImageTag              = "ImageTag"
+	KeyInfraImage            = "InfraImage"
 	KeyIP                    = "IP"
 	KeyIP6                   = "IP6"
 	KeyExitCodePropagation   = "ExitCodePropagation"
 	KeyLabel                 = "Label"
 	KeyLogDriver             = "LogDriver"
 	KeyMask                  = "Mask"
+	KeyMemory                = "Memory"
 	KeyMount                 = "Mount"
 	KeyNetwork               = "Network"
 	KeyNetworkDisableDNS     = "DisableDNS"
@@ -76,11 +110,16 @@ const (
 	KeyNetworkIPRange        = "IPRange"
 	KeyNetworkIPv6           = "IPv6"
 	KeyNetworkInternal       = "Internal"
+	KeyNetworkName           = "NetworkName"
 	KeyNetworkOptions        = "Options"
 	KeyNetworkSubnet         = "Subnet"
 	KeyNoNewPrivileges       = "NoNewPrivileges"
 	KeyNotify                = "Notify"
 	KeyOptions               = "Options"
+	KeyOS                    = "OS"
+	KeyPidsLimit             = "PidsLimit"
+	KeyPod                   = "Pod"
+	KeyPodName               = "PodName"
 	KeyPodmanArgs            = "PodmanArgs"
 	KeyPublishPort           = "PublishPort"
 	KeyPull                  = "Pull"
@@ -98,15 +137,24 @@ const (
 	KeySecurityLabelNested   = "SecurityLabelNested"
 	KeySecurityLabelType     = "SecurityLabelType"
 	KeySecret                = "Secret"
+	KeySetWorkingDirectory   = "SetWorkingDirectory"
+	KeyShmSize               = "ShmSize"
+	KeyStopSignal            = "StopSignal"
+	KeyStopTimeout           = "StopTimeout"
 	KeySysctl                = "Sysctl"
+	KeyTarget                = "Target"
 	KeyTimezone              = "Timezone"
+	KeyTLSVerify             = "TLSVerify"
 	KeyTmpfs                 = "Tmpfs"
 	KeyType                  = "Type"
+	KeyUlimit                = "Ulimit"
 	KeyUnmask                = "Unmask"
 	KeyUser                  = "User"
 	KeyUserNS                = "UserNS"
+	KeyVariant               = "Variant"
 	KeyVolatileTmp           = "VolatileTmp"
 	KeyVolume                = "Volume"
+	KeyVolumeName            = "VolumeName"
 	KeyWorkingDir            = "WorkingDir"
 	KeyYaml                  = "Yaml"
 )
@@ -118,17 +166,30 @@ var (
 	supportedContainerKeys = map[string]bool{
 		KeyAddCapability:         true,
 		KeyAddDevice:             true,
+		KeyAddHost:               true,
 		KeyAnnotation:            true,
 		KeyAutoUpdate:            true,
+		KeyCPUQuota:              true,
+		KeyCPUWeight:             true,
 		KeyContainerName:         true,
+		KeyDNS:                   true,
+		KeyDNSOption:             true,
+		KeyDNSSearch:             true,
 		KeyDropCapability:        true,
+		KeyEntrypoint:            true,
 		KeyEnvironment:           true,
 		KeyEnvironmentFile:       true,
 		KeyEnvironmentHost:       true,
 		KeyExec:                  true,
 		KeyExposeHostPort:        true,
 		KeyGroup:                 true,
+		KeyGroupAdd:              true,
 		KeyHealthCmd:             true,
+		KeyHealthGRPC:            true,
+		KeyHealthGRPCService:     true,
+		KeyHealthHTTP:            true,
+		KeyHealthHTTPHeader:      true,
+		KeyHealthHTTPStatus:      true,
 		KeyHealthInterval:        true,
 		KeyHealthOnFailure:       true,
 		KeyHealthRetries:         true,
@@ -138,6 +199,7 @@ var (
 		KeyHealthStartupRetries:  true,
 		KeyHealthStartupSuccess:  true,
 		KeyHealthStartupTimeout:  true,
+		KeyHealthTCP:             true,
 		KeyHealthTimeout:         true,
 		KeyHostName:              true,
 		KeyIP6:                   true,
@@ -146,10 +208,13 @@ var (
 		KeyLabel:                 true,
 		KeyLogDriver:             true,
 		KeyMask:                  true,
+		KeyMemory:                true,
 		KeyMount:                 true,
 		KeyNetwork:               true,
 		KeyNoNewPrivileges:       true,
 		KeyNotify:                true,
+		KeyPidsLimit:             true,
+		KeyPod:                   true,
 		KeyPodmanArgs:            true,
 		KeyPublishPort:           true,
 		KeyPull:                  true,
@@ -167,9 +232,13 @@ var (
 		KeySecurityLabelLevel:    true,
 		KeySecurityLabelNested:   true,
 		KeySecurityLabelType:     true,
+		KeyShmSize:               true,
+		KeyStopSignal:            true,
+		KeyStopTimeout:           true,
 		KeySysctl:                true,
 		KeyTimezone:              true,
 		KeyTmpfs:                 true,
+		KeyUlimit:                true,
 		KeyUnmask:                true,
 		KeyUser:                  true,
 		KeyUserNS:                true,
@@ -188,6 +257,7 @@ var (
 		KeyPodmanArgs: true,
 		KeyType:       true,
 		KeyUser:       true,
+		KeyVolumeName: true,
 	}
 
 	// Supported keys in "Network" group
@@ -200,6 +270,7 @@ var (
 		KeyNetworkIPRange:    true,
 		KeyNetworkIPv6:       true,
 		KeyNetworkInternal:   true,
+		KeyNetworkName:       true,
 		KeyNetworkOptions:    true,
 		KeyNetworkSubnet:     true,
 		KeyPodmanArgs:        true,
@@ -220,8 +291,56 @@ var (
 		KeyUserNS:              true,
 		KeyYaml:                true,
 	}
+
+	// Supported keys in "Build" group
+	supportedBuildKeys = map[string]bool{
+		KeyEnvironment:         true,
+		KeyFile:                true,
+		KeyGlobalArgs:          true,
+		KeyImageTag:            true,
+		KeyLabel:               true,
+		KeyNetwork:             true,
+		KeyPodmanArgs:          true,
+		KeyPull:                true,
+		KeySecret:              true,
+		KeySetWorkingDirectory: true,
+		KeyTarget:              true,
+		KeyVolume:              true,
+	}
+
+	// Supported keys in "Image" group
+	supportedImageKeys = map[string]bool{
+		KeyAllTags:       true,
+		KeyArch:          true,
+		KeyAuthFile:      true,
+		KeyCertDir:       true,
+		KeyDecryptionKey: true,
+		KeyImage:         true,
+		KeyOS:            true,
+		KeyPodmanArgs:    true,
+		KeyTLSVerify:     true,
+		KeyVariant:       true,
+	}
+
+	// Supported keys in "Pod" group
+	supportedPodKeys = map[string]bool{
+		KeyInfraImage:  true,
+		KeyNetwork:     true,
+		KeyPodName:     true,
+		KeyPodmanArgs:  true,
+		KeyPublishPort: true,
+		KeyUserNS:      true,
+		KeyVolume:      true,
+	}
 )
 
+// PodInfo describes a quadlet pod unit and the container services that are
+// members of it, so the pod service can be ordered before them.
+type PodInfo struct {
+	ServiceName string
+	Containers  []string
+}
+
 func replaceExtension(name string, extension string, extraPrefix string, extraSuffix string) string {
 	baseName := name
 
@@ -230,10 +349,72 @@ func replaceExtension(name string, extension string, extraPrefix string, extraSu
 		baseName = name[:dot]
 	}
 
+	// For template and instance units the suffix goes before the '@',
+	// so that e.g. foo@bar.volume becomes foo-volume@bar.service
+	if at := strings.IndexByte(baseName, '@'); at >= 0 {
+		return extraPrefix + baseName[:at] + extraSuffix + baseName[at:] + extension
+	}
+
 	return extraPrefix + baseName + extraSuffix + extension
 }
 
+// Returns true if the unit name is a systemd template, i.e. foo@.container
+func isTemplateUnit(name string) bool {
+	baseName := replaceExtension(name, "", "", "")
+	return strings.HasSuffix(baseName, "@")
+}
+
+// Returns the template name of a template or instance unit, i.e. foo@.pod for
+// foo@bar.pod, or the name itself for other units
+func getTemplateName(name string) string {
+	baseName := replaceExtension(name, "", "", "")
+	at := strings.IndexByte(baseName, '@')
+	if at < 0 {
+		return name
+	}
+	return baseName[:at+1] + strings.TrimPrefix(name, baseName)
+}
+
+// Returns the default name of the Podman resource (e.g. volume or network)
+// created for a quadlet unit, i.e. systemd-$name. As Podman names can't
+// contain '@', template and instance units use systemd-$name_$instance,
+// where the instance of a template defaults to the %i specifier.
+func getDefaultResourceName(name string) string {
+	baseName := replaceExtension(name, "", "", "")
+	if template, instance, found := strings.Cut(baseName, "@"); found {
+		if len(instance) == 0 {
+			instance = "%i"
+		}
+		baseName = template + "_" + instance
+	}
+	return "systemd-" + baseName
+}
+
+// Returns the name of the Podman resource created for a .volume or .network
+// unit: the value of the key, or the default name if it is not set.
+func getResourceName(unitFile *parser.UnitFile, groupName, key, name string) string {
+	if resourceName, ok := unitFile.Lookup(groupName, key); ok && len(resourceName) > 0 {
+		return resourceName
+	}
+	return getDefaultResourceName(name)
+}
+
+// Returns the name of the Podman resource created for the referenced
+// .volume or .network unit.  Units not in names, e.g. instances of template
+// units, create a resource with the default name.
+func lookupResourceName(names map[string]string, name string) string {
+	if resourceName, ok := names[name]; ok {
+		return resourceName
+	}
+	return getDefaultResourceName(name)
+}
+
 func isPortRange(port string) bool {
+	// Ports using systemd specifiers (e.g. 80%i in template units) can
+	// only be validated once systemd expands them
+	if containsSystemdSpecifier(port) {
+		return true
+	}
 	return validPortRange.MatchString(port)
 }
 
@@ -241,12 +422,22 @@ func checkForUnknownKeys(unit *parser.UnitFile, groupName string, supportedKeys
 	keys := unit.ListKeys(groupName)
 	for _, key := range keys {
 		if !supportedKeys[key] {
-			return fmt.Errorf("unsupported key '%s' in group '%s' in %s", key, groupName, unit.Path)
+			return fmt.Errorf("unsupported key '%s' in group '%s' in %s", key, groupName, keyPosition(unit, groupName, key))
 		}
 	}
 	return nil
 }
 
+// Returns where the key was set as path:line, which for keys set in drop-ins
+// is the drop-in. Falls back to the path of the unit if the line is unknown.
+func keyPosition(unit *parser.UnitFile, groupName string, key string) string {
+	path, line, ok := unit.LookupPosition(groupName, key)
+	if !ok || len(path) == 0 {
+		return unit.Path
+	}
+	return fmt.Sprintf("%s:%d", path, line)
+}
+
 func splitPorts(ports string) []string {
 	parts := make([]string, 0)
 
@@ -295,7 +486,7 @@ func usernsOpts(kind string, opts []string) string {
 // service file (unit file with Service group) based on the options in the
 // Container group.
 // The original Container group is kept around as X-Container.
-func ConvertContainer(container *parser.UnitFile, isUser bool) (*parser.UnitFile, error) {
+func ConvertContainer(container *parser.UnitFile, names map[string]string, isUser bool, podsInfoMap map[string]*PodInfo) (*parser.UnitFile, error) {
 	service := container.Dup()
 	service.Filename = replaceExtension(container.Filename, ".service", "", "")
 
@@ -320,10 +511,22 @@ func ConvertContainer(container *parser.UnitFile, isUser bool) (*parser.UnitFile
 		return nil, fmt.Errorf("the Image And Rootfs keys conflict can not be specified together")
 	}
 
+	if len(image) > 0 {
+		var err error
+		if image, err = handleImageSource(image, service, names); err != nil {
+			return nil, err
+		}
+	}
+
 	containerName, ok := container.Lookup(ContainerGroup, KeyContainerName)
 	if !ok || len(containerName) == 0 {
 		// By default, We want to name the container by the service name
-		containerName = "systemd-%N"
+		if isTemplateUnit(container.Filename) {
+			// Podman names can't contain '@', so name instances by prefix and instance
+			containerName = "systemd-%p_%i"
+		} else {
+			containerName = "systemd-%N"
+		}
 	}
 
 	// Set PODMAN_SYSTEMD_UNIT so that podman auto-update can restart the service.
@@ -333,7 +536,7 @@ func ConvertContainer(container *parser.UnitFile, isUser bool) (*parser.UnitFile
 	killMode, ok := service.Lookup(ServiceGroup, "KillMode")
 	if !ok || !(killMode == "mixed" || killMode == "control-group") {
 		if ok {
-			return nil, fmt.Errorf("invalid KillMode '%s'", killMode)
+			return nil, fmt.Errorf("invalid KillMode '%s' in %s", killMode, keyPosition(service, ServiceGroup, "KillMode"))
 		}
 
 		// We default to mixed instead of control-group, because it lets conmon do its thing
@@ -381,7 +584,11 @@ func ConvertContainer(container *parser.UnitFile, isUser bool) (*parser.UnitFile
 		podman.addf("--tz=%s", timezone)
 	}
 
-	addNetworks(container, ContainerGroup, service, podman)
+	addNetworks(container, ContainerGroup, service, names, podman)
+
+	if err := handlePod(container, service, ContainerGroup, podsInfoMap, podman); err != nil {
+		return nil, err
+	}
 
 	// Run with a pid1 init to reap zombies by default (as most apps don't do that)
 	runInit, ok := container.LookupBoolean(ContainerGroup, KeyRunInit)
@@ -391,7 +598,7 @@ func ConvertContainer(container *parser.UnitFile, isUser bool) (*parser.UnitFile
 
 	serviceType, ok := service.Lookup(ServiceGroup, "Type")
 	if ok && serviceType != "notify" && serviceType != "oneshot" {
-		return nil, fmt.Errorf("invalid service Type '%s'", serviceType)
+		return nil, fmt.Errorf("invalid service Type '%s' in %s", serviceType, keyPosition(service, ServiceGroup, "Type"))
 	}
 
 	if serviceType != "oneshot" {
@@ -531,37 +738,8 @@ func ConvertContainer(container *parser.UnitFile, isUser bool) (*parser.UnitFile
 		podman.add("--tmpfs", tmpfs)
 	}
 
-	volumes := container.LookupAll(ContainerGroup, KeyVolume)
-	for _, volume := range volumes {
-		parts := strings.SplitN(volume, ":", 3)
-
-		source := ""
-		var dest string
-		options := ""
-		if len(parts) >= 2 {
-			source = parts[0]
-			dest = parts[1]
-		} else {
-			dest = parts[0]
-		}
-		if len(parts) >= 3 {
-			options = ":" + parts[2]
-		}
-
-		if source != "" {
-			var err error
-			source, err = handleStorageSource(container, service, source)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		podman.add("-v")
-		if source == "" {
-			podman.add(dest)
-		} else {
-			podman.addf("%s:%s%s", source, dest, options)
-		}
+	if err := addVolumes(container, service, ContainerGroup, names, podman); err != nil {
+		return nil, err
 	}
 
 	update, ok := container.Lookup(ContainerGroup, KeyAutoUpdate)
@@ -644,9 +822,9 @@ func ConvertContainer(container *parser.UnitFile, isUser bool) (*parser.UnitFile
 			if paramType == "volume" || paramType == "bind" {
 				var err error
 				if paramSource, ok := paramsMap["source"]; ok {
-					paramsMap["source"], err = handleStorageSource(container, service, paramSource)
+					paramsMap["source"], err = handleStorageSource(container, service, paramSource, names)
 				} else if paramSource, ok = paramsMap["src"]; ok {
-					paramsMap["src"], err = handleStorageSource(container, service, paramSource)
+					paramsMap["src"], err = handleStorageSource(container, service, paramSource, names)
 				}
 				if err != nil {
 					return nil, err
@@ -670,6 +848,39 @@ func ConvertContainer(container *parser.UnitFile, isUser bool) (*parser.UnitFile
 		podman.add("--hostname", hostname)
 	}
 
+	addHosts := container.LookupAllStrv(ContainerGroup, KeyAddHost)
+	for _, addHost := range addHosts {
+		podman.addf("--add-host=%s", addHost)
+	}
+
+	dnsServers := container.LookupAllStrv(ContainerGroup, KeyDNS)
+	for _, dns := range dnsServers {
+		podman.addf("--dns=%s", dns)
+	}
+
+	dnsSearches := container.LookupAllStrv(ContainerGroup, KeyDNSSearch)
+	for _, dnsSearch := range dnsSearches {
+		podman.addf("--dns-search=%s", dnsSearch)
+	}
+
+	dnsOptions := container.LookupAllStrv(ContainerGroup, KeyDNSOption)
+	for _, dnsOption := range dnsOptions {
+		podman.addf("--dns-option=%s", dnsOption)
+	}
+
+	groupAdds := container.LookupAllStrv(ContainerGroup, KeyGroupAdd)
+	for _, groupAdd := range groupAdds {
+		podman.addf("--group-add=%s", groupAdd)
+	}
+
+	if err := handleResourceLimits(container, ContainerGroup, podman); err != nil {
+		return nil, err
+	}
+
+	lookupAndAddString(container, ContainerGroup, KeyStopSignal, "--stop-signal", podman)
+	lookupAndAddString(container, ContainerGroup, KeyStopTimeout, "--stop-timeout", podman)
+	lookupAndAddString(container, ContainerGroup, KeyEntrypoint, "--entrypoint", podman)
+
 	pull, ok := container.Lookup(ContainerGroup, KeyPull)
 	if ok && len(pull) > 0 {
 		podman.add("--pull", pull)
@@ -708,7 +919,7 @@ func ConvertNetwork(network *parser.UnitFile, name string) (*parser.UnitFile, er
 	/* Rename old Network group to x-Network so that systemd ignores it */
 	service.RenameGroup(NetworkGroup, XNetworkGroup)
 
-	networkName := replaceExtension(name, "", "systemd-", "")
+	networkName := getResourceName(network, NetworkGroup, KeyNetworkName, name)
 
 	// Need the containers filesystem mounted to start podman
 	service.Add(UnitGroup, "RequiresMountsFor", "%t/containers")
@@ -799,7 +1010,7 @@ func ConvertVolume(volume *parser.UnitFile, name string) (*parser.UnitFile, erro
 	/* Rename old Volume group to x-Volume so that systemd ignores it */
 	service.RenameGroup(VolumeGroup, XVolumeGroup)
 
-	volumeName := replaceExtension(name, "", "systemd-", "")
+	volumeName := getResourceName(volume, VolumeGroup, KeyVolumeName, name)
 
 	// Need the containers filesystem mounted to start podman
 	service.Add(UnitGroup, "RequiresMountsFor", "%t/containers")
@@ -887,7 +1098,7 @@ func ConvertVolume(volume *parser.UnitFile, name string) (*parser.UnitFile, erro
 	return service, nil
 }
 
-func ConvertKube(kube *parser.UnitFile, isUser bool) (*parser.UnitFile, error) {
+func ConvertKube(kube *parser.UnitFile, names map[string]string, isUser bool) (*parser.UnitFile, error) {
 	service := kube.Dup()
 	service.Filename = replaceExtension(kube.Filename, ".service", "", "")
 
@@ -916,7 +1127,7 @@ func ConvertKube(kube *parser.UnitFile, isUser bool) (*parser.UnitFile, error) {
 	killMode, ok := service.Lookup(ServiceGroup, "KillMode")
 	if !ok || !(killMode == "mixed" || killMode == "control-group") {
 		if ok {
-			return nil, fmt.Errorf("invalid KillMode '%s'", killMode)
+			return nil, fmt.Errorf("invalid KillMode '%s' in %s", killMode, keyPosition(service, ServiceGroup, "KillMode"))
 		}
 
 		// We default to mixed instead of control-group, because it lets conmon do its thing
@@ -959,7 +1170,7 @@ func ConvertKube(kube *parser.UnitFile, isUser bool) (*parser.UnitFile, error) {
 
 	handleUserNS(kube, KubeGroup, execStart)
 
-	addNetworks(kube, KubeGroup, service, execStart)
+	addNetworks(kube, KubeGroup, service, names, execStart)
 
 	configMaps := kube.LookupAllStrv(KubeGroup, KeyConfigMap)
 	for _, configMap := range configMaps {
@@ -989,6 +1200,291 @@ func ConvertKube(kube *parser.UnitFile, isUser bool) (*parser.UnitFile, error) {
 	return service, nil
 }
 
+// Convert a quadlet image file (unit file with an Image group) to a systemd
+// service file (unit file with Service group) based on the options in the
+// Image group.
+// The original Image group is kept around as X-Image.
+// Also returns the name of the image, so that containers can refer to it.
+func ConvertImage(image *parser.UnitFile) (*parser.UnitFile, string, error) {
+	service := image.Dup()
+	service.Filename = replaceExtension(image.Filename, ".service", "", "-image")
+
+	if image.Path != "" {
+		service.Add(UnitGroup, "SourcePath", image.Path)
+	}
+
+	if err := checkForUnknownKeys(image, ImageGroup, supportedImageKeys); err != nil {
+		return nil, "", err
+	}
+
+	imageName, ok := image.Lookup(ImageGroup, KeyImage)
+	if !ok || len(imageName) == 0 {
+		return nil, "", fmt.Errorf("no Image key specified")
+	}
+
+	/* Rename old Image group to x-Image so that systemd ignores it */
+	service.RenameGroup(ImageGroup, XImageGroup)
+
+	// Need the containers filesystem mounted to start podman
+	service.Add(UnitGroup, "RequiresMountsFor", "%t/containers")
+
+	podman := NewPodmanCmdline("image", "pull")
+
+	stringKeys := [][2]string{
+		{KeyArch, "--arch"},
+		{KeyAuthFile, "--authfile"},
+		{KeyCertDir, "--cert-dir"},
+		{KeyDecryptionKey, "--decryption-key"},
+		{KeyOS, "--os"},
+		{KeyVariant, "--variant"},
+	}
+
+	boolKeys := [][2]string{
+		{KeyAllTags, "--all-tags"},
+		{KeyTLSVerify, "--tls-verify"},
+	}
+
+	for _, keyFlag := range stringKeys {
+		lookupAndAddString(image, ImageGroup, keyFlag[0], keyFlag[1], podman)
+	}
+
+	for _, keyFlag := range boolKeys {
+		lookupAndAddBoolean(image, ImageGroup, keyFlag[0], keyFlag[1], podman)
+	}
+
+	handlePodmanArgs(image, ImageGroup, podman)
+
+	podman.add(imageName)
+
+	service.AddCmdline(ServiceGroup, "ExecStart", podman.Args)
+
+	service.Setv(ServiceGroup,
+		"Type", "oneshot",
+		"RemainAfterExit", "yes",
+
+		// The default syslog identifier is the exec basename (podman) which isn't very useful here
+		"SyslogIdentifier", "%N")
+
+	return service, imageName, nil
+}
+
+// Convert a quadlet build file (unit file with a Build group) to a systemd
+// service file (unit file with Service group) based on the options in the
+// Build group.
+// The original Build group is kept around as X-Build.
+// Also returns the name of the built image, so that containers can refer to it.
+func ConvertBuild(build *parser.UnitFile, names map[string]string) (*parser.UnitFile, string, error) {
+	service := build.Dup()
+	service.Filename = replaceExtension(build.Filename, ".service", "", "-build")
+
+	if build.Path != "" {
+		service.Add(UnitGroup, "SourcePath", build.Path)
+	}
+
+	if err := checkForUnknownKeys(build, BuildGroup, supportedBuildKeys); err != nil {
+		return nil, "", err
+	}
+
+	/* Rename old Build group to X-Build so that systemd ignores it */
+	service.RenameGroup(BuildGroup, XBuildGroup)
+
+	// The first tag is the name containers refer to the image by
+	imageTags := build.LookupAll(BuildGroup, KeyImageTag)
+	if len(imageTags) == 0 {
+		return nil, "", fmt.Errorf("no ImageTag key specified")
+	}
+
+	// Need the containers filesystem mounted to start podman
+	service.Add(UnitGroup, "RequiresMountsFor", "%t/containers")
+
+	podman := NewPodmanCmdline()
+
+	handleGlobalArgs(build, BuildGroup, podman)
+
+	podman.add("build")
+
+	for _, imageTag := range imageTags {
+		podman.addf("--tag=%s", imageTag)
+	}
+
+	if pull, ok := build.Lookup(BuildGroup, KeyPull); ok && len(pull) > 0 {
+		podman.addf("--pull=%s", pull)
+	}
+
+	if target, ok := build.Lookup(BuildGroup, KeyTarget); ok && len(target) > 0 {
+		podman.addf("--target=%s", target)
+	}
+
+	podmanEnv := build.LookupAllKeyVal(BuildGroup, KeyEnvironment)
+	podman.addEnv(podmanEnv)
+
+	labels := build.LookupAllKeyVal(BuildGroup, KeyLabel)
+	podman.addLabels(labels)
+
+	addNetworks(build, BuildGroup, service, names, podman)
+
+	secrets := build.LookupAllArgs(BuildGroup, KeySecret)
+	for _, secret := range secrets {
+		podman.add("--secret", secret)
+	}
+
+	if err := addVolumes(build, service, BuildGroup, names, podman); err != nil {
+		return nil, "", err
+	}
+
+	// In order to build an image locally, we need either a File key pointing directly at a
+	// Containerfile, or a context directory containing all required files.
+	// SetWorkingDirectory= can also be a path, or a URL to either a Containerfile, a Git repo
+	// or an archive.
+	context, err := handleSetWorkingDirectory(build, service)
+	if err != nil {
+		return nil, "", err
+	}
+
+	workingDirectory, _ := service.Lookup(ServiceGroup, ServiceKeyWorkingDirectory)
+	filePath, _ := build.Lookup(BuildGroup, KeyFile)
+	if len(workingDirectory) == 0 && len(filePath) == 0 && len(context) == 0 {
+		return nil, "", fmt.Errorf("neither SetWorkingDirectory, nor File key specified")
+	}
+
+	if len(filePath) > 0 {
+		podman.addf("--file=%s", filePath)
+	}
+
+	handlePodmanArgs(build, BuildGroup, podman)
+
+	// The context or working directory has to be the last argument
+	if len(context) > 0 {
+		podman.add(context)
+	} else if !filepath.IsAbs(filePath) && !isURL(filePath) {
+		// Relative file paths are resolved by podman against the build context
+		if len(workingDirectory) == 0 {
+			return nil, "", fmt.Errorf("relative path in File key requires SetWorkingDirectory key to be set")
+		}
+		podman.add(workingDirectory)
+	}
+
+	service.AddCmdline(ServiceGroup, "ExecStart", podman.Args)
+
+	service.Setv(ServiceGroup,
+		"Type", "oneshot",
+		"RemainAfterExit", "yes",
+
+		// The default syslog identifier is the exec basename (podman) which isn't very useful here
+		"SyslogIdentifier", "%N")
+
+	return service, imageTags[0], nil
+}
+
+// GetPodServiceName returns the name of the service generated for a quadlet
+// pod unit, without the .service suffix, i.e. $name-pod.
+func GetPodServiceName(podUnit *parser.UnitFile) string {
+	return replaceExtension(podUnit.Filename, "", "", "-pod")
+}
+
+// Convert a quadlet pod file (unit file with a Pod group) to a systemd
+// service file (unit file with Service group) based on the options in the
+// Pod group.
+// The original Pod group is kept around as X-Pod.
+func ConvertPod(podUnit *parser.UnitFile, name string, names map[string]string, podsInfoMap map[string]*PodInfo) (*parser.UnitFile, error) {
+	podInfo, ok := podsInfoMap[podUnit.Filename]
+	if !ok {
+		return nil, fmt.Errorf("internal error while processing pod %s", podUnit.Filename)
+	}
+
+	service := podUnit.Dup()
+	service.Filename = podInfo.ServiceName + ".service"
+
+	if podUnit.Path != "" {
+		service.Add(UnitGroup, "SourcePath", podUnit.Path)
+	}
+
+	if err := checkForUnknownKeys(podUnit, PodGroup, supportedPodKeys); err != nil {
+		return nil, err
+	}
+
+	// Rename old Pod group to x-Pod so that systemd ignores it
+	service.RenameGroup(PodGroup, XPodGroup)
+
+	podName, ok := podUnit.Lookup(PodGroup, KeyPodName)
+	if !ok || len(podName) == 0 {
+		// By default, We want to name the pod by the quadlet file name
+		podName = getDefaultResourceName(name)
+	}
+
+	// Need the containers filesystem mounted to start podman
+	service.Add(UnitGroup, "RequiresMountsFor", "%t/containers")
+
+	// The containers are started by the pod service, and stopped with it
+	for _, containerService := range podInfo.Containers {
+		service.Add(UnitGroup, "Wants", containerService)
+		service.Add(UnitGroup, "Before", containerService)
+	}
+
+	if !podUnit.HasKey(ServiceGroup, "SyslogIdentifier") {
+		service.Set(ServiceGroup, "SyslogIdentifier", "%N")
+	}
+
+	execStart := NewPodmanCmdline("pod", "start", "--pod-id-file=%t/%N.pod-id")
+	service.AddCmdline(ServiceGroup, "ExecStart", execStart.Args)
+
+	execStop := NewPodmanCmdline("pod", "stop")
+	execStop.add(
+		"--pod-id-file=%t/%N.pod-id",
+		"--ignore",
+		"--time=10",
+	)
+	service.AddCmdline(ServiceGroup, "ExecStop", execStop.Args)
+
+	execStopPost := NewPodmanCmdline("pod", "rm")
+	execStopPost.add(
+		"--pod-id-file=%t/%N.pod-id",
+		"--ignore",
+		"--force",
+	)
+	service.AddCmdline(ServiceGroup, "ExecStopPost", execStopPost.Args)
+
+	execStartPre := NewPodmanCmdline("pod", "create")
+	execStartPre.add(
+		"--infra-conmon-pidfile=%t/%N.pid",
+		"--pod-id-file=%t/%N.pod-id",
+		"--exit-policy=stop",
+		"--replace",
+	)
+
+	if infraImage, ok := podUnit.Lookup(PodGroup, KeyInfraImage); ok && len(infraImage) > 0 {
+		execStartPre.addf("--infra-image=%s", infraImage)
+	}
+
+	handleUserNS(podUnit, PodGroup, execStartPre)
+
+	addNetworks(podUnit, PodGroup, service, names, execStartPre)
+
+	if err := handlePublishPorts(podUnit, PodGroup, execStartPre); err != nil {
+		return nil, err
+	}
+
+	if err := addVolumes(podUnit, service, PodGroup, names, execStartPre); err != nil {
+		return nil, err
+	}
+
+	execStartPre.addf("--infra-name=%s-infra", podName)
+	execStartPre.addf("--name=%s", podName)
+
+	handlePodmanArgs(podUnit, PodGroup, execStartPre)
+
+	service.AddCmdline(ServiceGroup, "ExecStartPre", execStartPre.Args)
+
+	service.Setv(ServiceGroup,
+		"Environment", "PODMAN_SYSTEMD_UNIT=%n",
+		"Type", "forking",
+		"Restart", "on-failure",
+		"PIDFile", "%t/%N.pid",
+	)
+
+	return service, nil
+}
+
 func handleUserRemap(unitFile *parser.UnitFile, groupName string, podman *PodmanCmdline, isUser, supportManual bool) error {
 	// ignore Remap keys if UserNS is set
 	if userns, ok := unitFile.Lookup(groupName, KeyUserNS); ok && len(userns) > 0 {
@@ -1053,7 +1549,7 @@ func handleUserRemap(unitFile *parser.UnitFile, groupName string, podman *Podman
 		podman.addf("--userns=" + usernsOpts("keep-id", keepidOpts))
 
 	default:
-		return fmt.Errorf("unsupported RemapUsers option '%s'", remapUsers)
+		return fmt.Errorf("unsupported RemapUsers option '%s' in %s", remapUsers, keyPosition(unitFile, groupName, KeyRemapUsers))
 	}
 
 	return nil
@@ -1065,14 +1561,14 @@ func handleUserNS(unitFile *parser.UnitFile, groupName string, podman *PodmanCmd
 	}
 }
 
-func addNetworks(quadletUnitFile *parser.UnitFile, groupName string, serviceUnitFile *parser.UnitFile, podman *PodmanCmdline) {
+func addNetworks(quadletUnitFile *parser.UnitFile, groupName string, serviceUnitFile *parser.UnitFile, names map[string]string, podman *PodmanCmdline) {
 	networks := quadletUnitFile.LookupAll(groupName, KeyNetwork)
 	for _, network := range networks {
 		if len(network) > 0 {
 			quadletNetworkName, options, found := strings.Cut(network, ":")
 			if strings.HasSuffix(quadletNetworkName, ".network") {
-				// the podman network name is systemd-$name
-				networkName := replaceExtension(quadletNetworkName, "", "systemd-", "")
+				// the podman network name is systemd-$name, unless set by NetworkName
+				networkName := lookupResourceName(names, quadletNetworkName)
 
 				// the systemd unit name is $name-network.service
 				networkServiceName := replaceExtension(quadletNetworkName, ".service", "", "-network")
@@ -1105,6 +1601,18 @@ func startsWithSystemdSpecifier(filePath string) bool {
 	return true
 }
 
+func containsSystemdSpecifier(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if startsWithSystemdSpecifier(s[i:]) {
+			return true
+		}
+		if strings.HasPrefix(s[i:], "%%") {
+			i++
+		}
+	}
+	return false
+}
+
 func getAbsolutePath(quadletUnitFile *parser.UnitFile, filePath string) (string, error) {
 	// When the path starts with a Systemd specifier do not resolve what looks like a relative address
 	if !startsWithSystemdSpecifier(filePath) && !filepath.IsAbs(filePath) {
@@ -1192,7 +1700,7 @@ func handleLogDriver(unitFile *parser.UnitFile, groupName string, podman *Podman
 	}
 }
 
-func handleStorageSource(quadletUnitFile, serviceUnitFile *parser.UnitFile, source string) (string, error) {
+func handleStorageSource(quadletUnitFile, serviceUnitFile *parser.UnitFile, source string, names map[string]string) (string, error) {
 	if source[0] == '.' {
 		var err error
 		source, err = getAbsolutePath(quadletUnitFile, source)
@@ -1204,8 +1712,8 @@ func handleStorageSource(quadletUnitFile, serviceUnitFile *parser.UnitFile, sour
 		// Absolute path
 		serviceUnitFile.Add(UnitGroup, "RequiresMountsFor", source)
 	} else if strings.HasSuffix(source, ".volume") {
-		// the podman volume name is systemd-$name
-		volumeName := replaceExtension(source, "", "systemd-", "")
+		// the podman volume name is systemd-$name, unless set by VolumeName
+		volumeName := lookupResourceName(names, source)
 
 		// the systemd unit name is $name-volume.service
 		volumeServiceName := replaceExtension(source, ".service", "", "-volume")
@@ -1222,6 +1730,11 @@ func handleStorageSource(quadletUnitFile, serviceUnitFile *parser.UnitFile, sour
 func handleHealth(unitFile *parser.UnitFile, groupName string, podman *PodmanCmdline) {
 	keyArgMap := [][2]string{
 		{KeyHealthCmd, "cmd"},
+		{KeyHealthHTTP, "http"},
+		{KeyHealthHTTPStatus, "http-status"},
+		{KeyHealthTCP, "tcp"},
+		{KeyHealthGRPC, "grpc"},
+		{KeyHealthGRPCService, "grpc-service"},
 		{KeyHealthInterval, "interval"},
 		{KeyHealthOnFailure, "on-failure"},
 		{KeyHealthRetries, "retries"},
@@ -1241,6 +1754,227 @@ func handleHealth(unitFile *parser.UnitFile, groupName string, podman *PodmanCmd
 			podman.addf("%s", val)
 		}
 	}
+
+	for _, header := range unitFile.LookupAll(groupName, KeyHealthHTTPHeader) {
+		podman.addf("--health-http-header=%s", header)
+	}
+}
+
+func addVolumes(quadletUnitFile, serviceUnitFile *parser.UnitFile, groupName string, names map[string]string, podman *PodmanCmdline) error {
+	volumes := quadletUnitFile.LookupAll(groupName, KeyVolume)
+	for _, volume := range volumes {
+		parts := strings.SplitN(volume, ":", 3)
+
+		source := ""
+		var dest string
+		options := ""
+		if len(parts) >= 2 {
+			source = parts[0]
+			dest = parts[1]
+		} else {
+			dest = parts[0]
+		}
+		if len(parts) >= 3 {
+			options = ":" + parts[2]
+		}
+
+		if source != "" {
+			var err error
+			source, err = handleStorageSource(quadletUnitFile, serviceUnitFile, source, names)
+			if err != nil {
+				return err
+			}
+		}
+
+		podman.add("-v")
+		if source == "" {
+			podman.add(dest)
+		} else {
+			podman.addf("%s:%s%s", source, dest, options)
+		}
+	}
+
+	return nil
+}
+
+func handlePod(quadletUnitFile, serviceUnitFile *parser.UnitFile, groupName string, podsInfoMap map[string]*PodInfo, podman *PodmanCmdline) error {
+	pod, ok := quadletUnitFile.Lookup(groupName, KeyPod)
+	if !ok || len(pod) == 0 {
+		return nil
+	}
+
+	// Pods not managed by Quadlet are joined by name
+	if !strings.HasSuffix(pod, ".pod") {
+		podman.addf("--pod=%s", pod)
+		return nil
+	}
+
+	// Instances of template pods (e.g. foo@%i.pod) refer to the template unit
+	podInfo, ok := podsInfoMap[getTemplateName(pod)]
+	if !ok {
+		return fmt.Errorf("quadlet pod unit %s does not exist", pod)
+	}
+
+	podServiceName := podInfo.ServiceName
+	if pod != getTemplateName(pod) {
+		podServiceName = replaceExtension(pod, "", "", "-pod")
+	}
+
+	podman.addf("--pod-id-file=%%t/%s.pod-id", podServiceName)
+
+	// The pod must exist before the container is created, and the
+	// container goes away together with the pod
+	serviceUnitFile.Add(UnitGroup, "BindsTo", podServiceName+".service")
+	serviceUnitFile.Add(UnitGroup, "After", podServiceName+".service")
+
+	containerServiceName := serviceUnitFile.Filename
+	if isTemplateUnit(containerServiceName) {
+		// Only a template pod can start the matching instance of a template container
+		if !isTemplateUnit(podInfo.ServiceName + ".service") {
+			return nil
+		}
+		containerServiceName = replaceExtension(containerServiceName, "", "", "") + "%i.service"
+	}
+	podInfo.Containers = append(podInfo.Containers, containerServiceName)
+	return nil
+}
+
+func handleImageSource(quadletImageName string, serviceUnitFile *parser.UnitFile, names map[string]string) (string, error) {
+	var serviceSuffix string
+	switch {
+	case strings.HasSuffix(quadletImageName, ".image"):
+		serviceSuffix = "-image"
+	case strings.HasSuffix(quadletImageName, ".build"):
+		serviceSuffix = "-build"
+	default:
+		return quadletImageName, nil
+	}
+
+	// since there is no default name conversion, the actual image name must exist in the names map
+	imageName, ok := names[quadletImageName]
+	if !ok {
+		return "", fmt.Errorf("requested Quadlet image %s was not found", quadletImageName)
+	}
+
+	// the systemd unit name is $name-image.service or $name-build.service
+	imageServiceName := replaceExtension(quadletImageName, ".service", "", serviceSuffix)
+
+	serviceUnitFile.Add(UnitGroup, "Requires", imageServiceName)
+	serviceUnitFile.Add(UnitGroup, "After", imageServiceName)
+
+	return imageName, nil
+}
+
+// Sets the WorkingDirectory of the service according to SetWorkingDirectory=.
+// Returns the build context if SetWorkingDirectory= is a path or URL rather
+// than one of the special values.
+func handleSetWorkingDirectory(quadletUnitFile, serviceUnitFile *parser.UnitFile) (string, error) {
+	setWorkingDirectory, ok := quadletUnitFile.Lookup(BuildGroup, KeySetWorkingDirectory)
+	if !ok || len(setWorkingDirectory) == 0 {
+		return "", nil
+	}
+
+	var relativeToFile string
+	switch strings.ToLower(setWorkingDirectory) {
+	case "file":
+		relativeToFile, ok = quadletUnitFile.Lookup(BuildGroup, KeyFile)
+		if !ok || len(relativeToFile) == 0 {
+			return "", fmt.Errorf("SetWorkingDirectory=file requires the File key to be set")
+		}
+		if isURL(relativeToFile) {
+			return "", fmt.Errorf("SetWorkingDirectory=file can not be used with a File key URL")
+		}
+	case "unit":
+		relativeToFile = quadletUnitFile.Path
+	default:
+		// Any other value is used as the build context
+		if isURL(setWorkingDirectory) {
+			return setWorkingDirectory, nil
+		}
+		return getAbsolutePath(quadletUnitFile, setWorkingDirectory)
+	}
+
+	// If WorkingDirectory is already set in the Service section do not change it
+	if workingDir, ok := quadletUnitFile.Lookup(ServiceGroup, ServiceKeyWorkingDirectory); ok && len(workingDir) > 0 {
+		return "", nil
+	}
+
+	fileInWorkingDir, err := getAbsolutePath(quadletUnitFile, relativeToFile)
+	if err != nil {
+		return "", err
+	}
+	serviceUnitFile.Add(ServiceGroup, ServiceKeyWorkingDirectory, filepath.Dir(fileInWorkingDir))
+
+	return "", nil
+}
+
+func isURL(urlCandidate string) bool {
+	for _, prefix := range []string{"http://", "https://", "git://", "github.com/"} {
+		if strings.HasPrefix(urlCandidate, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func handleGlobalArgs(unitFile *parser.UnitFile, groupName string, podman *PodmanCmdline) {
+	globalArgs := unitFile.LookupAllArgs(groupName, KeyGlobalArgs)
+	if len(globalArgs) > 0 {
+		podman.add(globalArgs...)
+	}
+}
+
+func lookupAndAddString(unit *parser.UnitFile, group, key, flag string, podman *PodmanCmdline) {
+	val, ok := unit.Lookup(group, key)
+	if ok && len(val) > 0 {
+		podman.addf("%s=%s", flag, val)
+	}
+}
+
+func lookupAndAddBoolean(unit *parser.UnitFile, group, key, flag string, podman *PodmanCmdline) {
+	val, ok := unit.LookupBoolean(group, key)
+	if ok {
+		podman.addBool(flag, val)
+	}
+}
+
+// Adds the memory, CPU, pids and other resource limits of the container.
+// CPUQuota and CPUWeight are interpreted like the systemd keys of the same
+// name, so they are converted to the equivalent podman options.
+func handleResourceLimits(unitFile *parser.UnitFile, groupName string, podman *PodmanCmdline) error {
+	lookupAndAddString(unitFile, groupName, KeyMemory, "--memory", podman)
+
+	if cpuQuota, ok := unitFile.Lookup(groupName, KeyCPUQuota); ok && len(cpuQuota) > 0 {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(cpuQuota, "%"), 64)
+		if err != nil || !strings.HasSuffix(cpuQuota, "%") || percent <= 0 {
+			return fmt.Errorf("invalid CPUQuota '%s' in %s, expected a percentage", cpuQuota, keyPosition(unitFile, groupName, KeyCPUQuota))
+		}
+		podman.addf("--cpus=%s", strconv.FormatFloat(percent/100, 'f', -1, 64))
+	}
+
+	if cpuWeight, ok := unitFile.Lookup(groupName, KeyCPUWeight); ok && len(cpuWeight) > 0 {
+		weight, err := strconv.ParseUint(cpuWeight, 10, 64)
+		if err != nil || weight < 1 || weight > 10000 {
+			return fmt.Errorf("invalid CPUWeight '%s' in %s, expected a value between 1 and 10000", cpuWeight, keyPosition(unitFile, groupName, KeyCPUWeight))
+		}
+		// Like systemd, map the default weight of 100 to the default of 1024 shares
+		shares := weight * 1024 / 100
+		if shares < 2 {
+			shares = 2
+		}
+		podman.addf("--cpu-shares=%d", shares)
+	}
+
+	lookupAndAddString(unitFile, groupName, KeyPidsLimit, "--pids-limit", podman)
+
+	ulimits := unitFile.LookupAllStrv(groupName, KeyUlimit)
+	for _, ulimit := range ulimits {
+		podman.addf("--ulimit=%s", ulimit)
+	}
+
+	lookupAndAddString(unitFile, groupName, KeyShmSize, "--shm-size", podman)
+
+	return nil
 }
 
 func handlePodmanArgs(unitFile *parser.UnitFile, groupName string, podman *PodmanCmdline) {
@@ -3,6 +3,7 @@ package quadlet
 import (
 	"testing"
 
+	"github.com/containers/podman/v4/pkg/systemd/parser"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,3 +44,94 @@ func TestQuadlet_SplitPorts(t *testing.T) {
 	assert.Equal(t, parts[0], "foo")
 	assert.Equal(t, parts[1], "abc[foo::barxyz:bar")
 }
+
+func TestQuadlet_ReplaceExtension(t *testing.T) {
+	tests := []struct {
+		name, extension, prefix, suffix string
+		res                             string
+	}{
+		{"foo.container", ".service", "", "", "foo.service"},
+		{"foo.volume", ".service", "", "-volume", "foo-volume.service"},
+		{"foo.volume", "", "systemd-", "", "systemd-foo"},
+		{"foo@.container", ".service", "", "", "foo@.service"},
+		{"foo@.volume", ".service", "", "-volume", "foo-volume@.service"},
+		{"foo@bar.volume", ".service", "", "-volume", "foo-volume@bar.service"},
+		{"foo@%i.network", ".service", "", "-network", "foo-network@%i.service"},
+	}
+
+	for _, test := range tests {
+		res := replaceExtension(test.name, test.extension, test.prefix, test.suffix)
+		assert.Equal(t, test.res, res, "%q", test.name)
+	}
+}
+
+func TestQuadlet_TemplateNames(t *testing.T) {
+	assert.True(t, isTemplateUnit("foo@.container"))
+	assert.False(t, isTemplateUnit("foo@bar.container"))
+	assert.False(t, isTemplateUnit("foo.container"))
+
+	assert.Equal(t, "foo@.pod", getTemplateName("foo@bar.pod"))
+	assert.Equal(t, "foo@.pod", getTemplateName("foo@%i.pod"))
+	assert.Equal(t, "foo@.pod", getTemplateName("foo@.pod"))
+	assert.Equal(t, "foo.pod", getTemplateName("foo.pod"))
+
+	assert.Equal(t, "systemd-foo", getDefaultResourceName("foo.volume"))
+	assert.Equal(t, "systemd-foo_%i", getDefaultResourceName("foo@.volume"))
+	assert.Equal(t, "systemd-foo_bar", getDefaultResourceName("foo@bar.volume"))
+	assert.Equal(t, "systemd-foo_%i", getDefaultResourceName("foo@%i.volume"))
+}
+
+func TestQuadlet_PortSpecifiers(t *testing.T) {
+	assert.True(t, isPortRange("8080"))
+	assert.True(t, isPortRange("80%i"))
+	assert.True(t, isPortRange("%i/udp"))
+	assert.False(t, isPortRange("80%%i"))
+	assert.False(t, isPortRange("foo"))
+}
+
+func TestQuadlet_InstanceSpecifiersSurviveQuoting(t *testing.T) {
+	podman := NewPodmanCmdline("run")
+	podman.addf("--name=%s", "systemd-%p_%i")
+	podman.add("-v", "/data/%i:/data")
+	podman.addEnv(map[string]string{
+		"INSTANCE": "%i",
+		"NAME":     "%p %i",
+	})
+	podman.addLabels(map[string]string{
+		"instance": "\"%I\"",
+	})
+
+	service := parser.NewUnitFile()
+	service.AddCmdline(ServiceGroup, "ExecStart", podman.Args)
+
+	execStart, ok := service.Lookup(ServiceGroup, "ExecStart")
+	assert.True(t, ok)
+	assert.Contains(t, execStart, "--name=systemd-%p_%i")
+	assert.Contains(t, execStart, `"NAME=%p %i"`)
+
+	// The specifiers must be passed on unmodified, for systemd to expand
+	args, ok := service.LookupLastArgs(ServiceGroup, "ExecStart")
+	assert.True(t, ok)
+	assert.Equal(t, podman.Args, args)
+}
+
+func TestQuadlet_UnknownKeyPosition(t *testing.T) {
+	unit := parser.NewUnitFile()
+	unit.Path = "/etc/containers/systemd/foo.container"
+	err := unit.Parse(`[Container]
+Image=localhost/imagename
+
+Imag=localhost/typo
+`)
+	assert.Nil(t, err)
+
+	err = checkForUnknownKeys(unit, ContainerGroup, supportedContainerKeys)
+	assert.EqualError(t, err, "unsupported key 'Imag' in group 'Container' in /etc/containers/systemd/foo.container:4")
+
+	// Without a known line, the unit is reported
+	unit = parser.NewUnitFile()
+	unit.Path = "/etc/containers/systemd/bar.container"
+	unit.Set(ContainerGroup, "Imag", "localhost/typo")
+	err = checkForUnknownKeys(unit, ContainerGroup, supportedContainerKeys)
+	assert.EqualError(t, err, "unsupported key 'Imag' in group 'Container' in /etc/containers/systemd/bar.container")
+}
@@ -0,0 +1,288 @@
+package quadlet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containers/podman/v4/pkg/systemd/parser"
+)
+
+var (
+	void                struct{}
+	supportedExtensions = map[string]struct{}{
+		".container": void,
+		".volume":    void,
+		".kube":      void,
+		".network":   void,
+		".pod":       void,
+		".image":     void,
+		".build":     void,
+	}
+
+	// Images and builds are converted first, so containers can resolve
+	// their names. Pods are converted last, as the containers register
+	// themselves with their pod while being converted
+	unitTypeOrder = map[string]int{
+		".image":     0,
+		".build":     0,
+		".container": 1,
+		".volume":    1,
+		".kube":      1,
+		".network":   1,
+		".pod":       2,
+	}
+)
+
+// ConvertedUnit is the result of converting a Quadlet unit
+type ConvertedUnit struct {
+	// Name of the Quadlet unit, e.g. foo.container
+	Name string
+	// The Quadlet unit, including its drop-ins
+	Unit *parser.UnitFile
+	// The generated service, nil if the conversion failed
+	Service *parser.UnitFile
+	// Why the conversion failed, if it did
+	Err error
+}
+
+// GetUnitDirs returns the directories where we read quadlet .container and .volumes from
+// For system generators these are in /usr/share/containers/systemd (for distro files)
+// and /etc/containers/systemd (for sysadmin files).
+// For user generators these can live in /etc/containers/systemd/users, /etc/containers/systemd/users/$UID, and $XDG_CONFIG_HOME/containers/systemd
+func GetUnitDirs(rootless bool) []string {
+	// Allow overriding source dir, this is mainly for the CI tests
+	unitDirsEnv := os.Getenv("QUADLET_UNIT_DIRS")
+	if len(unitDirsEnv) > 0 {
+		return strings.Split(unitDirsEnv, ":")
+	}
+
+	dirs := make([]string, 0)
+	if rootless {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v", err)
+			return nil
+		}
+		dirs = append(dirs, path.Join(configDir, "containers/systemd"))
+		u, err := user.Current()
+		if err == nil {
+			dirs = append(dirs, filepath.Join(UnitDirAdmin, "users", u.Uid))
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: %v", err)
+		}
+		return append(dirs, filepath.Join(UnitDirAdmin, "users"))
+	}
+	dirs = append(dirs, UnitDirAdmin)
+	return append(dirs, UnitDirDistro)
+}
+
+// IsExtSupported returns whether the file name has the extension of a Quadlet unit
+func IsExtSupported(filename string) bool {
+	ext := filepath.Ext(filename)
+	_, ok := supportedExtensions[ext]
+	return ok
+}
+
+// LoadUnitsFromDir loads the Quadlet units in the directory that are not in
+// units yet, so units in earlier directories shadow those in later ones.
+// Units that fail to load are skipped and reported in the returned error.
+func LoadUnitsFromDir(sourcePath string, units map[string]*parser.UnitFile) error {
+	var prevError error
+	reportError := func(err error) {
+		if prevError != nil {
+			err = fmt.Errorf("%s\n%s", prevError, err)
+		}
+		prevError = err
+	}
+
+	files, err := os.ReadDir(sourcePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("can't read %q: %w", sourcePath, err)
+		}
+		return nil
+	}
+
+	for _, file := range files {
+		name := file.Name()
+		if units[name] == nil && IsExtSupported(name) {
+			path := path.Join(sourcePath, name)
+
+			if f, err := parser.ParseUnitFile(path); err != nil {
+				reportError(fmt.Errorf("error loading %q, ignoring: %w", path, err))
+			} else {
+				units[name] = f
+			}
+		}
+	}
+
+	return prevError
+}
+
+// Returns the names of the drop-in directories of a unit, from the most to the
+// least specific one, e.g. foo.container.d and container.d for foo.container
+func getDropinDirNames(unitName string) []string {
+	return []string{
+		unitName + ".d",
+		strings.TrimPrefix(filepath.Ext(unitName), ".") + ".d",
+	}
+}
+
+// LoadUnitDropins merges the *.conf drop-in files of the unit found in the
+// source paths into the unit. As in systemd, the drop-ins are applied in
+// lexical order of their file names, and a file shadows any file with the
+// same name in less specific drop-in directories and in later source paths.
+func LoadUnitDropins(unit *parser.UnitFile, sourcePaths []string) error {
+	var prevError error
+	reportError := func(err error) {
+		if prevError != nil {
+			err = fmt.Errorf("%s\n%s", prevError, err)
+		}
+		prevError = err
+	}
+
+	dropinPaths := make(map[string]string)
+	for _, sourcePath := range sourcePaths {
+		for _, dropinDirName := range getDropinDirNames(unit.Filename) {
+			dropinDir := path.Join(sourcePath, dropinDirName)
+
+			dropinFiles, err := os.ReadDir(dropinDir)
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) {
+					reportError(fmt.Errorf("error reading directory %q: %w", dropinDir, err))
+				}
+				continue
+			}
+
+			for _, dropinFile := range dropinFiles {
+				dropinName := dropinFile.Name()
+				if filepath.Ext(dropinName) != ".conf" {
+					continue // Only *.conf supported
+				}
+
+				if _, ok := dropinPaths[dropinName]; ok {
+					continue // We already saw this name
+				}
+
+				dropinPaths[dropinName] = path.Join(dropinDir, dropinName)
+			}
+		}
+	}
+
+	dropinNames := make([]string, 0, len(dropinPaths))
+	for dropinName := range dropinPaths {
+		dropinNames = append(dropinNames, dropinName)
+	}
+
+	// Merge in lexical order
+	sort.Strings(dropinNames)
+
+	for _, dropinName := range dropinNames {
+		dropinPath := dropinPaths[dropinName]
+
+		if f, err := parser.ParseUnitFile(dropinPath); err != nil {
+			reportError(fmt.Errorf("error loading %q: %w", dropinPath, err))
+		} else {
+			unit.Merge(f)
+		}
+	}
+
+	return prevError
+}
+
+// Returns the names of the units in the order they have to be converted in
+func sortedUnitNames(units map[string]*parser.UnitFile) []string {
+	names := make([]string, 0, len(units))
+	for name := range units {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		oi := unitTypeOrder[filepath.Ext(names[i])]
+		oj := unitTypeOrder[filepath.Ext(names[j])]
+		if oi != oj {
+			return oi < oj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// ConvertUnits converts the Quadlet units to services, resolving the
+// references between them (e.g. of containers to their pod). The results
+// are in conversion order, and a unit failing to convert does not stop the
+// conversion of the others.
+func ConvertUnits(units map[string]*parser.UnitFile, isUser bool) []*ConvertedUnit {
+	// Generate the pods info map to allow containers to link to their pods
+	// and add themselves to the pod's container list
+	podsInfoMap := make(map[string]*PodInfo)
+	for name, unit := range units {
+		if strings.HasSuffix(name, ".pod") {
+			podsInfoMap[name] = &PodInfo{
+				ServiceName: GetPodServiceName(unit),
+				Containers:  make([]string, 0),
+			}
+		}
+	}
+
+	// Names of the resources created by quadlet units (e.g. the image
+	// pulled by an .image unit), keyed by the unit name. The names of
+	// volumes and networks are known upfront.
+	names := make(map[string]string)
+	for name, unit := range units {
+		switch {
+		case strings.HasSuffix(name, ".volume"):
+			names[name] = getResourceName(unit, VolumeGroup, KeyVolumeName, name)
+		case strings.HasSuffix(name, ".network"):
+			names[name] = getResourceName(unit, NetworkGroup, KeyNetworkName, name)
+		}
+	}
+
+	results := make([]*ConvertedUnit, 0, len(units))
+	for _, name := range sortedUnitNames(units) {
+		unit := units[name]
+		result := &ConvertedUnit{
+			Name: name,
+			Unit: unit,
+		}
+
+		switch {
+		case strings.HasSuffix(name, ".container"):
+			result.Service, result.Err = ConvertContainer(unit, names, isUser, podsInfoMap)
+		case strings.HasSuffix(name, ".volume"):
+			result.Service, result.Err = ConvertVolume(unit, name)
+		case strings.HasSuffix(name, ".kube"):
+			result.Service, result.Err = ConvertKube(unit, names, isUser)
+		case strings.HasSuffix(name, ".network"):
+			result.Service, result.Err = ConvertNetwork(unit, name)
+		case strings.HasSuffix(name, ".pod"):
+			result.Service, result.Err = ConvertPod(unit, name, names, podsInfoMap)
+		case strings.HasSuffix(name, ".image"):
+			var imageName string
+			result.Service, imageName, result.Err = ConvertImage(unit)
+			if result.Err == nil {
+				names[name] = imageName
+			}
+		case strings.HasSuffix(name, ".build"):
+			var imageName string
+			result.Service, imageName, result.Err = ConvertBuild(unit, names)
+			if result.Err == nil {
+				names[name] = imageName
+			}
+		default:
+			result.Err = fmt.Errorf("unsupported file type %q", name)
+		}
+
+		if result.Err != nil {
+			result.Service = nil
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,98 @@
+package quadlet
+
+import (
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/podman/v4/pkg/systemd/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitDirs(t *testing.T) {
+	rootDirs := []string{
+		UnitDirAdmin,
+		UnitDirDistro,
+	}
+	unitDirs := GetUnitDirs(false)
+	assert.Equal(t, unitDirs, rootDirs, "rootful unit dirs should match")
+
+	configDir, err := os.UserConfigDir()
+	assert.Nil(t, err)
+	u, err := user.Current()
+	assert.Nil(t, err)
+
+	rootlessDirs := []string{
+		path.Join(configDir, "containers/systemd"),
+		filepath.Join(UnitDirAdmin, "users", u.Uid),
+		filepath.Join(UnitDirAdmin, "users"),
+	}
+
+	unitDirs = GetUnitDirs(true)
+	assert.Equal(t, unitDirs, rootlessDirs, "rootless unit dirs should match")
+
+	name, err := os.MkdirTemp("", "dir")
+	assert.Nil(t, err)
+	// remove the temporary directory at the end of the program
+	defer os.RemoveAll(name)
+
+	t.Setenv("QUADLET_UNIT_DIRS", name)
+	unitDirs = GetUnitDirs(false)
+	assert.Equal(t, unitDirs, []string{name}, "rootful should use environment variable")
+
+	unitDirs = GetUnitDirs(true)
+	assert.Equal(t, unitDirs, []string{name}, "rootless should use environment variable")
+}
+
+func TestSortedUnitNames(t *testing.T) {
+	units := map[string]*parser.UnitFile{
+		"b.pod":       nil,
+		"a.pod":       nil,
+		"c.container": nil,
+		"a.volume":    nil,
+		"b.container": nil,
+		"z.image":     nil,
+	}
+
+	// Images come first so that containers can refer to them, and pods
+	// come last, so that containers can register with them first
+	expected := []string{"z.image", "a.volume", "b.container", "c.container", "a.pod", "b.pod"}
+	assert.Equal(t, expected, sortedUnitNames(units))
+}
+
+func TestLoadUnitDropins(t *testing.T) {
+	adminDir := t.TempDir()
+	distroDir := t.TempDir()
+
+	writeFile := func(dir, name, content string) {
+		p := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.Nil(t, os.WriteFile(p, []byte(content), 0644))
+	}
+
+	writeFile(distroDir, "foo.container", "[Container]\nImage=localhost/imagename\nVolume=/a:/a\n")
+	// Type-wide defaults, shadowed by the unit specific file with the same name
+	writeFile(distroDir, "container.d/10-image.conf", "[Container]\nImage=localhost/shadowed\n")
+	writeFile(distroDir, "container.d/20-label.conf", "[Container]\nLabel=type=wide\n")
+	writeFile(distroDir, "foo.container.d/10-image.conf", "[Container]\nImage=localhost/distro\n")
+	// The admin directory shadows the distro one
+	writeFile(distroDir, "foo.container.d/30-volume.conf", "[Container]\nVolume=/shadowed:/shadowed\n")
+	writeFile(adminDir, "foo.container.d/30-volume.conf", "[Container]\nVolume=/b:/b\n")
+	// Only *.conf files are merged
+	writeFile(adminDir, "foo.container.d/40-ignored.txt", "[Container]\nImage=localhost/ignored\n")
+	// Drop-ins of other types do not apply
+	writeFile(adminDir, "volume.d/10-volume.conf", "[Container]\nImage=localhost/volume\n")
+
+	unit, err := parser.ParseUnitFile(filepath.Join(distroDir, "foo.container"))
+	assert.Nil(t, err)
+
+	err = LoadUnitDropins(unit, []string{adminDir, distroDir})
+	assert.Nil(t, err)
+
+	image, _ := unit.Lookup(ContainerGroup, KeyImage)
+	assert.Equal(t, "localhost/distro", image)
+	assert.Equal(t, []string{"/a:/a", "/b:/b"}, unit.LookupAll(ContainerGroup, KeyVolume))
+	assert.Equal(t, map[string]string{"type": "wide"}, unit.LookupAllKeyVal(ContainerGroup, KeyLabel))
+}
@@ -49,6 +49,31 @@ class SystemTestCase(APITestCase):
             if obj["Actor"].get("Attributes") and obj["Actor"]["Attributes"].get("image"):
                 self.assertEqual(obj["Actor"]["Attributes"]["image"], obj["from"])
 
+    def test_events_accept(self):
+        r = requests.get(
+            self.uri("/events?stream=false"), headers={"Accept": "application/cloudevents+json"}
+        )
+        self.assertEqual(r.status_code, 200, r.text)
+        self.assertEqual(r.headers["Content-Type"], "application/cloudevents+json")
+
+        report = r.text.splitlines()
+        self.assertGreater(len(report), 0, "No events found!")
+        for line in report:
+            obj = json.loads(line)
+            self.assertEqual(obj["specversion"], "1.0")
+            self.assertEqual(obj["dataschema"], "urn:podman:events:docker:v1")
+            self.assertTrue(obj["type"].startswith("io.podman.event." + obj["data"]["Type"] + "."))
+
+        r = requests.get(
+            self.compat_uri("events?stream=false"),
+            headers={"Accept": "application/vnd.podman.docker-events.v1+json"},
+        )
+        self.assertEqual(r.status_code, 200, r.text)
+        for line in r.text.splitlines():
+            obj = json.loads(line)
+            self.assertIn("ID", obj["Actor"])
+            self.assertNotEqual(obj["Action"], "died")
+
     def test_ping(self):
         required_headers = (
             "API-Version",
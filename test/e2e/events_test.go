@@ -3,6 +3,8 @@ package integration
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -59,6 +61,39 @@ var _ = Describe("Podman events", func() {
 		Expect(events[0]).To(ContainSubstring(vname), "event log includes volume name")
 	})
 
+	It("podman events with a secret filter", func() {
+		secretFilePath := filepath.Join(podmanTest.TempDir, "secret")
+		err := os.WriteFile(secretFilePath, []byte("mysecret"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		session := podmanTest.Podman([]string{"secret", "create", "events-secret", secretFilePath})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		secretID := session.OutputToString()
+
+		session = podmanTest.Podman([]string{"run", "--rm", "--name", "secret-user", "--secret", "events-secret", ALPINE, "true"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"secret", "rm", "events-secret"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--filter", "secret=events-secret", "--format", "{{.Type}} {{.Status}} {{.ID}}"})
+		result.WaitWithDefaultTimeout()
+		Expect(result).Should(Exit(0))
+		Expect(result.OutputToStringArray()).To(Equal([]string{
+			"secret create " + secretID,
+			"secret use " + secretID,
+			"secret remove " + secretID,
+		}))
+
+		result = podmanTest.Podman([]string{"events", "--stream=false", "--filter", "type=secret", "--filter", "event=use", "--format", "{{.Attributes.containerName}}"})
+		result.WaitWithDefaultTimeout()
+		Expect(result).Should(Exit(0))
+		Expect(result.OutputToStringArray()).To(ContainElement("secret-user"))
+	})
+
 	It("podman events with an event filter and container=cid", func() {
 		_, ec, cid := podmanTest.RunLsContainer("")
 		Expect(ec).To(Equal(0))
@@ -242,4 +277,100 @@ var _ = Describe("Podman events", func() {
 		Expect(result.OutputToStringArray()).ToNot(BeEmpty(), "Number of health_status events")
 	})
 
+	It("podman events forwarded to sinks", func() {
+		forwarded := filepath.Join(podmanTest.TempDir, "forwarded")
+		conffile := filepath.Join(podmanTest.TempDir, "containers.conf")
+		conf := fmt.Sprintf(`[[engine.events_sinks]]
+type = "exec"
+command = ["sh", "-c", "echo $PODMAN_EVENT_STATUS $PODMAN_EVENT_NAME >> %s"]
+filters = ["type=container", "event=create"]
+`, forwarded)
+		err := os.WriteFile(conffile, []byte(conf), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		os.Setenv("CONTAINERS_CONF_OVERRIDE", conffile)
+		if IsRemote() {
+			podmanTest.RestartRemoteService()
+		}
+
+		session := podmanTest.Podman([]string{"create", "--name", "forwarded-ctr", ALPINE})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		Eventually(func() (string, error) {
+			content, err := os.ReadFile(forwarded)
+			return string(content), err
+		}, "10s", "500ms").Should(Equal("create forwarded-ctr\n"))
+	})
+
+	It("podman events --format cloudevents and docker-json", func() {
+		session := podmanTest.Podman([]string{"create", "--name", "structured", ALPINE, "false"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--filter", "container=structured", "--format", "cloudevents"})
+		result.WaitWithDefaultTimeout()
+		Expect(result).Should(Exit(0))
+		events := result.OutputToStringArray()
+		Expect(events).To(HaveLen(1))
+		var cloudEvent map[string]interface{}
+		err := json.Unmarshal([]byte(events[0]), &cloudEvent)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cloudEvent).To(HaveKeyWithValue("specversion", "1.0"))
+		Expect(cloudEvent).To(HaveKeyWithValue("type", "io.podman.event.container.create"))
+		Expect(cloudEvent).To(HaveKeyWithValue("subject", "structured"))
+		Expect(cloudEvent).To(HaveKeyWithValue("dataschema", "urn:podman:events:docker:v1"))
+		Expect(cloudEvent).To(HaveKeyWithValue("data", HaveKeyWithValue("Action", "create")))
+
+		result = podmanTest.Podman([]string{"events", "--stream=false", "--filter", "container=structured", "--format", "docker-json"})
+		result.WaitWithDefaultTimeout()
+		Expect(result).Should(Exit(0))
+		var dockerEvent map[string]interface{}
+		err = json.Unmarshal([]byte(result.OutputToString()), &dockerEvent)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dockerEvent).To(HaveKeyWithValue("Type", "container"))
+		Expect(dockerEvent).To(HaveKeyWithValue("Action", "create"))
+		Expect(dockerEvent).To(HaveKeyWithValue("Actor", HaveKeyWithValue("Attributes", HaveKeyWithValue("name", "structured"))))
+	})
+
+	It("podman events --count", func() {
+		for _, name := range []string{"count1", "count2", "count3"} {
+			session := podmanTest.Podman([]string{"create", "--name", name, ALPINE})
+			session.WaitWithDefaultTimeout()
+			Expect(session).Should(Exit(0))
+		}
+
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--filter", "type=container", "--filter", "event=create", "--count", "2", "--format", "{{.Name}}"})
+		result.WaitWithDefaultTimeout()
+		Expect(result).Should(Exit(0))
+		Expect(result.OutputToStringArray()).To(Equal([]string{"count2", "count3"}))
+	})
+
+	It("podman events with the sqlite backend", func() {
+		conffile := filepath.Join(podmanTest.TempDir, "containers.conf")
+		err := os.WriteFile(conffile, []byte("[engine]\nevents_logger = \"sqlite\"\nevents_max_age = \"1h\"\n"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		os.Setenv("CONTAINERS_CONF_OVERRIDE", conffile)
+		if IsRemote() {
+			podmanTest.RestartRemoteService()
+		}
+
+		for _, name := range []string{"sqlite1", "sqlite2"} {
+			session := podmanTest.Podman([]string{"create", "--name", name, ALPINE})
+			session.WaitWithDefaultTimeout()
+			Expect(session).Should(Exit(0))
+		}
+
+		result := podmanTest.Podman([]string{"events", "--stream=false", "--since", "5m", "--filter", "container=sqlite2", "--format", "{{.Status}} {{.Name}}"})
+		result.WaitWithDefaultTimeout()
+		Expect(result).Should(Exit(0))
+		Expect(result.OutputToStringArray()).To(Equal([]string{"create sqlite2"}))
+
+		result = podmanTest.Podman([]string{"events", "--stream=false", "--filter", "type=container", "--count", "1", "--format", "{{.Name}}"})
+		result.WaitWithDefaultTimeout()
+		Expect(result).Should(Exit(0))
+		Expect(result.OutputToStringArray()).To(Equal([]string{"sqlite2"}))
+	})
+
 })
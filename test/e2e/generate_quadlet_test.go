@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("Podman generate quadlet", func() {
+
+	BeforeEach(func() {
+		SkipIfRemote("podman generate quadlet is not supported on the remote client")
+	})
+
+	It("podman generate quadlet bogus should fail", func() {
+		session := podmanTest.Podman([]string{"generate", "quadlet", "foobar"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(125))
+		Expect(session.ErrorToString()).To(ContainSubstring(`name or ID "foobar" not found`))
+	})
+
+	It("podman generate quadlet container", func() {
+		session := podmanTest.Podman([]string{"create", "--name", "web", "-p", "8080:80", "-v", "data:/data:ro", "--env", "FOO=bar", "--restart", "always", "-t", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"generate", "quadlet", "--no-header", "web"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		Expect(session.OutputToString()).To(ContainSubstring("Image=" + ALPINE))
+		Expect(session.OutputToString()).To(ContainSubstring("ContainerName=web"))
+		Expect(session.OutputToString()).To(ContainSubstring("Exec=top"))
+		Expect(session.OutputToString()).To(ContainSubstring("Environment=FOO=bar"))
+		Expect(session.OutputToString()).To(ContainSubstring("PublishPort=8080:80"))
+		Expect(session.OutputToString()).To(ContainSubstring("Volume=data:/data:ro"))
+		Expect(session.OutputToString()).To(ContainSubstring("Restart=always"))
+		Expect(session.OutputToString()).ToNot(ContainSubstring("autogenerated by Podman"))
+		Expect(session.ErrorToString()).To(ContainSubstring("web.container: cannot express --tty"))
+	})
+
+	It("podman generate quadlet pod, volume and network files", func() {
+		session := podmanTest.Podman([]string{"pod", "create", "--name", "app", "-p", "8080:80"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"create", "--pod", "app", "--name", "worker", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"volume", "create", "--label", "app=web", "vol"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		netName := createNetworkName("net")
+		session = podmanTest.Podman([]string{"network", "create", "--internal", netName})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		defer podmanTest.removeNetwork(netName)
+
+		// A container of a pod is generated along with the pod
+		session = podmanTest.Podman([]string{"generate", "quadlet", "worker"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(125))
+		Expect(session.ErrorToString()).To(ContainSubstring("use generate on the pod itself"))
+
+		dir := filepath.Join(tempdir, "quadlet")
+		err := os.Mkdir(dir, 0755)
+		Expect(err).ToNot(HaveOccurred())
+		cwd, err := os.Getwd()
+		Expect(err).ToNot(HaveOccurred())
+		err = os.Chdir(dir)
+		Expect(err).ToNot(HaveOccurred())
+		defer func() {
+			Expect(os.Chdir(cwd)).To(Succeed())
+		}()
+
+		session = podmanTest.Podman([]string{"generate", "quadlet", "--files", "app", "vol", netName})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		Expect(session.OutputToStringArray()).To(Equal([]string{
+			filepath.Join(dir, "app.pod"),
+			filepath.Join(dir, netName+".network"),
+			filepath.Join(dir, "vol.volume"),
+			filepath.Join(dir, "worker.container"),
+		}))
+
+		content, err := os.ReadFile(filepath.Join(dir, "app.pod"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("PodName=app"))
+		Expect(string(content)).To(ContainSubstring("PublishPort=8080:80"))
+
+		content, err = os.ReadFile(filepath.Join(dir, "worker.container"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("Pod=app.pod"))
+		Expect(string(content)).ToNot(ContainSubstring("PublishPort"))
+
+		content, err = os.ReadFile(filepath.Join(dir, "vol.volume"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("VolumeName=vol"))
+		Expect(string(content)).To(ContainSubstring("Label=app=web"))
+
+		content, err = os.ReadFile(filepath.Join(dir, netName+".network"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("NetworkName=" + netName))
+		Expect(string(content)).To(ContainSubstring("Internal=true"))
+	})
+})
@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -310,6 +311,140 @@ HEALTHCHECK CMD ls -l / 2>&1`, ALPINE)
 		Expect(inspect[0].Config.Healthcheck).To(HaveField("Test", []string{"CMD-SHELL", "ls -l / 2>&1"}))
 	})
 
+	It("podman healthcheck with native HTTP and TCP probes", func() {
+		session := podmanTest.Podman([]string{"run", "-d", "--name", "hc-http", "--health-http", "http://localhost:8080/", "--health-http-status", "404", "busybox", "httpd", "-f", "-p", "8080"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		inspect := podmanTest.InspectContainer("hc-http")
+		Expect(inspect[0].Config.Healthcheck).To(HaveField("Test", []string{"HTTP-GET", "http://localhost:8080/", "status=404"}))
+
+		// Buy a little time to get the server running
+		for i := 0; i < 5; i++ {
+			hc := podmanTest.Podman([]string{"healthcheck", "run", "hc-http"})
+			hc.WaitWithDefaultTimeout()
+			if hc.ExitCode() == 0 || i == 4 {
+				Expect(hc).Should(Exit(0))
+				break
+			}
+			time.Sleep(1 * time.Second)
+		}
+
+		hc := podmanTest.Podman([]string{"container", "inspect", "--format", "{{.State.Healthcheck.Log}}", "hc-http"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc).Should(Exit(0))
+		Expect(hc.OutputToString()).To(ContainSubstring("HTTP GET http://localhost:8080/: 404"))
+
+		session = podmanTest.Podman([]string{"run", "-d", "--name", "hc-tcp", "--health-tcp", "8081", "busybox", "httpd", "-f", "-p", "8080"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		hc = podmanTest.Podman([]string{"healthcheck", "run", "hc-tcp"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc).Should(Exit(1))
+		Expect(hc.OutputToString()).To(ContainSubstring(define.HealthCheckUnhealthy))
+
+		session = podmanTest.Podman([]string{"create", "--health-cmd", "true", "--health-tcp", "8080", ALPINE})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(125))
+		Expect(session.ErrorToString()).To(ContainSubstring("cannot specify both --health-cmd and a health probe"))
+	})
+
+	It("podman healthcheck log retention and output limits", func() {
+		session := podmanTest.Podman([]string{"run", "-d", "--name", "hc-log", "--health-cmd", "echo 0123456789", "--health-max-log-count", "2", "--health-max-log-size", "4", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		inspect := podmanTest.InspectContainer("hc-log")
+		Expect(inspect[0].Config).To(HaveField("HealthMaxLogCount", uint(2)))
+		Expect(inspect[0].Config).To(HaveField("HealthMaxLogSize", uint(4)))
+
+		for i := 0; i < 3; i++ {
+			hc := podmanTest.Podman([]string{"healthcheck", "run", "hc-log"})
+			hc.WaitWithDefaultTimeout()
+			Expect(hc).Should(Exit(0))
+		}
+
+		hc := podmanTest.Podman([]string{"healthcheck", "log", "--format", "json", "hc-log"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc).Should(Exit(0))
+		var log []define.HealthCheckLog
+		Expect(json.Unmarshal(hc.Out.Contents(), &log)).To(Succeed())
+		Expect(log).To(HaveLen(2))
+		Expect(log[1]).To(HaveField("Output", "0123"))
+
+		hc = podmanTest.Podman([]string{"healthcheck", "log", "--since", "10m", "--format", "{{.Output}}", "hc-log"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc).Should(Exit(0))
+		Expect(hc.OutputToStringArray()).To(Equal([]string{"0123", "0123"}))
+
+		session = podmanTest.Podman([]string{"run", "-d", "--name", "hc-events", "--health-cmd", "echo ok", "--health-log-destination", "events", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		hc = podmanTest.Podman([]string{"healthcheck", "run", "hc-events"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc).Should(Exit(0))
+
+		hc = podmanTest.Podman([]string{"healthcheck", "log", "--format", "{{.ExitCode}} {{.Output}}", "hc-events"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc).Should(Exit(0))
+		Expect(hc.OutputToString()).To(Equal("0 ok"))
+
+		inspect = podmanTest.InspectContainer("hc-events")
+		Expect(inspect[0].State.Health.Log).To(HaveLen(1))
+		Expect(inspect[0].State.Health.Log[0]).To(HaveField("Output", "ok"))
+
+		session = podmanTest.Podman([]string{"create", "--health-cmd", "true", "--health-log-destination", "bogus", ALPINE})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(125))
+		Expect(session.ErrorToString()).To(ContainSubstring("invalid healthcheck log destination"))
+	})
+
+	It("podman readiness check gates dependent containers", func() {
+		session := podmanTest.Podman([]string{"run", "-d", "--name", "db", "--readiness-cmd", "cat /ready", "--readiness-interval", "disable", "--readiness-retries", "5", ALPINE, "sh", "-c", "sleep 2; touch /ready; top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		inspect := podmanTest.InspectContainer("db")
+		Expect(inspect[0].Config.ReadinessCheck).ToNot(BeNil())
+		Expect(inspect[0].Config.ReadinessCheck.Test).To(Equal([]string{"CMD-SHELL", "cat /ready"}))
+		Expect(inspect[0].State.Ready).To(BeFalse())
+
+		hc := podmanTest.Podman([]string{"healthcheck", "run", "--readiness", "db"})
+		hc.WaitWithDefaultTimeout()
+		Expect(hc).Should(Exit(1))
+		Expect(hc.OutputToString()).To(Equal(define.ReadinessCheckNotReady))
+
+		// The dependent container is only started once db is ready.
+		session = podmanTest.Podman([]string{"run", "-d", "--name", "app", "--requires", "db", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		inspect = podmanTest.InspectContainer("db")
+		Expect(inspect[0].State.Ready).To(BeTrue())
+
+		ps := podmanTest.Podman([]string{"ps", "--filter", "name=db", "--format", "{{.Status}}"})
+		ps.WaitWithDefaultTimeout()
+		Expect(ps).Should(Exit(0))
+		Expect(ps.OutputToString()).To(ContainSubstring("(ready)"))
+
+		wait := podmanTest.Podman([]string{"wait", "--condition", "ready", "db", "app"})
+		wait.WaitWithDefaultTimeout()
+		Expect(wait).Should(Exit(0))
+	})
+
+	It("podman wait --condition=ready fails for a container exiting before it is ready", func() {
+		session := podmanTest.Podman([]string{"run", "-d", "--name", "early", "--readiness-cmd", "cat /ready", "--readiness-interval", "disable", ALPINE, "sh", "-c", "sleep 2; exit 3"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		wait := podmanTest.Podman([]string{"wait", "--condition", "ready", "early"})
+		wait.WaitWithDefaultTimeout()
+		Expect(wait).To(ExitWithError())
+		Expect(wait.ErrorToString()).To(ContainSubstring("exited with code 3 before it was ready"))
+	})
+
 	It("Startup healthcheck success transitions to regular healthcheck", func() {
 		ctrName := "hcCtr"
 		ctrRun := podmanTest.Podman([]string{"run", "-dt", "--name", ctrName, "--health-cmd", "echo regular", "--health-startup-cmd", "cat /test", ALPINE, "top"})
@@ -1,8 +1,12 @@
 package integration
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"os"
 	"os/exec"
+	"strings"
 	"time"
 
 	. "github.com/containers/podman/v4/test/utils"
@@ -262,6 +266,36 @@ var _ = Describe("Podman logs", func() {
 			Expect(output[0]).To(Or(ContainSubstring(cid1[:12]), ContainSubstring(cid2[:12])))
 		})
 
+		It("two containers ordered by time: "+log, func() {
+			skipIfJournaldInContainer()
+			SkipIfRemote("podman-remote logs does not support showing two containers at the same time")
+
+			log1 := podmanTest.Podman([]string{"run", "--log-driver", log, "-d", ALPINE, "sh", "-c", "echo first; sleep 4; echo third"})
+			log1.WaitWithDefaultTimeout()
+			Expect(log1).Should(Exit(0))
+			cid1 := log1.OutputToString()
+
+			log2 := podmanTest.Podman([]string{"run", "--log-driver", log, "-d", ALPINE, "sh", "-c", "sleep 1; echo second"})
+			log2.WaitWithDefaultTimeout()
+			Expect(log2).Should(Exit(0))
+			cid2 := log2.OutputToString()
+
+			wait := podmanTest.Podman([]string{"wait", cid1, cid2})
+			wait.WaitWithDefaultTimeout()
+			Expect(wait).To(Exit(0))
+
+			for _, follow := range []string{"--follow=false", "--follow=true"} {
+				results := podmanTest.Podman([]string{"logs", follow, cid1, cid2})
+				results.WaitWithDefaultTimeout()
+				Expect(results).Should(Exit(0))
+				output := results.OutputToStringArray()
+				Expect(output).To(HaveLen(3))
+				Expect(output[0]).To(HaveSuffix("first"))
+				Expect(output[1]).To(HaveSuffix("second"))
+				Expect(output[2]).To(HaveSuffix("third"))
+			}
+		})
+
 		It("podman logs on a created container should result in 0 exit code: "+log, func() {
 			skipIfJournaldInContainer()
 
@@ -396,6 +430,50 @@ var _ = Describe("Podman logs", func() {
 			Expect(results.ErrorToString()).To(Equal("stderr"))
 		})
 
+		It("podman logs with --grep, --stream and --format json: "+log, func() {
+			skipIfJournaldInContainer()
+
+			cname := "log-test"
+			logc := podmanTest.Podman([]string{"run", "--log-driver", log, "--name", cname, ALPINE, "sh", "-c", "echo out1; echo err1 >&2; echo out2; echo err2 >&2"})
+			logc.WaitWithDefaultTimeout()
+			Expect(logc).To(Exit(0))
+
+			results := podmanTest.Podman([]string{"logs", "--grep", "2$", cname})
+			results.WaitWithDefaultTimeout()
+			Expect(results).To(Exit(0))
+			Expect(results.OutputToString()).To(Equal("out2"))
+			Expect(results.ErrorToString()).To(Equal("err2"))
+
+			results = podmanTest.Podman([]string{"logs", "--stream", "stderr", cname})
+			results.WaitWithDefaultTimeout()
+			Expect(results).To(Exit(0))
+			Expect(results.OutputToString()).To(BeEmpty())
+			Expect(results.ErrorToStringArray()).To(Equal([]string{"err1", "err2"}))
+
+			results = podmanTest.Podman([]string{"logs", "--format", "json", "--stream", "stdout", "--grep", "out1", cname})
+			results.WaitWithDefaultTimeout()
+			Expect(results).To(Exit(0))
+			lines := results.OutputToStringArray()
+			Expect(lines).To(HaveLen(1))
+			var line map[string]interface{}
+			Expect(json.Unmarshal([]byte(lines[0]), &line)).To(Succeed())
+			Expect(line).To(HaveKeyWithValue("message", "out1"))
+			Expect(line).To(HaveKeyWithValue("stream", "stdout"))
+			Expect(line).To(HaveKeyWithValue("containerName", cname))
+			Expect(line).To(HaveKeyWithValue("partial", false))
+			Expect(line).To(HaveKey("time"))
+			inspect := podmanTest.Podman([]string{"container", "inspect", "--format", "{{.ID}}", cname})
+			inspect.WaitWithDefaultTimeout()
+			Expect(inspect).To(Exit(0))
+			Expect(line).To(HaveKeyWithValue("containerId", inspect.OutputToString()))
+
+			for _, args := range [][]string{{"--stream", "stdin"}, {"--grep", "("}, {"--format", "yaml"}} {
+				results = podmanTest.Podman(append(append([]string{"logs"}, args...), cname))
+				results.WaitWithDefaultTimeout()
+				Expect(results).To(Exit(125))
+			}
+		})
+
 		It("podman logs partial log lines: "+log, func() {
 			skipIfJournaldInContainer()
 
@@ -524,6 +602,113 @@ var _ = Describe("Podman logs", func() {
 		Expect(string(out)).To(ContainSubstring(containerName))
 	})
 
+	It("podman logs with json-file rotation", func() {
+		logc := podmanTest.Podman([]string{"run", "--log-driver", "json-file", "--log-opt", "max-size=2k", "--log-opt", "max-file=3", "--log-opt", "compress=true", "-d", ALPINE, "sh", "-c", "i=1; while [ \"$i\" -le 200 ]; do echo \"line $i\"; i=$((i + 1)); done"})
+		logc.WaitWithDefaultTimeout()
+		Expect(logc).To(Exit(0))
+		cid := logc.OutputToString()
+
+		wait := podmanTest.Podman([]string{"wait", cid})
+		wait.WaitWithDefaultTimeout()
+		Expect(wait).To(Exit(0))
+
+		inspect := podmanTest.Podman([]string{"container", "inspect", "--format", "{{.HostConfig.LogConfig.Path}} {{index .HostConfig.LogConfig.Config \"max-file\"}}", cid})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect).To(Exit(0))
+		fields := strings.Fields(inspect.OutputToString())
+		Expect(fields).To(HaveLen(2))
+		Expect(fields[1]).To(Equal("3"))
+		logPath := fields[0]
+
+		content, err := os.ReadFile(logPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(HavePrefix(`{"log":"line `))
+		Expect(logPath + ".1.gz").To(BeARegularFile())
+		Expect(logPath + ".2.gz").To(BeARegularFile())
+		Expect(logPath + ".3.gz").ToNot(BeAnExistingFile())
+
+		// The logs span the rotated files, the oldest lines were dropped.
+		results := podmanTest.Podman([]string{"logs", cid})
+		results.WaitWithDefaultTimeout()
+		Expect(results).To(Exit(0))
+		lines := results.OutputToStringArray()
+		Expect(len(lines)).To(BeNumerically(">", 30))
+		Expect(len(lines)).To(BeNumerically("<", 200))
+		Expect(lines[len(lines)-1]).To(Equal("line 200"))
+
+		results = podmanTest.Podman([]string{"logs", "--tail", "20", cid})
+		results.WaitWithDefaultTimeout()
+		Expect(results).To(Exit(0))
+		Expect(results.OutputToStringArray()).To(Equal(lines[len(lines)-20:]))
+	})
+
+	It("podman run with invalid json-file log options", func() {
+		session := podmanTest.Podman([]string{"create", "--log-driver", "json-file", "--log-opt", "max-file=0", ALPINE})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(125))
+		Expect(session.ErrorToString()).To(ContainSubstring("invalid value \"0\" for log option max-file"))
+	})
+
+	It("podman logs with syslog log driver in dual mode", func() {
+		server, err := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		defer server.Close()
+
+		ctrName := "logsctr"
+		logc := podmanTest.Podman([]string{"run", "--name", ctrName, "--log-driver", "syslog", "--log-opt", "syslog-address=udp://" + server.LocalAddr().String(),
+			"--log-opt", "mode=dual", "--log-opt", "tag={{.Name}}", ALPINE, "sh", "-c", "echo hello; echo world >&2"})
+		logc.WaitWithDefaultTimeout()
+		Expect(logc).To(Exit(0))
+
+		buf := make([]byte, 1024)
+		Expect(server.SetReadDeadline(time.Now().Add(10 * time.Second))).To(Succeed())
+		var messages []string
+		for i := 0; i < 2; i++ {
+			n, _, err := server.ReadFrom(buf)
+			Expect(err).ToNot(HaveOccurred())
+			messages = append(messages, string(buf[:n]))
+		}
+		Expect(messages).To(ContainElement(MatchRegexp(`^<30>1 \S+ \S+ ` + ctrName + ` - stdout - hello$`)))
+		Expect(messages).To(ContainElement(MatchRegexp(`^<27>1 \S+ \S+ ` + ctrName + ` - stderr - world$`)))
+
+		results := podmanTest.Podman([]string{"logs", ctrName})
+		results.WaitWithDefaultTimeout()
+		Expect(results).To(Exit(0))
+		Expect(results.OutputToString()).To(Equal("hello"))
+		Expect(results.ErrorToString()).To(Equal("world"))
+	})
+
+	It("podman logs with fluentd log driver without dual mode errors", func() {
+		ctrName := "logsctr"
+		logc := podmanTest.Podman([]string{"run", "--name", ctrName, "--log-driver", "fluentd", "--log-opt", "fluentd-address=127.0.0.1:1", "--log-opt", "fluentd-max-retries=1", ALPINE, "true"})
+		logc.WaitWithDefaultTimeout()
+		Expect(logc).To(Exit(0))
+
+		inspect := podmanTest.Podman([]string{"inspect", "--format", "{{.HostConfig.LogConfig.Type}}", ctrName})
+		inspect.WaitWithDefaultTimeout()
+		Expect(inspect).To(Exit(0))
+		Expect(inspect.OutputToString()).To(Equal("fluentd"))
+
+		results := podmanTest.Podman([]string{"logs", ctrName})
+		results.WaitWithDefaultTimeout()
+		Expect(results).To(Exit(125))
+		Expect(results.ErrorToString()).To(ContainSubstring("cannot read logs without --log-opt mode=dual"))
+	})
+
+	It("podman run with invalid syslog and fluentd log options", func() {
+		for _, args := range [][]string{
+			{"--log-driver", "syslog", "--log-opt", "syslog-address=http://localhost"},
+			{"--log-driver", "syslog", "--log-opt", "syslog-facility=nope"},
+			{"--log-driver", "fluentd", "--log-opt", "fluentd-retry-wait=often"},
+			{"--log-driver", "fluentd", "--log-opt", "mode=blocking"},
+		} {
+			session := podmanTest.Podman(append(append([]string{"create"}, args...), ALPINE))
+			session.WaitWithDefaultTimeout()
+			Expect(session).Should(Exit(125))
+			Expect(session.ErrorToString()).To(ContainSubstring("invalid value"))
+		}
+	})
+
 	It("podman logs with log-driver=none errors", func() {
 		ctrName := "logsctr"
 		logc := podmanTest.Podman([]string{"run", "--name", ctrName, "-d", "--log-driver", "none", ALPINE, "top"})
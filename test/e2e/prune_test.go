@@ -191,6 +191,47 @@ var _ = Describe("Podman prune", func() {
 		Expect(images.OutputToStringArray()).To(HaveLen(len(CACHE_IMAGES)))
 	})
 
+	It("podman image prune -a with an image protected by auto-update", func() {
+		podmanTest.AddImageToRWStore(ALPINE)
+		podmanTest.AddImageToRWStore(BB)
+		image := "localhost/autoupdate:latest"
+		session := podmanTest.Podman([]string{"tag", ALPINE, image})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"run", "-d", "--name", "ctr", "--label", "io.containers.autoupdate=local", image, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		session = podmanTest.Podman([]string{"image", "inspect", "--format", "{{.ID}}", image})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		oldID := session.OutputToString()
+
+		session = podmanTest.Podman([]string{"commit", "-q", "ctr", image})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		session = podmanTest.Podman([]string{"auto-update", "--format", "{{.Updated}}"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		Expect(session.OutputToString()).To(Equal("true"))
+
+		// An unused image with more than one tag must not fail pruning.
+		session = podmanTest.Podman([]string{"tag", BB, "localhost/multi:1", "localhost/multi:2"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		prune := podmanTest.Podman([]string{"image", "prune", "-af"})
+		prune.WaitWithDefaultTimeout()
+		Expect(prune).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"image", "exists", oldID})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		session = podmanTest.Podman([]string{"image", "exists", "localhost/multi:1"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(1))
+	})
+
 	It("podman system image prune unused images", func() {
 		useCustomNetworkDir(podmanTest, tempdir)
 		podmanTest.AddImageToRWStore(ALPINE)
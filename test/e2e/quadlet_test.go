@@ -30,13 +30,24 @@ func loadQuadletTestcase(path string) *quadletTestcase {
 	base := filepath.Base(path)
 	ext := filepath.Ext(base)
 	service := base[:len(base)-len(ext)]
+	// Template units keep the instance part after the suffix, e.g. foo-volume@.service
+	instance := ""
+	if at := strings.IndexByte(service, '@'); at >= 0 {
+		service, instance = service[:at], service[at:]
+	}
 	switch ext {
 	case ".volume":
 		service += "-volume"
 	case ".network":
 		service += "-network"
+	case ".pod":
+		service += "-pod"
+	case ".image":
+		service += "-image"
+	case ".build":
+		service += "-build"
 	}
-	service += ".service"
+	service += instance + ".service"
 
 	checks := make([][]string, 0)
 
@@ -274,6 +285,14 @@ func (t *quadletTestcase) assertStartPodmanFinalArgsRegex(args []string, unit *p
 	return t.assertPodmanFinalArgsRegex(args, unit, "ExecStart")
 }
 
+func (t *quadletTestcase) assertStartPrePodmanArgs(args []string, unit *parser.UnitFile) bool {
+	return t.assertPodmanArgs(args, unit, "ExecStartPre")
+}
+
+func (t *quadletTestcase) assertStartPrePodmanFinalArgs(args []string, unit *parser.UnitFile) bool {
+	return t.assertPodmanFinalArgs(args, unit, "ExecStartPre")
+}
+
 func (t *quadletTestcase) assertStopPodmanArgs(args []string, unit *parser.UnitFile) bool {
 	return t.assertPodmanArgs(args, unit, "ExecStop")
 }
@@ -351,6 +370,10 @@ func (t *quadletTestcase) doAssert(check []string, unit *parser.UnitFile, sessio
 		ok = t.assertStartPodmanFinalArgs(args, unit)
 	case "assert-podman-final-args-regex":
 		ok = t.assertStartPodmanFinalArgsRegex(args, unit)
+	case "assert-podman-pre-args":
+		ok = t.assertStartPrePodmanArgs(args, unit)
+	case "assert-podman-pre-final-args":
+		ok = t.assertStartPrePodmanFinalArgs(args, unit)
 	case "assert-symlink":
 		ok = t.assertSymlink(args, unit)
 	case "assert-podman-stop-args":
@@ -511,13 +534,21 @@ var _ = Describe("quadlet system generator", func() {
 	})
 
 	DescribeTable("Running quadlet test case",
-		func(fileName string) {
+		func(fileName string, dependencyFiles ...string) {
 			testcase := loadQuadletTestcase(filepath.Join("quadlet", fileName))
 
 			// Write the tested file to the quadlet dir
 			err = os.WriteFile(filepath.Join(quadletDir, fileName), testcase.data, 0644)
 			Expect(err).ToNot(HaveOccurred())
 
+			// Also write any dependent files the tested file refers to
+			for _, dependencyFile := range dependencyFiles {
+				data, err := os.ReadFile(filepath.Join("quadlet", dependencyFile))
+				Expect(err).ToNot(HaveOccurred())
+				err = os.WriteFile(filepath.Join(quadletDir, dependencyFile), data, 0644)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
 			// Run quadlet to convert the file
 			session := podmanTest.Quadlet([]string{"--user", "-no-kmsg-log", generatedDir}, quadletDir)
 			session.WaitWithDefaultTimeout()
@@ -532,19 +563,27 @@ var _ = Describe("quadlet system generator", func() {
 			testcase.check(generatedDir, session)
 		},
 		Entry("Basic container", "basic.container"),
+		Entry("addhost.container", "addhost.container"),
 		Entry("annotation.container", "annotation.container"),
 		Entry("autoupdate.container", "autoupdate.container"),
 		Entry("basepodman.container", "basepodman.container"),
 		Entry("capabilities.container", "capabilities.container"),
 		Entry("capabilities2.container", "capabilities2.container"),
+		Entry("cpuquota.invalid.container", "cpuquota.invalid.container"),
+		Entry("cpuweight.invalid.container", "cpuweight.invalid.container"),
 		Entry("devices.container", "devices.container"),
 		Entry("disableselinux.container", "disableselinux.container"),
+		Entry("dns.container", "dns.container"),
+		Entry("entrypoint.container", "entrypoint.container"),
 		Entry("env-file.container", "env-file.container"),
 		Entry("env-host-false.container", "env-host-false.container"),
 		Entry("env-host.container", "env-host.container"),
 		Entry("env.container", "env.container"),
 		Entry("escapes.container", "escapes.container"),
 		Entry("exec.container", "exec.container"),
+		Entry("groupadd.container", "groupadd.container"),
+		Entry("health-grpc.container", "health-grpc.container"),
+		Entry("health-probe.container", "health-probe.container"),
 		Entry("health.container", "health.container"),
 		Entry("hostname.container", "hostname.container"),
 		Entry("image.container", "image.container"),
@@ -558,6 +597,7 @@ var _ = Describe("quadlet system generator", func() {
 		Entry("nestedselinux.container", "nestedselinux.container"),
 		Entry("network.container", "network.container"),
 		Entry("network.quadlet.container", "network.quadlet.container"),
+		Entry("name.quadlet.container", "name.quadlet.container", "name.volume", "name.network"),
 		Entry("noimage.container", "noimage.container"),
 		Entry("notify.container", "notify.container"),
 		Entry("oneshot.container", "oneshot.container"),
@@ -574,11 +614,13 @@ var _ = Describe("quadlet system generator", func() {
 		Entry("remap-keep-id.container", "remap-keep-id.container"),
 		Entry("remap-keep-id2.container", "remap-keep-id2.container"),
 		Entry("remap-manual.container", "remap-manual.container"),
+		Entry("resources.container", "resources.container"),
 		Entry("rootfs.container", "rootfs.container"),
 		Entry("seccomp.container", "seccomp.container"),
 		Entry("secrets.container", "secrets.container"),
 		Entry("selinux.container", "selinux.container"),
 		Entry("shortname.container", "shortname.container"),
+		Entry("stop.container", "stop.container"),
 		Entry("sysctl.container", "sysctl.container"),
 		Entry("timezone.container", "timezone.container"),
 		Entry("unmask.container", "unmask.container"),
@@ -592,6 +634,7 @@ var _ = Describe("quadlet system generator", func() {
 		Entry("device-copy.volume", "device-copy.volume"),
 		Entry("device.volume", "device.volume"),
 		Entry("podmanargs.volume", "podmanargs.volume"),
+		Entry("Volume - VolumeName", "name.volume"),
 
 		Entry("Basic kube", "basic.kube"),
 		Entry("Syslog Identifier", "syslog.identifier.kube"),
@@ -627,6 +670,61 @@ var _ = Describe("quadlet system generator", func() {
 		Entry("Network - Options", "options.network"),
 		Entry("Network - Multiple Options", "options.multiple.network"),
 		Entry("Network - PodmanArgs", "podmanargs.network"),
+		Entry("Network - NetworkName", "name.network"),
+
+		Entry("Pod - Basic", "basic.pod"),
+		Entry("Pod - Name", "name.pod"),
+		Entry("Pod - Network", "network.pod"),
+		Entry("Pod - Quadlet Network", "network.quadlet.pod", "basic.network"),
+		Entry("Pod - Publish ports", "ports.pod"),
+		Entry("Pod - Volume", "volume.pod"),
+		Entry("Pod - UserNS", "userns.pod"),
+		Entry("Pod - Infra image", "infraimage.pod"),
+		Entry("Pod - PodmanArgs", "podmanargs.pod"),
+		Entry("Pod - Unknown key", "unknownkey.pod"),
+		Entry("Pod - Container joins pod", "pod.container", "basic.pod"),
+		Entry("Pod - Pod orders its containers", "containers.pod", "pod-member.container"),
+		Entry("Pod - Container joins non quadlet pod", "pod.non-quadlet.container"),
+		Entry("Pod - Container with missing pod", "pod.not-found.container"),
+
+		Entry("Image - Basic", "basic.image"),
+		Entry("Image - No Image", "no-image.image"),
+		Entry("Image - Architecture", "arch.image"),
+		Entry("Image - Auth and certificates", "auth.image"),
+		Entry("Image - All tags", "all-tags.image"),
+		Entry("Image - TLS verify", "tls-verify.image"),
+		Entry("Image - Decryption key", "decryption-key.image"),
+		Entry("Image - PodmanArgs", "podmanargs.image"),
+		Entry("Image - Unknown key", "unknownkey.image"),
+		Entry("Image - Container with quadlet image", "image.quadlet.container", "basic.image"),
+		Entry("Image - Container with missing quadlet image", "image.not-found.container"),
+
+		Entry("Build - Basic", "basic.build"),
+		Entry("Build - No ImageTag", "no-imagetag.build"),
+		Entry("Build - Neither WorkingDirectory nor File", "no-context.build"),
+		Entry("Build - Relative File without WorkingDirectory", "relative-file.no-wd.build"),
+		Entry("Build - Multiple tags", "tags.build"),
+		Entry("Build - SetWorkingDirectory file", "setworkingdirectory-file.build"),
+		Entry("Build - SetWorkingDirectory unit", "setworkingdirectory-unit.build"),
+		Entry("Build - SetWorkingDirectory path", "setworkingdirectory-path.build"),
+		Entry("Build - SetWorkingDirectory URL", "setworkingdirectory-url.build"),
+		Entry("Build - Labels and Environment", "label-env.build"),
+		Entry("Build - Secrets", "secrets.build"),
+		Entry("Build - Volumes", "volume.build"),
+		Entry("Build - Network", "network.build"),
+		Entry("Build - Pull and Target", "pull-target.build"),
+		Entry("Build - GlobalArgs", "globalargs.build"),
+		Entry("Build - Container with quadlet build", "build.quadlet.container", "basic.build"),
+
+		Entry("Template - Container", "template@.container"),
+		Entry("Template - Container name", "template-name@.container"),
+		Entry("Template - Install DefaultInstance", "template-install@.container"),
+		Entry("Template - Install without DefaultInstance", "template-noinstance@.container"),
+		Entry("Template - Volume", "template@.volume"),
+		Entry("Template - Network", "template@.network"),
+		Entry("Template - Pod", "template@.pod"),
+		Entry("Template - Container with template volume", "volume-template@.container", "template@.volume"),
+		Entry("Template - Container in template pod", "pod-template@.container", "template@.pod"),
 	)
 
 })
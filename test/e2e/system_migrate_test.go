@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("podman system migrate", func() {
+
+	It("podman system migrate --migrate-db and --rollback-db", func() {
+		SkipIfRemote("system migrate not supported on podman --remote")
+		if podmanTest.DatabaseBackend != "boltdb" {
+			Skip("the database can only be migrated from boltdb")
+		}
+		configPath := filepath.Join(podmanTest.TempDir, "containers.conf")
+		os.Setenv("CONTAINERS_CONF", configPath)
+
+		session := podmanTest.Podman([]string{"volume", "create", "data"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"pod", "create", "--name", "migratepod"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"create", "--pod", "migratepod", "-v", "data:/data", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"ps", "-aq", "--sort", "names"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		ctrs := session.OutputToStringArray()
+		Expect(ctrs).To(HaveLen(2))
+
+		session = podmanTest.Podman([]string{"system", "migrate", "--migrate-db", "--dry-run"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		Expect(session.OutputToString()).To(HavePrefix("Would migrate 2 containers, 1 pods, 1 volumes, "))
+		Expect(session.OutputToString()).To(HaveSuffix(" from boltdb to sqlite"))
+		Expect(configPath).ToNot(BeAnExistingFile())
+
+		session = podmanTest.Podman([]string{"system", "migrate", "--migrate-db"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		Expect(session.OutputToString()).To(ContainSubstring("from boltdb to sqlite"))
+		conf, err := os.ReadFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(conf)).To(ContainSubstring(`database_backend = "sqlite"`))
+
+		podmanTest.DatabaseBackend = "sqlite"
+		session = podmanTest.Podman([]string{"ps", "-aq", "--sort", "names"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		Expect(session.OutputToStringArray()).To(Equal(ctrs))
+
+		session = podmanTest.Podman([]string{"volume", "rm", "data"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(2))
+		Expect(session.ErrorToString()).To(ContainSubstring("volume is being used"))
+
+		session = podmanTest.Podman([]string{"pod", "inspect", "--format", "{{.NumContainers}}", "migratepod"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		Expect(session.OutputToString()).To(Equal("2"))
+
+		// Containers created after the migration would be lost.
+		session = podmanTest.Podman([]string{"create", "--name", "aftermigration", ALPINE, "top"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"system", "migrate", "--rollback-db"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(125))
+		Expect(session.OutputToString()).To(ContainSubstring("the state changed since the migration"))
+
+		session = podmanTest.Podman([]string{"rm", "aftermigration"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+
+		session = podmanTest.Podman([]string{"system", "migrate", "--rollback-db"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		Expect(session.OutputToString()).To(ContainSubstring("Rolled back the database backend from sqlite to boltdb"))
+		conf, err = os.ReadFile(configPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(conf)).To(ContainSubstring(`database_backend = "boltdb"`))
+
+		podmanTest.DatabaseBackend = "boltdb"
+		session = podmanTest.Podman([]string{"ps", "-aq", "--sort", "names"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(0))
+		Expect(session.OutputToStringArray()).To(Equal(ctrs))
+	})
+
+	It("podman system migrate with invalid database options", func() {
+		SkipIfRemote("system migrate not supported on podman --remote")
+		session := podmanTest.Podman([]string{"system", "migrate", "--dry-run"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(125))
+		Expect(session.OutputToString()).To(Equal("--dry-run can only be used with --migrate-db or --rollback-db"))
+
+		session = podmanTest.Podman([]string{"system", "migrate", "--migrate-db", "--rollback-db"})
+		session.WaitWithDefaultTimeout()
+		Expect(session).Should(Exit(125))
+		Expect(session.OutputToString()).To(Equal("--migrate-db and --rollback-db cannot be used together"))
+	})
+})
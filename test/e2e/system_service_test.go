@@ -38,6 +38,35 @@ var _ = Describe("podman system service", func() {
 		})
 	})
 
+	Describe("healthcheck scheduler", func() {
+		It("runs healthchecks without systemd timers", func() {
+			SkipIfRemote("service subcommand not supported remotely")
+
+			address := url.URL{
+				Scheme: "tcp",
+				Host:   net.JoinHostPort("localhost", randomPort()),
+			}
+			// The e2e tests set DISABLE_HC_SYSTEMD, so the service
+			// runs the healthchecks itself.
+			session := podmanTest.Podman([]string{
+				"system", "service", "--time=0", address.String(),
+			})
+			defer session.Kill()
+
+			WaitForService(address)
+
+			run := podmanTest.Podman([]string{"run", "-d", "--name", "hc", "--health-cmd", "false", "--health-interval", "1s", "--health-retries", "2", "--health-on-failure", "kill", ALPINE, "top"})
+			run.WaitWithDefaultTimeout()
+			Expect(run).Should(Exit(0))
+
+			Eventually(func() string {
+				inspect := podmanTest.Podman([]string{"inspect", "--format", "{{.State.Status}}", "hc"})
+				inspect.WaitWithDefaultTimeout()
+				return inspect.OutputToString()
+			}, timeout, 1).Should(Equal("exited"))
+		})
+	})
+
 	Describe("verify pprof endpoints", func() {
 		// Depends on pkg/api/server/server.go:255
 		const magicComment = "pprof service listening on"
@@ -88,3 +88,33 @@ function teardown() {
     run_podman --url $URL rm $cname
     systemctl stop $SERVICE_NAME
 }
+
+@test "podman-system-service json-file log survives service stop" {
+    skip_if_remote "podman system service unavailable over remote"
+    local runtime=$(podman_runtime)
+    if [[ "$runtime" != "crun" ]]; then
+        skip "survival code only implemented in crun; you're using $runtime"
+    fi
+
+    port=$(random_free_port)
+    URL=tcp://127.0.0.1:$port
+
+    systemd-run --unit=$SERVICE_NAME $PODMAN system service $URL --time=0
+    wait_for_port 127.0.0.1 $port
+
+    # The log forwarder of the container is started by the service and
+    # must not be killed along with it.
+    cname=c-$(random_string)
+    run_podman --url $URL run -d --name $cname --log-driver json-file $IMAGE \
+               sh -c 'while :; do echo tick; sleep 0.2; done'
+
+    systemctl stop $SERVICE_NAME
+
+    run_podman logs $cname
+    local count=${#lines[@]}
+    sleep 2
+    run_podman logs $cname
+    assert "${#lines[@]}" -gt "$count" "log is written after service stop"
+
+    run_podman rm -f -t 0 $cname
+}
@@ -716,4 +716,69 @@ EOF
    run_podman rmi $(pause_image)
 }
 
+@test "quadlet - podman quadlet commands" {
+    local src_dir=$PODMAN_TMPDIR/quadlet_src_$(random_string)
+    local unit_dir=$(mktemp -d --tmpdir=$PODMAN_TMPDIR quadlet.XXXXXX)
+    mkdir -p $src_dir/cmd.container.d
+    cat > $src_dir/cmd.container <<EOF
+[Container]
+Image=$IMAGE
+Exec=top
+EOF
+    cat > $src_dir/cmd.container.d/10-label.conf <<EOF
+[Container]
+Label=dropin=yes
+EOF
+    cat > $src_dir/bad.container <<EOF
+[Container]
+Image=$IMAGE
+Imag=$IMAGE
+EOF
+
+    # The commands use the same directories as the generator
+    export QUADLET_UNIT_DIRS=$unit_dir
+
+    run_podman quadlet install --reload-systemd=false $src_dir
+    assert "$output" = "bad.container
+cmd.container" "podman quadlet install lists installed units"
+    test -e $unit_dir/cmd.container.d/10-label.conf || die "drop-in directory was not installed"
+
+    run_podman 125 quadlet install --reload-systemd=false $src_dir/cmd.container
+    assert "$output" =~ "already exists, use --replace to replace it"
+    run_podman quadlet install --reload-systemd=false --replace $src_dir/cmd.container
+
+    run_podman quadlet list --quiet
+    assert "$output" = "bad.container
+cmd.container" "podman quadlet list --quiet"
+
+    run_podman quadlet list --format "{{.Name}} {{.ServiceName}} {{.Path}}"
+    assert "$output" =~ "bad.container  $unit_dir/bad.container" "invalid unit has no service"
+    assert "$output" =~ "cmd.container cmd.service $unit_dir/cmd.container"
+
+    run_podman quadlet print cmd.container
+    assert "$output" =~ "ExecStart=.*podman run --name=systemd-%N .* --label dropin=yes .*$IMAGE top"
+
+    run_podman 125 quadlet validate
+    assert "$output" =~ "bad.container: unsupported key 'Imag' in group 'Container' in $unit_dir/bad.container:3"
+
+    run_podman 125 quadlet validate $src_dir/bad.container
+    assert "$output" =~ "in $src_dir/bad.container:3"
+    run_podman quadlet validate $src_dir/cmd.container
+    assert "$output" = "" "valid unit reports no errors"
+
+    run_podman quadlet rm --reload-systemd=false bad.container
+    assert "$output" = "bad.container"
+    run_podman 125 quadlet rm --reload-systemd=false bad.container
+    assert "$output" =~ "no such Quadlet unit \"bad.container\""
+    run_podman quadlet rm --reload-systemd=false --ignore bad.container
+
+    run_podman quadlet validate
+    assert "$output" = "" "no errors after removing the invalid unit"
+
+    run_podman quadlet rm --reload-systemd=false cmd.container
+    test -e $unit_dir/cmd.container.d && die "drop-in directory was not removed"
+    run_podman quadlet list --quiet
+    assert "$output" = "" "no units left"
+}
+
 # vim: filetype=sh
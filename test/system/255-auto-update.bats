@@ -254,6 +254,41 @@ function _confirm_update() {
     _confirm_update $cname $ori_image
 }
 
+@test "podman auto-update - history and manual rollback" {
+    generate_service localtest local
+    _wait_service_ready container-$cname.service
+
+    image=quay.io/libpod/localtest:latest
+    run_podman commit --change CMD=/bin/bash $cname $image
+    run_podman image inspect --format "{{.ID}}" $image
+    new_iid="$output"
+
+    run_podman auto-update --rollback=false --format "{{.Unit}},{{.Image}},{{.Updated}},{{.Policy}}"
+    is "$output" ".*container-$cname.service,$image,true,local.*" "Image is updated."
+    _confirm_update $cname $ori_image
+
+    run_podman auto-update --history --format "{{.ContainerName}},{{.Unit}},{{.Image}},{{.Result}}"
+    is "$output" ".*$cname,container-$cname.service,$image,true.*" "update is listed in the history"
+    run_podman auto-update --history --format json
+    is "$output" ".*\"OldImage\": \"${ori_image:0:12}\".*" "history lists the old image"
+
+    # The old image is protected from pruning.
+    run_podman image prune -f
+    run_podman image exists $ori_image
+
+    run_podman auto-update rollback --format "{{.Unit}},{{.Image}},{{.Updated}}" container-$cname.service
+    is "$output" ".*container-$cname.service,$image,rolled back.*" "update has been rolled back"
+    _confirm_update $cname $new_iid
+    run_podman container inspect --format "{{.Image}}" $cname
+    is "$output" "$ori_image" "container runs the previous image"
+
+    run_podman 125 auto-update rollback $cname
+    is "$output" "Error: rolling back container $cname: latest update has status \"rolled back\"" "rollback can only be done once"
+
+    run_podman 125 auto-update rollback container-nonexistent.service
+    is "$output" "Error: no auto-update found for container or unit \"container-nonexistent.service\""
+}
+
 # This test can fail in dev. environment because of SELinux.
 # quick fix: chcon -t container_runtime_exec_t ./bin/podman
 @test "podman auto-update - label io.containers.autoupdate=local with rollback" {
@@ -309,6 +344,83 @@ EOF
     _confirm_update $cname $newID
 }
 
+@test "podman auto-update - rollback of unhealthy containers" {
+    generate_service alpine local "top -d 120" "--health-cmd=/bin/true --health-interval=1s --health-retries=1"
+    _wait_service_ready container-$cname.service
+
+    run_podman container inspect --format "{{.Image}}" $cname
+    oldID="$output"
+
+    # The updated image fails the healthcheck.
+    image=quay.io/libpod/alpine:latest
+    run_podman exec $cname rm /bin/true
+    run_podman commit $cname $image
+
+    run_podman auto-update --health-timeout=30s --format "{{.Unit}},{{.Image}},{{.Updated}},{{.Policy}}"
+    is "$output" ".*container-$cname.service,$image,rolled-back \(unhealthy\),local.*" "Rolled back unhealthy container"
+
+    run_podman container inspect --format "{{.Image}}" $cname
+    is "$output" "$oldID" "container rolled back to previous image"
+}
+
+@test "podman auto-update - containers and kube pods not running in systemd" {
+    local cname=c_local_$(random_string)
+    local image=quay.io/libpod/localtest:latest
+    run_podman tag $IMAGE $image
+
+    run_podman volume create vol-$cname
+    run_podman run -d --name $cname --label io.containers.autoupdate=local \
+               -v vol-$cname:/data $image top -d 120
+    run_podman inspect --format "{{.ID}}" $cname
+    local old_cid="$output"
+
+    local podname=p-$(random_string)
+    local kube_yaml=$PODMAN_TMPDIR/$podname.yaml
+    cat >$kube_yaml <<EOF
+apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    io.containers.autoupdate: local
+  name: $podname
+spec:
+  containers:
+  - command:
+    - top
+    image: $image
+    imagePullPolicy: Never
+    name: ctr
+EOF
+    # The options of kube play must be preserved when replaying the YAML
+    run_podman kube play --annotation replay=$podname $kube_yaml
+
+    # Update the image
+    run_podman commit --change CMD=/bin/bash $cname $image
+    run_podman image inspect --format "{{.ID}}" $image
+    local new_iid="$output"
+
+    run_podman auto-update --format "{{.Unit}},{{.ContainerName}},{{.Updated}},{{.Policy}}"
+    is "$output" ".*,$cname,true,local.*" "container has been updated"
+    is "$output" ".*,$podname-ctr,true,local.*" "kube pod has been updated"
+
+    run_podman container inspect --format "{{.ID}} {{.Image}} {{.State.Status}}" $cname
+    assert "$output" !~ "$old_cid" "container has been recreated"
+    is "$output" ".* $new_iid running" "container runs the new image"
+    run_podman container inspect --format "{{range .Mounts}}{{.Name}}{{end}}" $cname
+    is "$output" "vol-$cname" "volume is preserved"
+    run_podman ps -a --no-trunc --format "{{.ID}}"
+    assert "$output" !~ "$old_cid" "old container has been removed"
+
+    run_podman container inspect --format "{{.Image}} {{.State.Status}}" $podname-ctr
+    is "$output" "$new_iid running" "kube pod runs the new image"
+    run_podman container inspect --format '{{index .Config.Annotations "replay"}}' $podname-ctr
+    is "$output" "$podname" "kube play options are preserved"
+
+    run_podman kube down $kube_yaml
+    run_podman rm -f -t0 $cname
+    run_podman volume rm vol-$cname
+}
+
 @test "podman auto-update with multiple services" {
     # Preserve original image ID, to confirm that it changes (or not)
     run_podman inspect --format "{{.Id}}" $IMAGE
@@ -47,7 +47,7 @@ func RunUnderSystemdScope(pid int, slice string, unitName string) error {
 		// On errors check if the cgroup already exists, if it does move the process there
 		if props, err := conn.GetUnitTypePropertiesContext(context.Background(), unitName, "Scope"); err == nil {
 			if cgroup, ok := props["ControlGroup"].(string); ok && cgroup != "" {
-				if err := moveUnderCgroup(cgroup, "", []uint32{uint32(pid)}); err == nil {
+				if err := moveUnderCgroup("/proc/self/cgroup", cgroup, "", []uint32{uint32(pid)}); err == nil {
 					return nil
 				}
 				// On errors return the original error message we got from StartTransientUnit.
@@ -107,14 +107,19 @@ func GetCgroupProcess(pid int) (string, error) {
 
 // MoveUnderCgroupSubtree moves the PID under a cgroup subtree.
 func MoveUnderCgroupSubtree(subtree string) error {
-	return moveUnderCgroup("", subtree, nil)
+	return moveUnderCgroup("/proc/self/cgroup", "", subtree, nil)
+}
+
+// MoveUnderCgroupOfProcess moves the processes to the cgroups of the process
+// with the specified PID.
+func MoveUnderCgroupOfProcess(pid int, processes []uint32) error {
+	return moveUnderCgroup(fmt.Sprintf("/proc/%d/cgroup", pid), "", "", processes)
 }
 
 // moveUnderCgroup moves a group of processes to a new cgroup.
-// If cgroup is the empty string, then the current calling process cgroup is used.
+// If cgroup is the empty string, then the cgroup of the process of procFile is used.
 // If processes is empty, then the processes from the current cgroup are moved.
-func moveUnderCgroup(cgroup, subtree string, processes []uint32) error {
-	procFile := "/proc/self/cgroup"
+func moveUnderCgroup(procFile, cgroup, subtree string, processes []uint32) error {
 	f, err := os.Open(procFile)
 	if err != nil {
 		return err
@@ -13,6 +13,10 @@ func MoveUnderCgroupSubtree(subtree string) error {
 	return errors.New("not implemented for windows")
 }
 
+func MoveUnderCgroupOfProcess(pid int, processes []uint32) error {
+	return errors.New("not implemented for windows")
+}
+
 func GetOwnCgroup() (string, error) {
 	return "", errors.New("not implemented for windows")
 }